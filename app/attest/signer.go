@@ -0,0 +1,119 @@
+// Package attest menandatangani dan memverifikasi atestasi prestasi dengan kunci Ed25519 milik server.
+package attest
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var ErrBadSignature = errors.New("invalid attestation signature")
+
+// Signer memegang kunci privat Ed25519. KeyID adalah 16 hex pertama SHA-256 kunci publik,
+// dicantumkan di setiap atestasi supaya rotasi kunci bisa dikenali.
+type Signer struct {
+	KeyID string
+	priv  ed25519.PrivateKey
+}
+
+func NewSigner(seed []byte) (*Signer, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("seed Ed25519 harus %d byte, bukan %d", ed25519.SeedSize, len(seed))
+	}
+	priv := ed25519.NewKeyFromSeed(seed)
+	return &Signer{KeyID: KeyIDOf(priv.Public().(ed25519.PublicKey)), priv: priv}, nil
+}
+
+// ErrNoKey: file kunci belum ada. Kunci harus dibuat eksplisit lewat Generate supaya server tidak
+// diam-diam menandatangani dengan kunci baru yang tidak pernah dibackup.
+var ErrNoKey = errors.New("attestation key file not found")
+
+// Load membaca seed (base64) dari path; ErrNoKey jika file belum ada.
+func Load(path string) (*Signer, error) {
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNoKey, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	seed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(raw)))
+	if err != nil {
+		return nil, fmt.Errorf("%s bukan base64: %w", path, err)
+	}
+	return NewSigner(seed)
+}
+
+// Generate membuat seed baru dan menyimpannya ke path dengan permission 0600. File yang sudah ada
+// tidak pernah ditimpa; rotasi dilakukan dengan path baru.
+func Generate(path string) (*Signer, error) {
+	seed := make([]byte, ed25519.SeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.WriteString(base64.StdEncoding.EncodeToString(seed) + "\n"); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	return NewSigner(seed)
+}
+
+// KeyIDOf menghitung key id kunci publik pub.
+func KeyIDOf(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:8])
+}
+
+func (s *Signer) PublicKey() ed25519.PublicKey {
+	return s.priv.Public().(ed25519.PublicKey)
+}
+
+func (s *Signer) Sign(payload []byte) []byte {
+	return ed25519.Sign(s.priv, payload)
+}
+
+func (s *Signer) Verify(payload, sig []byte) bool {
+	return ed25519.Verify(s.PublicKey(), payload, sig)
+}
+
+// Verify memeriksa tanda tangan dengan kunci publik pub, mis. kunci lama yang sudah dirotasi.
+func Verify(pub ed25519.PublicKey, payload, sig []byte) bool {
+	return len(pub) == ed25519.PublicKeySize && ed25519.Verify(pub, payload, sig)
+}
+
+// EncodeToken membentuk payload QR: base64url(payload) "." base64url(signature).
+func EncodeToken(payload, sig []byte) string {
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+// DecodeToken memecah payload QR menjadi payload dan signature.
+func DecodeToken(token string) (payload, sig []byte, err error) {
+	p, s, ok := strings.Cut(strings.TrimSpace(token), ".")
+	if !ok {
+		return nil, nil, ErrBadSignature
+	}
+	if payload, err = base64.RawURLEncoding.DecodeString(p); err != nil {
+		return nil, nil, ErrBadSignature
+	}
+	if sig, err = base64.RawURLEncoding.DecodeString(s); err != nil || len(sig) != ed25519.SignatureSize {
+		return nil, nil, ErrBadSignature
+	}
+	return payload, sig, nil
+}
@@ -0,0 +1,159 @@
+package badge
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// Image adalah isi visual satu badge.
+type Image struct {
+	Title  string
+	Label  string // mis. tipe prestasi
+	Issuer string
+	Color  color.RGBA
+}
+
+const (
+	imageSize = 400
+	// Gambar digambar di kanvas setengah ukuran lalu diperbesar supaya font bitmap 7x13 terbaca.
+	canvasSize = imageSize / 2
+)
+
+// wrap memecah teks menjadi baris maksimal width karakter, paling banyak maxLines baris.
+func wrap(text string, width, maxLines int) []string {
+	lines := []string{}
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if len(word) > width {
+			word = word[:width-1] + "~"
+		}
+		if line != "" && len(line)+1+len(word) > width {
+			lines = append(lines, line)
+			line = word
+			continue
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+		last := lines[maxLines-1]
+		if len(last) > width-3 {
+			last = last[:width-3]
+		}
+		lines[maxLines-1] = last + "..."
+	}
+	return lines
+}
+
+// RenderPNG menggambar badge bulat 400x400 dengan judul, label, dan nama penerbit.
+func RenderPNG(img Image) ([]byte, error) {
+	canvas := image.NewRGBA(image.Rect(0, 0, canvasSize, canvasSize))
+	c := float64(canvasSize) / 2
+	for y := 0; y < canvasSize; y++ {
+		for x := 0; x < canvasSize; x++ {
+			dx, dy := float64(x)-c+0.5, float64(y)-c+0.5
+			switch d := dx*dx + dy*dy; {
+			case d <= (c-14)*(c-14):
+				canvas.SetRGBA(x, y, color.RGBA{255, 255, 255, 255})
+			case d <= (c-1)*(c-1):
+				canvas.SetRGBA(x, y, img.Color)
+			}
+		}
+	}
+
+	face := basicfont.Face7x13
+	drawer := &font.Drawer{Dst: canvas, Face: face}
+	center := func(y int, text string, col color.Color) {
+		drawer.Src = image.NewUniform(col)
+		width := drawer.MeasureString(text).Ceil()
+		drawer.Dot = fixed.P((canvasSize-width)/2, y)
+		drawer.DrawString(text)
+	}
+
+	dark := color.RGBA{33, 37, 41, 255}
+	title := wrap(asciiOnly(img.Title), 20, 4)
+	y := canvasSize/2 - len(title)*14/2 + 6
+	for _, line := range title {
+		center(y, line, dark)
+		y += 14
+	}
+	center(48, strings.ToUpper(asciiOnly(img.Label)), img.Color)
+	if issuer := wrap(asciiOnly(img.Issuer), 22, 1); len(issuer) > 0 {
+		center(canvasSize-38, issuer[0], color.RGBA{108, 117, 125, 255})
+	}
+
+	out := image.NewRGBA(image.Rect(0, 0, imageSize, imageSize))
+	draw.CatmullRom.Scale(out, out.Bounds(), canvas, canvas.Bounds(), draw.Src, nil)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, out); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderSVG menggambar badge yang sama dalam format SVG.
+func RenderSVG(img Image) []byte {
+	hex := fmt.Sprintf("#%02x%02x%02x", img.Color.R, img.Color.G, img.Color.B)
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="%[1]d" viewBox="0 0 %[1]d %[1]d">`, imageSize)
+	fmt.Fprintf(&b, `<circle cx="200" cy="200" r="198" fill="%s"/><circle cx="200" cy="200" r="172" fill="#ffffff"/>`, hex)
+	fmt.Fprintf(&b, `<text x="200" y="100" text-anchor="middle" font-family="Helvetica, Arial, sans-serif" font-size="18" font-weight="bold" fill="%s">%s</text>`,
+		hex, xmlEscape(strings.ToUpper(img.Label)))
+
+	title := wrap(img.Title, 24, 4)
+	y := 200 - len(title)*28/2 + 20
+	for _, line := range title {
+		fmt.Fprintf(&b, `<text x="200" y="%d" text-anchor="middle" font-family="Helvetica, Arial, sans-serif" font-size="22" fill="#212529">%s</text>`, y, xmlEscape(line))
+		y += 28
+	}
+	if issuer := wrap(img.Issuer, 30, 1); len(issuer) > 0 {
+		fmt.Fprintf(&b, `<text x="200" y="322" text-anchor="middle" font-family="Helvetica, Arial, sans-serif" font-size="14" fill="#6c757d">%s</text>`, xmlEscape(issuer[0]))
+	}
+	b.WriteString(`</svg>`)
+	return []byte(b.String())
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '&':
+			b.WriteString("&amp;")
+		case '<':
+			b.WriteString("&lt;")
+		case '>':
+			b.WriteString("&gt;")
+		case '"':
+			b.WriteString("&quot;")
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// asciiOnly mengganti karakter di luar ASCII karena font bitmap hanya memuat ASCII.
+func asciiOnly(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r > 126 {
+			return '?'
+		}
+		return r
+	}, s)
+}
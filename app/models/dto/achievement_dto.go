@@ -10,6 +10,10 @@ type AchievementResponse struct {
 	Status        string              `json:"status"`
 	RejectionNote *string             `json:"rejection_note,omitempty"`
 	Content       mongodb.Achievement `json:"content"`
+	// Members hanya diisi pada detail prestasi
+	Members []postgres.AchievementMember `json:"members,omitempty"`
+	// Duplicates: kemungkinan duplikat, hanya untuk dosen/admin
+	Duplicates []postgres.DuplicateFlag `json:"duplicates,omitempty"`
 }
 
 func ToAchievementResponse(ref postgres.AchievementReference, data mongodb.Achievement) AchievementResponse {
@@ -19,4 +23,39 @@ func ToAchievementResponse(ref postgres.AchievementReference, data mongodb.Achie
 		RejectionNote: ref.RejectionNote,
 		Content:       data,
 	}
-}
\ No newline at end of file
+}
+
+// MissingContent menandai referensi Postgres yang dokumen Mongo-nya tidak ditemukan.
+type MissingContent struct {
+	RefID              string `json:"ref_id"`
+	MongoAchievementID string `json:"mongo_achievement_id"`
+}
+
+// ToAchievementResponses menggabungkan refs dengan konten hasil batch load, mengikuti urutan refs.
+// Referensi tanpa konten dikembalikan terpisah di missing, bukan dibuang diam-diam.
+func ToAchievementResponses(refs []postgres.AchievementReference, contents map[string]mongodb.Achievement) ([]AchievementResponse, []MissingContent) {
+	results := []AchievementResponse{}
+	missing := []MissingContent{}
+	for _, ref := range refs {
+		content, ok := contents[ref.MongoAchievementID]
+		if !ok {
+			missing = append(missing, MissingContent{RefID: ref.ID, MongoAchievementID: ref.MongoAchievementID})
+			continue
+		}
+		results = append(results, ToAchievementResponse(ref, content))
+	}
+	return results, missing
+}
+
+type PageMeta struct {
+	Page       int   `json:"page"`
+	Limit      int   `json:"limit"`
+	Total      int64 `json:"total"`
+	TotalPages int   `json:"total_pages"`
+}
+
+type AchievementListResponse struct {
+	Data    []AchievementResponse `json:"data"`
+	Missing []MissingContent      `json:"missing"`
+	Meta    PageMeta              `json:"meta"`
+}
@@ -0,0 +1,97 @@
+package dto
+
+import (
+	"sort"
+	"time"
+
+	"pelaporan_prestasi/app/models/mongo"
+	"pelaporan_prestasi/app/models/postgres"
+)
+
+const (
+	TimelineStatus   = "status"
+	TimelineRevision = "revision"
+	TimelineComment  = "comment"
+)
+
+// TimelineEntry adalah satu kejadian di timeline prestasi: perubahan status (Postgres),
+// revisi konten (Mongo) atau komentar review (Postgres).
+type TimelineEntry struct {
+	Type      string    `json:"type"`
+	ChangedBy string    `json:"changed_by"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// type = status
+	ID             string `json:"id,omitempty"`
+	PreviousStatus string `json:"previous_status,omitempty"`
+	NewStatus      string `json:"new_status,omitempty"`
+	Remarks        string `json:"remarks,omitempty"`
+
+	// type = revision
+	Revision      int      `json:"revision,omitempty"`
+	ChangedFields []string `json:"changed_fields,omitempty"`
+
+	// type = comment
+	CommentID          string  `json:"comment_id,omitempty"`
+	ParentID           *string `json:"parent_id,omitempty"`
+	Body               string  `json:"body,omitempty"`
+	AnchorField        *string `json:"anchor_field,omitempty"`
+	AnchorAttachmentID *string `json:"anchor_attachment_id,omitempty"`
+}
+
+func ToStatusTimeline(history []postgres.AchievementHistory) []TimelineEntry {
+	entries := make([]TimelineEntry, 0, len(history))
+	for _, h := range history {
+		entries = append(entries, TimelineEntry{
+			Type:           TimelineStatus,
+			ChangedBy:      h.ChangedBy,
+			CreatedAt:      h.CreatedAt,
+			ID:             h.ID,
+			PreviousStatus: h.PreviousStatus,
+			NewStatus:      h.NewStatus,
+			Remarks:        h.Remarks,
+		})
+	}
+	return entries
+}
+
+func ToRevisionTimeline(revs []mongodb.AchievementRevision) []TimelineEntry {
+	entries := make([]TimelineEntry, 0, len(revs))
+	for _, rev := range revs {
+		entries = append(entries, TimelineEntry{
+			Type:          TimelineRevision,
+			ChangedBy:     rev.ChangedBy,
+			CreatedAt:     rev.CreatedAt,
+			Revision:      rev.Revision,
+			ChangedFields: rev.ChangedFields,
+		})
+	}
+	return entries
+}
+
+func ToCommentTimeline(comments []postgres.AchievementComment) []TimelineEntry {
+	entries := make([]TimelineEntry, 0, len(comments))
+	for _, cm := range comments {
+		entries = append(entries, TimelineEntry{
+			Type:               TimelineComment,
+			ChangedBy:          cm.AuthorID,
+			CreatedAt:          cm.CreatedAt,
+			CommentID:          cm.ID,
+			ParentID:           cm.ParentID,
+			Body:               cm.Body,
+			AnchorField:        cm.AnchorField,
+			AnchorAttachmentID: cm.AnchorAttachmentID,
+		})
+	}
+	return entries
+}
+
+// MergeTimeline menggabungkan beberapa sumber timeline, terbaru dulu.
+func MergeTimeline(parts ...[]TimelineEntry) []TimelineEntry {
+	timeline := []TimelineEntry{}
+	for _, part := range parts {
+		timeline = append(timeline, part...)
+	}
+	sort.SliceStable(timeline, func(i, j int) bool { return timeline[i].CreatedAt.After(timeline[j].CreatedAt) })
+	return timeline
+}
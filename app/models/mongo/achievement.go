@@ -6,14 +6,49 @@ import (
 )
 
 type Attachment struct {
-	FileName   string    `json:"fileName" bson:"fileName"`
-	FileURL    string    `json:"fileUrl" bson:"fileUrl"`
-	FileType   string    `json:"fileType" bson:"fileType"`
-	UploadedAt time.Time `json:"uploadedAt" bson:"uploadedAt"`
+	ID         string             `json:"id" bson:"id,omitempty"`
+	FileName   string             `json:"fileName" bson:"fileName"`
+	FileURL    string             `json:"fileUrl" bson:"fileUrl"`
+	StorageKey string             `json:"-" bson:"storageKey,omitempty"`
+	FileType   string             `json:"fileType" bson:"fileType"`
+	Size       int64              `json:"size,omitempty" bson:"size,omitempty"`
+	SHA256     string             `json:"sha256,omitempty" bson:"sha256,omitempty"`
+	UploadedAt time.Time          `json:"uploadedAt" bson:"uploadedAt"`
+	Preview    *AttachmentPreview `json:"preview,omitempty" bson:"preview,omitempty"`
+}
+
+const (
+	PreviewPending     = "pending"
+	PreviewReady       = "ready"
+	PreviewFailed      = "failed"
+	PreviewUnsupported = "unsupported"
+)
+
+// AttachmentPreview adalah thumbnail gambar / preview halaman pertama PDF yang dibuat di background.
+type AttachmentPreview struct {
+	Status       string    `json:"status" bson:"status"`
+	ThumbnailKey string    `json:"-" bson:"thumbnailKey,omitempty"`
+	ThumbnailURL string    `json:"thumbnailUrl,omitempty" bson:"-"`
+	Width        int       `json:"width,omitempty" bson:"width,omitempty"`
+	Height       int       `json:"height,omitempty" bson:"height,omitempty"`
+	PageCount    int       `json:"pageCount,omitempty" bson:"pageCount,omitempty"`
+	Error        string    `json:"error,omitempty" bson:"error,omitempty"`
+	GeneratedAt  time.Time `json:"generatedAt,omitempty" bson:"generatedAt,omitempty"`
 }
 
 type AchievementDetails map[string]interface{}
 
+// PointsBreakdown mencatat bagaimana poin dihitung dari rulebook saat verifikasi.
+type PointsBreakdown struct {
+	RuleID          string    `json:"ruleId,omitempty" bson:"ruleId,omitempty"`
+	AchievementType string    `json:"achievementType" bson:"achievementType"`
+	Level           string    `json:"level,omitempty" bson:"level,omitempty"`
+	Rank            string    `json:"rank,omitempty" bson:"rank,omitempty"`
+	Points          int       `json:"points" bson:"points"`
+	Note            string    `json:"note" bson:"note"`
+	CalculatedAt    time.Time `json:"calculatedAt" bson:"calculatedAt"`
+}
+
 type Achievement struct {
 	ID              primitive.ObjectID `json:"id" bson:"_id,omitempty"`
 	StudentID       string             `json:"studentId" bson:"studentId"`
@@ -23,8 +58,23 @@ type Achievement struct {
 	Details         AchievementDetails `json:"details" bson:"details"`
 	Attachments     []Attachment       `json:"attachments" bson:"attachments"`
 	Tags            []string           `json:"tags" bson:"tags"`
+	// EventID menautkan prestasi ke katalog event (tabel events di Postgres); kosong = tidak tertaut
+	EventID         string             `json:"eventId,omitempty" bson:"eventId,omitempty"`
 	Points          int                `json:"points" bson:"points"`
+	PointsBreakdown *PointsBreakdown   `json:"pointsBreakdown,omitempty" bson:"pointsBreakdown,omitempty"`
 	CreatedAt       time.Time          `json:"createdAt" bson:"createdAt"`
 	UpdatedAt       time.Time          `json:"updatedAt" bson:"updatedAt"`
 	Deleted         bool               `json:"deleted" bson:"deleted,omitempty"`
+	DeletedAt       *time.Time         `json:"deletedAt,omitempty" bson:"deletedAt,omitempty"`
+	DeletedBy       string             `json:"deletedBy,omitempty" bson:"deletedBy,omitempty"`
+	// Version sama dengan nomor revisi konten terakhir; dokumen lama tanpa field ini dianggap versi 1.
+	Version int `json:"version" bson:"version,omitempty"`
+}
+
+// CurrentVersion mengembalikan Version dengan dokumen lama (tanpa version) dianggap versi 1.
+func (a Achievement) CurrentVersion() int {
+	if a.Version < 1 {
+		return 1
+	}
+	return a.Version
 }
\ No newline at end of file
@@ -0,0 +1,24 @@
+package mongodb
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AchievementRevision adalah snapshot konten prestasi yang tidak pernah diubah setelah ditulis.
+// Revisi 1 adalah konten saat dibuat; setiap edit menambah revisi baru.
+type AchievementRevision struct {
+	ID              primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	AchievementID   string             `json:"achievementId" bson:"achievementId"` // hex _id dokumen achievement
+	Revision        int                `json:"revision" bson:"revision"`
+	Title           string             `json:"title" bson:"title"`
+	Description     string             `json:"description" bson:"description"`
+	AchievementType string             `json:"achievementType" bson:"achievementType"`
+	Tags            []string           `json:"tags" bson:"tags"`
+	Details         AchievementDetails `json:"details" bson:"details"`
+	EventID         string             `json:"eventId,omitempty" bson:"eventId,omitempty"`
+	ChangedFields   []string           `json:"changedFields" bson:"changedFields"`
+	ChangedBy       string             `json:"changedBy" bson:"changedBy"`
+	CreatedAt       time.Time          `json:"createdAt" bson:"createdAt"`
+}
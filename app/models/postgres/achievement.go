@@ -12,6 +12,8 @@ type AchievementReference struct {
 	VerifiedAt         *time.Time `json:"verified_at,omitempty"`
 	CreatedAt          time.Time  `json:"created_at"`
 	UpdatedAt          time.Time  `json:"updated_at"`
+	DeletedAt          *time.Time `json:"deleted_at,omitempty"`
+	DeletedBy          *string    `json:"deleted_by,omitempty"`
 }
 
 type AchievementHistory struct {
@@ -22,4 +24,50 @@ type AchievementHistory struct {
 	NewStatus      string    `json:"new_status"`
 	Remarks        string    `json:"remarks"`
 	CreatedAt      time.Time `json:"created_at"`
-}
\ No newline at end of file
+}
+const (
+	MemberKetua   = "ketua"
+	MemberAnggota = "anggota"
+)
+
+// AchievementMember adalah satu mahasiswa anggota tim prestasi beserta dosen walinya.
+type AchievementMember struct {
+	StudentID   string  `json:"student_id"`
+	Name        string  `json:"name"`
+	NIM         string  `json:"nim"`
+	Role        string  `json:"role"`
+	AdvisorID   *string `json:"advisor_id,omitempty"` // user id dosen wali
+	AdvisorName *string `json:"advisor_name,omitempty"`
+}
+
+// AdvisorApproval adalah status persetujuan satu dosen wali atas prestasi yang sedang PENDING.
+type AdvisorApproval struct {
+	AdvisorID   string     `json:"advisor_id"`
+	AdvisorName string     `json:"advisor_name"`
+	Approved    bool       `json:"approved"`
+	Notes       *string    `json:"notes,omitempty"`
+	ApprovedAt  *time.Time `json:"approved_at,omitempty"`
+}
+
+const (
+	DuplicateSuspected = "suspected"
+	DuplicateConfirmed = "confirmed"
+	DuplicateDismissed = "dismissed"
+)
+
+// DuplicateFlag adalah prestasi lain yang terdeteksi mirip, dilihat dari sisi satu prestasi.
+type DuplicateFlag struct {
+	AchievementID      string     `json:"achievement_id"`
+	MongoAchievementID string     `json:"-"`
+	Title              string     `json:"title"`
+	StudentID          string     `json:"student_id"`
+	StudentName        string     `json:"student_name"`
+	Status             string     `json:"status"`
+	Score              float64    `json:"score"`
+	Reasons            []string   `json:"reasons"`
+	Decision           string     `json:"decision"`
+	DecidedBy          *string    `json:"decided_by,omitempty"`
+	DecidedAt          *time.Time `json:"decided_at,omitempty"`
+	Notes              *string    `json:"notes,omitempty"`
+	DetectedAt         time.Time  `json:"detected_at"`
+}
@@ -0,0 +1,50 @@
+package postgres
+
+import "time"
+
+// Attestation adalah atestasi bertanda tangan yang diterbitkan saat prestasi VERIFIED.
+// Payload adalah JSON AttestationClaims persis seperti yang ditandatangani.
+type Attestation struct {
+	ID               string     `json:"id"`
+	AchievementID    *string    `json:"achievement_id"`
+	KeyID            string     `json:"key_id"`
+	Payload          string     `json:"-"`
+	Signature        string     `json:"signature"`
+	ContentSHA256    string     `json:"content_sha256"`
+	StudentID        string     `json:"student_id"`
+	VerifierID       string     `json:"verifier_id"`
+	IssuedAt         time.Time  `json:"issued_at"`
+	RevokedAt        *time.Time `json:"revoked_at"`
+	RevokedBy        *string    `json:"revoked_by"`
+	RevocationReason *string    `json:"revocation_reason"`
+}
+
+type AttestationParty struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	NIM  string `json:"nim,omitempty"`
+}
+
+// AttestationKey adalah kunci publik Ed25519 (base64) yang pernah dipakai menandatangani atestasi.
+// RetiredAt terisi setelah kunci dirotasi.
+type AttestationKey struct {
+	KeyID     string     `json:"key_id"`
+	PublicKey string     `json:"public_key"`
+	CreatedAt time.Time  `json:"created_at"`
+	RetiredAt *time.Time `json:"retired_at"`
+}
+
+// AttestationClaims adalah isi yang ditandatangani. Versi 2 menambahkan Team, yaitu seluruh anggota
+// tim (termasuk Student sebagai pembuat).
+type AttestationClaims struct {
+	Version       int                `json:"v"`
+	ID            string             `json:"id"`
+	KeyID         string             `json:"kid"`
+	AchievementID string             `json:"achievement_id"`
+	Title         string             `json:"title"`
+	ContentSHA256 string             `json:"content_sha256"`
+	Student       AttestationParty   `json:"student"`
+	Team          []AttestationParty `json:"team,omitempty"`
+	Verifier      AttestationParty   `json:"verifier"`
+	VerifiedAt    time.Time          `json:"verified_at"`
+}
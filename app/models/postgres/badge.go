@@ -0,0 +1,14 @@
+package postgres
+
+import "time"
+
+// BadgeAssertion adalah badge satu prestasi VERIFIED untuk satu mahasiswa penerima. Salt dipakai
+// untuk meng-hash email penerima di assertion.
+type BadgeAssertion struct {
+	ID            string    `json:"id"`
+	AchievementID string    `json:"achievement_id"`
+	StudentID     string    `json:"student_id"`
+	Salt          string    `json:"-"`
+	IssuedOn      time.Time `json:"issued_on"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,17 @@
+package postgres
+
+import "time"
+
+type AchievementComment struct {
+	ID                 string                `json:"id"`
+	AchievementID      string                `json:"achievement_id"`
+	ParentID           *string               `json:"parent_id,omitempty"`
+	AuthorID           string                `json:"author_id"`
+	AuthorName         string                `json:"author_name"`
+	AuthorRoleID       string                `json:"author_role_id"`
+	AnchorField        *string               `json:"anchor_field,omitempty"`
+	AnchorAttachmentID *string               `json:"anchor_attachment_id,omitempty"`
+	Body               string                `json:"body"`
+	CreatedAt          time.Time             `json:"created_at"`
+	Replies            []*AchievementComment `json:"replies"`
+}
@@ -0,0 +1,16 @@
+package postgres
+
+import "time"
+
+// Event adalah satu kompetisi/event di katalog resmi. NationallyRecognized menandai event yang
+// masuk daftar kompetisi rekognisi nasional.
+type Event struct {
+	ID                   string    `json:"id"`
+	Name                 string    `json:"name"`
+	Organizer            string    `json:"organizer"`
+	Level                string    `json:"level"`
+	Year                 int       `json:"year"`
+	NationallyRecognized bool      `json:"nationally_recognized"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
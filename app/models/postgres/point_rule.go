@@ -0,0 +1,14 @@
+package postgres
+
+import "time"
+
+type PointRule struct {
+	ID              string    `json:"id"`
+	AchievementType string    `json:"achievement_type"`
+	Level           *string   `json:"level"`
+	Rank            *string   `json:"rank"`
+	Points          int       `json:"points"`
+	Description     string    `json:"description"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
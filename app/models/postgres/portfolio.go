@@ -0,0 +1,25 @@
+package postgres
+
+import "time"
+
+// Portfolio adalah pengaturan portofolio publik seorang mahasiswa. Slug acak dibuat saat portofolio
+// pertama kali dimuat; halaman publik hanya bisa dibuka selama Published.
+type Portfolio struct {
+	ID        string    `json:"id"`
+	StudentID string    `json:"student_id"`
+	Slug      *string   `json:"slug"`
+	Published bool      `json:"published"`
+	Headline  *string   `json:"headline"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PortfolioItem adalah pilihan tampil satu prestasi di portofolio.
+type PortfolioItem struct {
+	AchievementID   string `json:"achievement_id"`
+	Visible         bool   `json:"visible"`
+	ShowAttachments bool   `json:"show_attachments"`
+	// ShowPrivateDetails ikut menampilkan field details bertanda private (mis. nomor sertifikat)
+	ShowPrivateDetails bool      `json:"show_private_details"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
@@ -0,0 +1,21 @@
+package postgres
+
+import "time"
+
+// SkpiDocument adalah satu versi dokumen SKPI mahasiswa yang sudah digenerate.
+type SkpiDocument struct {
+	ID               string    `json:"id"`
+	MahasiswaID      string    `json:"mahasiswa_id"`
+	Version          int       `json:"version"`
+	DocumentNumber   string    `json:"document_number"`
+	StorageKey       string    `json:"-"`
+	SHA256           string    `json:"sha256"`
+	Size             int64     `json:"size"`
+	AchievementCount int       `json:"achievement_count"`
+	TotalPoints      int       `json:"total_points"`
+	SignatoryName    string    `json:"signatory_name"`
+	SignatoryTitle   string    `json:"signatory_title"`
+	SignatoryNIP     *string   `json:"signatory_nip"`
+	GeneratedBy      *string   `json:"generated_by"`
+	GeneratedAt      time.Time `json:"generated_at"`
+}
@@ -0,0 +1,15 @@
+package postgres
+
+import "time"
+
+// VerificationTask adalah pekerjaan lanjutan yang wajib selesai setelah prestasi VERIFIED.
+type VerificationTask struct {
+	ID            string    `json:"id"`
+	AchievementID string    `json:"achievement_id"`
+	Kind          string    `json:"kind"`
+	VerifierID    string    `json:"verifier_id"`
+	Attempts      int       `json:"attempts"`
+	LastError     *string   `json:"last_error"`
+	NextRunAt     time.Time `json:"next_run_at"`
+	CreatedAt     time.Time `json:"created_at"`
+}
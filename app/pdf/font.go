@@ -0,0 +1,115 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"math"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// face adalah font TrueType yang di-embed utuh ke PDF. Lebar glyph disimpan dalam satuan 1/1000 em
+// seperti yang dipakai array /W.
+type face struct {
+	name   string
+	ttf    []byte
+	font   *sfnt.Font
+	italic bool
+
+	mu     sync.Mutex
+	glyphs map[rune]glyph
+
+	packOnce sync.Once
+	packed   []byte
+}
+
+type glyph struct {
+	id    uint16
+	width int
+	// r adalah rune yang benar-benar digambar; berbeda dari input jika glyph tidak ada di font.
+	r rune
+}
+
+var faces = [...]*face{
+	newFace("GoRegular", goregular.TTF, false),
+	newFace("Go-Bold", gobold.TTF, false),
+	newFace("Go-Italic", goitalic.TTF, true),
+}
+
+func newFace(name string, ttf []byte, italic bool) *face {
+	f, err := sfnt.Parse(ttf)
+	if err != nil {
+		panic("pdf: font " + name + " tidak valid: " + err.Error())
+	}
+	return &face{name: name, ttf: ttf, font: f, italic: italic, glyphs: make(map[rune]glyph)}
+}
+
+// glyph mencari glyph untuk r. Karakter yang tidak ada di font (mis. CJK) digambar sebagai "?".
+func (f *face) glyph(r rune) glyph {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if g, ok := f.glyphs[r]; ok {
+		return g
+	}
+
+	var b sfnt.Buffer
+	g := glyph{r: r}
+	id, err := f.font.GlyphIndex(&b, r)
+	if err != nil || id == 0 {
+		g.r = '?'
+		id, _ = f.font.GlyphIndex(&b, '?')
+	}
+	g.id = uint16(id)
+	if adv, err := f.font.GlyphAdvance(&b, id, f.ppem(), font.HintingNone); err == nil {
+		g.width = f.scale(adv)
+	}
+	f.glyphs[r] = g
+	return g
+}
+
+// ppem sama dengan unitsPerEm supaya metrik keluar dalam satuan font apa adanya.
+func (f *face) ppem() fixed.Int26_6 {
+	return fixed.I(int(f.font.UnitsPerEm()))
+}
+
+func (f *face) scale(v fixed.Int26_6) int {
+	return int(math.Round(float64(v) / 64 * 1000 / float64(f.font.UnitsPerEm())))
+}
+
+// descriptor menyusun isi /FontDescriptor (tanpa /FontFile2).
+func (f *face) descriptor() (flags int, bbox [4]int, italicAngle float64, ascent, descent, capHeight int) {
+	var b sfnt.Buffer
+	flags = 32 // Nonsymbolic
+	if f.italic {
+		flags |= 64
+	}
+	// sfnt memakai sumbu y ke bawah, PDF ke atas
+	if bounds, err := f.font.Bounds(&b, f.ppem(), font.HintingNone); err == nil {
+		bbox = [4]int{f.scale(bounds.Min.X), -f.scale(bounds.Max.Y), f.scale(bounds.Max.X), -f.scale(bounds.Min.Y)}
+	}
+	if post := f.font.PostTable(); post != nil {
+		italicAngle = post.ItalicAngle
+	}
+	if m, err := f.font.Metrics(&b, f.ppem(), font.HintingNone); err == nil {
+		ascent, descent, capHeight = f.scale(m.Ascent), -f.scale(m.Descent), f.scale(m.CapHeight)
+	}
+	return
+}
+
+// compressed mengembalikan file TTF yang sudah di-deflate; dihitung sekali per proses.
+func (f *face) compressed() []byte {
+	f.packOnce.Do(func() {
+		var buf bytes.Buffer
+		zw, _ := zlib.NewWriterLevel(&buf, zlib.BestCompression)
+		zw.Write(f.ttf)
+		zw.Close()
+		f.packed = buf.Bytes()
+	})
+	return f.packed
+}
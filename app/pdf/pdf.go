@@ -0,0 +1,270 @@
+// Package pdf adalah penulis PDF minimal untuk dokumen teks resmi (SKPI): halaman A4, teks, dan
+// garis. Font Go (golang.org/x/image/font/gofont) di-embed sebagai CIDFontType2 dengan Identity-H
+// supaya nama di luar Latin-1 (mis. "Łukasz", Yunani, Kiril) tidak berubah menjadi "?", dan
+// /ToUnicode membuat teksnya tetap bisa dicari/disalin. Font di-embed utuh tanpa subset (sekitar
+// 70 KB per font setelah deflate). Koordinat memakai titik (pt) dengan origin di kiri atas halaman.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+type Font int
+
+const (
+	Regular Font = iota
+	Bold
+	Italic
+)
+
+type Document struct {
+	Title   string
+	Subject string
+	pages   []*bytes.Buffer
+	// used mencatat glyph yang dipakai per font untuk /W dan /ToUnicode
+	used [len(faces)]map[uint16]glyph
+}
+
+func New(title, subject string) *Document {
+	return &Document{Title: title, Subject: subject}
+}
+
+// AddPage memulai halaman baru; semua gambar berikutnya masuk ke halaman ini.
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+}
+
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+func (d *Document) current() *bytes.Buffer {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	return d.pages[len(d.pages)-1]
+}
+
+// Text menulis s dengan baseline di (x, y).
+func (d *Document) Text(x, y float64, font Font, size float64, s string) {
+	var hex strings.Builder
+	for _, r := range normalize(s) {
+		g := faces[font].glyph(r)
+		if d.used[font] == nil {
+			d.used[font] = make(map[uint16]glyph)
+		}
+		d.used[font][g.id] = g
+		fmt.Fprintf(&hex, "%04X", g.id)
+	}
+	fmt.Fprintf(d.current(), "BT /F%d %.2f Tf %.2f %.2f Td <%s> Tj ET\n", font+1, size, x, PageHeight-y, hex.String())
+}
+
+// TextRight menulis s rata kanan dengan tepi kanan di x.
+func (d *Document) TextRight(x, y float64, font Font, size float64, s string) {
+	d.Text(x-TextWidth(font, size, s), y, font, size, s)
+}
+
+// TextCenter menulis s di tengah halaman.
+func (d *Document) TextCenter(y float64, font Font, size float64, s string) {
+	d.Text((PageWidth-TextWidth(font, size, s))/2, y, font, size, s)
+}
+
+func (d *Document) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(d.current(), "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, x1, PageHeight-y1, x2, PageHeight-y2)
+}
+
+// TextWidth menghitung lebar teks (pt) dari advance width glyph font yang di-embed.
+func TextWidth(font Font, size float64, s string) float64 {
+	total := 0
+	for _, r := range normalize(s) {
+		total += faces[font].glyph(r).width
+	}
+	return float64(total) * size / 1000
+}
+
+// Wrap memecah s menjadi baris-baris yang lebarnya tidak melebihi maxWidth. Kata yang terlalu
+// panjang dibiarkan utuh di barisnya sendiri.
+func Wrap(font Font, size float64, s string, maxWidth float64) []string {
+	lines := []string{}
+	for _, para := range strings.Split(s, "\n") {
+		line := ""
+		for _, word := range strings.Fields(para) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if line != "" && TextWidth(font, size, candidate) > maxWidth {
+				lines = append(lines, line)
+				line = word
+				continue
+			}
+			line = candidate
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// normalize mengganti tab dengan spasi dan membuang karakter kontrol lain.
+func normalize(s string) []rune {
+	out := make([]rune, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r == '\t':
+			out = append(out, ' ')
+		case r < 32 || r == 127:
+		default:
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// textString menulis string metadata: literal biasa untuk ASCII, UTF-16BE dengan BOM untuk
+// selainnya.
+func textString(s string) string {
+	ascii := true
+	for _, r := range s {
+		if r < 32 || r > 126 {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return "(" + strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`).Replace(s) + ")"
+	}
+	return "<FEFF" + utf16Hex(normalize(s)) + ">"
+}
+
+func utf16Hex(rs []rune) string {
+	var sb strings.Builder
+	for _, u := range utf16.Encode(rs) {
+		fmt.Fprintf(&sb, "%04X", u)
+	}
+	return sb.String()
+}
+
+// Bytes menyusun file PDF lengkap. createdAt dicatat di metadata dokumen. Hanya font yang
+// dipakai yang ikut di-embed.
+func (d *Document) Bytes(createdAt time.Time) []byte {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	// Nomor objek dipesan dulu supaya referensi maju bisa ditulis, lalu objek ditulis berurutan.
+	// 1 catalog, 2 pages, 3 info, lalu 5 objek per font yang dipakai, lalu pasangan page + content.
+	var bodies []string
+	reserve := func() int {
+		bodies = append(bodies, "")
+		return len(bodies)
+	}
+	catalog, pages, info := reserve(), reserve(), reserve()
+
+	var fontRefs []string
+	for i, f := range faces {
+		if len(d.used[i]) == 0 {
+			continue
+		}
+		type0, cid, desc, file, toUnicode := reserve(), reserve(), reserve(), reserve(), reserve()
+		fontRefs = append(fontRefs, fmt.Sprintf("/F%d %d 0 R", i+1, type0))
+
+		ids := make([]uint16, 0, len(d.used[i]))
+		for id := range d.used[i] {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(a, b int) bool { return ids[a] < ids[b] })
+
+		bodies[type0-1] = fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+			f.name, cid, toUnicode)
+		bodies[cid-1] = fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor %d 0 R /CIDToGIDMap /Identity /W [%s] >>",
+			f.name, desc, widthArray(ids, d.used[i]))
+		flags, bbox, italicAngle, ascent, descent, capHeight := f.descriptor()
+		bodies[desc-1] = fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags %d /FontBBox [%d %d %d %d] /ItalicAngle %g /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+			f.name, flags, bbox[0], bbox[1], bbox[2], bbox[3], italicAngle, ascent, descent, capHeight, file)
+		packed := f.compressed()
+		bodies[file-1] = fmt.Sprintf("<< /Length %d /Length1 %d /Filter /FlateDecode >>\nstream\n%s\nendstream", len(packed), len(f.ttf), packed)
+		cmap := toUnicodeCMap(ids, d.used[i])
+		bodies[toUnicode-1] = fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(cmap), cmap)
+	}
+
+	resources := "<< >>"
+	if len(fontRefs) > 0 {
+		resources = "<< /Font << " + strings.Join(fontRefs, " ") + " >> >>"
+	}
+	kids := make([]string, len(d.pages))
+	for i, page := range d.pages {
+		pageObj, content := reserve(), reserve()
+		kids[i] = fmt.Sprintf("%d 0 R", pageObj)
+		bodies[pageObj-1] = fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.2f %.2f] /Resources %s /Contents %d 0 R >>",
+			pages, PageWidth, PageHeight, resources, content)
+		bodies[content-1] = fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String())
+	}
+	bodies[catalog-1] = fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pages)
+	bodies[pages-1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages))
+	bodies[info-1] = fmt.Sprintf("<< /Title %s /Subject %s /Producer (pelaporan_prestasi) /CreationDate (D:%s) >>",
+		textString(d.Title), textString(d.Subject), createdAt.UTC().Format("20060102150405Z"))
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(bodies))
+	for i, body := range bodies {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, body)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, catalog, info, xref)
+	return buf.Bytes()
+}
+
+// widthArray menyusun isi /W; glyph id yang berurutan digabung dalam satu array.
+func widthArray(ids []uint16, used map[uint16]glyph) string {
+	var sb strings.Builder
+	for i := 0; i < len(ids); {
+		j := i
+		widths := []string{}
+		for j < len(ids) && int(ids[j]) == int(ids[i])+(j-i) {
+			widths = append(widths, fmt.Sprint(used[ids[j]].width))
+			j++
+		}
+		if sb.Len() > 0 {
+			sb.WriteByte(' ')
+		}
+		fmt.Fprintf(&sb, "%d [%s]", ids[i], strings.Join(widths, " "))
+		i = j
+	}
+	return sb.String()
+}
+
+// toUnicodeCMap memetakan glyph id kembali ke Unicode; bfchar dibatasi 100 entri per blok.
+func toUnicodeCMap(ids []uint16, used map[uint16]glyph) string {
+	var sb strings.Builder
+	sb.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n")
+	sb.WriteString("/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n")
+	sb.WriteString("/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n")
+	sb.WriteString("1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	for start := 0; start < len(ids); start += 100 {
+		chunk := ids[start:min(start+100, len(ids))]
+		fmt.Fprintf(&sb, "%d beginbfchar\n", len(chunk))
+		for _, id := range chunk {
+			fmt.Fprintf(&sb, "<%04X> <%s>\n", id, utf16Hex([]rune{used[id].r}))
+		}
+		sb.WriteString("endbfchar\n")
+	}
+	sb.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	return sb.String()
+}
@@ -0,0 +1,184 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "tulis ulang file golden di testdata")
+
+var goldenTime = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func TestGolden(t *testing.T) {
+	cases := []struct {
+		name  string
+		title string
+		build func(d *Document)
+	}{
+		{"empty", "Kosong", func(d *Document) {}},
+		{"layout", "SKPI (2026) \\ 0001", func(d *Document) {
+			d.TextCenter(60, Bold, 14, "UNIVERSITAS CONTOH")
+			d.Text(56.69, 90, Regular, 10, "Nama (lengkap)\t: Budi \\ Santoso")
+			d.TextRight(538.59, 90, Italic, 8, "No. SKPI-2026-0001")
+			d.Line(56.69, 100, 538.59, 100, 0.5)
+			y := 120.0
+			for _, line := range Wrap(Regular, 10, "Juara 1 lomba karya tulis ilmiah tingkat nasional yang diselenggarakan oleh kementerian.", 200) {
+				d.Text(56.69, y, Regular, 10, line)
+				y += 14
+			}
+		}},
+		{"unicode", "Sertifikat – Łukasz Żółć", func(d *Document) {
+			d.Text(56.69, 60, Regular, 10, "Łukasz Żółć, Ştefan Dumitrescu, Nguyễn")
+			d.Text(56.69, 80, Bold, 10, "Αλέξανδρος · Дмитрий “Kutipan” — 1–2")
+			// Tidak ada di font Go (seperti "ễ" di atas): digambar sebagai "?"
+			d.Text(56.69, 100, Italic, 10, "王小明")
+		}},
+		{"multipage", "Dua Halaman", func(d *Document) {
+			d.Text(56.69, 60, Regular, 10, "Halaman 1")
+			d.AddPage()
+			d.Text(56.69, 60, Regular, 10, "Halaman 2")
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := New(tc.title, "Surat Keterangan Pendamping Ijazah")
+			tc.build(d)
+			raw := d.Bytes(goldenTime)
+			checkStructure(t, raw)
+			got := normalizeGolden(raw)
+
+			path := filepath.Join("testdata", tc.name+".golden")
+			if *update {
+				if err := os.WriteFile(path, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("golden tidak ada (jalankan go test ./app/pdf -update): %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("output berbeda dari %s; jalankan go test ./app/pdf -update lalu periksa diff-nya\n--- got ---\n%s", path, got)
+			}
+		})
+	}
+}
+
+func TestTextWidth(t *testing.T) {
+	if w := TextWidth(Regular, 10, ""); w != 0 {
+		t.Errorf("lebar string kosong = %v", w)
+	}
+	// Tab dihitung sebagai spasi, karakter kontrol lain dibuang
+	if a, b := TextWidth(Regular, 10, "a\tb\x01"), TextWidth(Regular, 10, "a b"); a != b {
+		t.Errorf("TextWidth(a\\tb) = %v, mau %v", a, b)
+	}
+	if TextWidth(Bold, 10, "Budi") <= TextWidth(Regular, 10, "Budi") {
+		t.Error("Bold seharusnya lebih lebar dari Regular")
+	}
+}
+
+func TestGlyphFallback(t *testing.T) {
+	for _, r := range "ŁŻşΑДé–“" {
+		if g := faces[Regular].glyph(r); g.r != r || g.id == 0 {
+			t.Errorf("glyph %q = %+v, seharusnya ada di font", r, g)
+		}
+	}
+	question := faces[Regular].glyph('?')
+	if g := faces[Regular].glyph('王'); g.r != '?' || g.id != question.id {
+		t.Errorf("glyph 王 = %+v, seharusnya jatuh ke \"?\"", g)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	lines := Wrap(Regular, 10, "satu dua tiga\nempat", TextWidth(Regular, 10, "satu dua"))
+	want := []string{"satu dua", "tiga", "empat"}
+	if fmt.Sprint(lines) != fmt.Sprint(want) {
+		t.Errorf("Wrap = %q, mau %q", lines, want)
+	}
+}
+
+var (
+	objHeader  = regexp.MustCompile(`(?m)^(\d+) 0 obj\n`)
+	fontStream = regexp.MustCompile(`(?s)<< /Length (\d+) /Length1 (\d+) /Filter /FlateDecode >>\nstream\n`)
+	xrefTable  = regexp.MustCompile(`(?s)\nxref\n.*\nstartxref\n\d+\n`)
+)
+
+// checkStructure memastikan tabel xref dan startxref menunjuk ke objek yang benar, dan setiap
+// FontFile2 bisa di-inflate kembali ke file TTF dengan panjang /Length1.
+func checkStructure(t *testing.T, raw []byte) {
+	t.Helper()
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(raw)
+	if startxref == nil {
+		t.Fatal("trailer startxref tidak ditemukan")
+	}
+	xref, _ := strconv.Atoi(string(startxref[1]))
+	if !bytes.HasPrefix(raw[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref %d tidak menunjuk ke tabel xref", xref)
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(raw[xref:], -1)
+	if len(entries) != len(objHeader.FindAll(raw, -1)) {
+		t.Fatalf("xref berisi %d entri, ada %d objek", len(entries), len(objHeader.FindAll(raw, -1)))
+	}
+	for i, e := range entries {
+		off, _ := strconv.Atoi(string(e[1]))
+		if want := fmt.Sprintf("%d 0 obj\n", i+1); !bytes.HasPrefix(raw[off:], []byte(want)) {
+			t.Errorf("xref objek %d menunjuk ke offset %d yang bukan %q", i+1, off, want)
+		}
+	}
+
+	for _, m := range fontStream.FindAllSubmatchIndex(raw, -1) {
+		length, _ := strconv.Atoi(string(raw[m[2]:m[3]]))
+		length1, _ := strconv.Atoi(string(raw[m[4]:m[5]]))
+		data := raw[m[1] : m[1]+length]
+		if !bytes.HasPrefix(raw[m[1]+length:], []byte("\nendstream")) {
+			t.Errorf("FontFile2 /Length %d tidak pas dengan endstream", length)
+		}
+		zr, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ttf, err := io.ReadAll(zr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ttf) != length1 {
+			t.Errorf("FontFile2 inflate %d byte, /Length1 %d", len(ttf), length1)
+		}
+		found := false
+		for _, f := range faces {
+			found = found || bytes.Equal(ttf, f.ttf)
+		}
+		if !found {
+			t.Error("FontFile2 bukan salah satu font Go")
+		}
+	}
+}
+
+// normalizeGolden membuang bagian yang bergantung pada implementasi zlib (isi FontFile2 dan
+// offset xref) supaya golden hanya berubah jika struktur atau isi dokumen berubah.
+func normalizeGolden(raw []byte) []byte {
+	var out bytes.Buffer
+	rest := raw
+	for {
+		m := fontStream.FindSubmatchIndex(rest)
+		if m == nil {
+			break
+		}
+		length, _ := strconv.Atoi(string(rest[m[2]:m[3]]))
+		out.Write(rest[:m[0]])
+		fmt.Fprintf(&out, "<< /Length1 %s /Filter /FlateDecode >>\nstream\n[font]", rest[m[4]:m[5]])
+		rest = rest[m[1]+length:]
+	}
+	out.Write(rest)
+	return xrefTable.ReplaceAll(out.Bytes(), []byte("\nxref [diperiksa checkStructure]\n"))
+}
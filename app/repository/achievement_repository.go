@@ -2,17 +2,27 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"pelaporan_prestasi/app/models/mongo"
 	"pelaporan_prestasi/app/models/postgres"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// ErrStatusConflict: status referensi sudah berubah sejak dibaca (update bersamaan).
+var ErrStatusConflict = errors.New("achievement status changed concurrently")
+
+// ErrVersionConflict: versi konten sudah berubah sejak dibaca (optimistic concurrency).
+var ErrVersionConflict = errors.New("achievement content version changed concurrently")
+
 type AchievementRepository struct {
 	PgPool    *pgxpool.Pool
 	MongoColl *mongo.Collection
@@ -32,6 +42,7 @@ func (r *AchievementRepository) InsertMongo(ctx context.Context, data *mongodb.A
 	if data.Attachments == nil { data.Attachments = []mongodb.Attachment{} }
 	if data.Details == nil { data.Details = make(map[string]interface{}) }
 	if data.Tags == nil { data.Tags = []string{} }
+	data.Version = 1
 
 	res, err := r.MongoColl.InsertOne(ctx, data)
 	if err != nil { return "", err }
@@ -46,18 +57,112 @@ func (r *AchievementRepository) FindContentByMongoID(ctx context.Context, hexID
 	return &data, nil
 }
 
-func (r *AchievementRepository) UpdateContentMongo(ctx context.Context, hexID string, data mongodb.Achievement) error {
-	oid, _ := primitive.ObjectIDFromHex(hexID)
+// FindContentByMongoIDs memuat banyak dokumen sekaligus dengan satu query $in.
+// Hasil dikunci dengan hex _id; ID yang tidak valid atau tidak ditemukan tidak ada di map.
+func (r *AchievementRepository) FindContentByMongoIDs(ctx context.Context, hexIDs []string) (map[string]mongodb.Achievement, error) {
+	oids := toObjectIDs(hexIDs)
+	contents := make(map[string]mongodb.Achievement, len(oids))
+	if len(oids) == 0 { return contents, nil }
+
+	cur, err := r.MongoColl.Find(ctx, bson.M{"_id": bson.M{"$in": oids}})
+	if err != nil { return nil, err }
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var data mongodb.Achievement
+		if err := cur.Decode(&data); err != nil { return nil, err }
+		contents[data.ID.Hex()] = data
+	}
+	return contents, cur.Err()
+}
+
+func toObjectIDs(hexIDs []string) []primitive.ObjectID {
+	oids := make([]primitive.ObjectID, 0, len(hexIDs))
+	for _, id := range hexIDs {
+		if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+			oids = append(oids, oid)
+		}
+	}
+	return oids
+}
+
+// CountByEvent menghitung dokumen prestasi (termasuk yang di tong sampah) yang menautkan eventID.
+func (r *AchievementRepository) CountByEvent(ctx context.Context, eventID string) (int64, error) {
+	return r.MongoColl.CountDocuments(ctx, bson.M{"eventId": eventID})
+}
+
+// UpdateContentMongo menimpa konten hanya jika versinya masih expectedVersion, lalu menaikkannya.
+// ErrVersionConflict berarti konten sudah diubah orang lain sejak dibaca.
+func (r *AchievementRepository) UpdateContentMongo(ctx context.Context, hexID string, data mongodb.Achievement, expectedVersion int) error {
+	oid, err := primitive.ObjectIDFromHex(hexID)
+	if err != nil { return err }
+	filter := bson.M{"_id": oid, "version": expectedVersion}
+	if expectedVersion == 1 {
+		filter = bson.M{"_id": oid, "$or": bson.A{bson.M{"version": 1}, bson.M{"version": bson.M{"$exists": false}}}}
+	}
 	update := bson.M{"$set": bson.M{
 		"title":           data.Title,
 		"description":     data.Description,
 		"achievementType": data.AchievementType,
-		"points":          data.Points,
 		"tags":            data.Tags,
 		"details":         data.Details,
+		"eventId":         data.EventID,
+		"version":         expectedVersion + 1,
 		"updatedAt":       time.Now(),
 	}}
-	_, err := r.MongoColl.UpdateOne(ctx, bson.M{"_id": oid}, update)
+	res, err := r.MongoColl.UpdateOne(ctx, filter, update)
+	if err != nil { return err }
+	if res.MatchedCount == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+func (r *AchievementRepository) DeleteMongo(ctx context.Context, hexID string) error {
+	oid, err := primitive.ObjectIDFromHex(hexID)
+	if err != nil { return err }
+	_, err = r.MongoColl.DeleteOne(ctx, bson.M{"_id": oid})
+	return err
+}
+
+func (r *AchievementRepository) MongoExists(ctx context.Context, hexID string) (bool, error) {
+	oid, err := primitive.ObjectIDFromHex(hexID)
+	if err != nil { return false, nil }
+	count, err := r.MongoColl.CountDocuments(ctx, bson.M{"_id": oid}, options.Count().SetLimit(1))
+	return count > 0, err
+}
+
+// MongoStamp adalah waktu buat dan ubah terakhir satu dokumen konten.
+type MongoStamp struct {
+	CreatedAt time.Time `bson:"createdAt"`
+	UpdatedAt time.Time `bson:"updatedAt"`
+}
+
+// FindAllMongoStamps mengembalikan peta _id -> createdAt/updatedAt semua dokumen, untuk rekonsiliasi.
+func (r *AchievementRepository) FindAllMongoStamps(ctx context.Context) (map[string]MongoStamp, error) {
+	opts := options.Find().SetProjection(bson.M{"_id": 1, "createdAt": 1, "updatedAt": 1})
+	cur, err := r.MongoColl.Find(ctx, bson.M{}, opts)
+	if err != nil { return nil, err }
+	defer cur.Close(ctx)
+
+	docs := make(map[string]MongoStamp)
+	for cur.Next(ctx) {
+		var doc struct {
+			ID         primitive.ObjectID `bson:"_id"`
+			MongoStamp `bson:",inline"`
+		}
+		if err := cur.Decode(&doc); err != nil { return nil, err }
+		docs[doc.ID.Hex()] = doc.MongoStamp
+	}
+	return docs, cur.Err()
+}
+
+// SetPoints menyimpan hasil perhitungan rulebook (poin + rinciannya).
+func (r *AchievementRepository) SetPoints(ctx context.Context, hexID string, points int, breakdown mongodb.PointsBreakdown) error {
+	oid, err := primitive.ObjectIDFromHex(hexID)
+	if err != nil { return err }
+	update := bson.M{"$set": bson.M{"points": points, "pointsBreakdown": breakdown, "updatedAt": time.Now()}}
+	_, err = r.MongoColl.UpdateOne(ctx, bson.M{"_id": oid}, update)
 	return err
 }
 
@@ -72,6 +177,72 @@ func (r *AchievementRepository) AddAttachmentMongo(ctx context.Context, hexID st
 	return err
 }
 
+// RemoveAttachmentMongo melepas satu lampiran dari dokumen. Lampiran lama tanpa id dicocokkan lewat fileUrl.
+func (r *AchievementRepository) RemoveAttachmentMongo(ctx context.Context, hexID string, att mongodb.Attachment) error {
+	oid, err := primitive.ObjectIDFromHex(hexID)
+	if err != nil { return err }
+	match := bson.M{"id": att.ID}
+	if att.ID == "" {
+		match = bson.M{"fileUrl": att.FileURL}
+	}
+	update := bson.M{
+		"$pull": bson.M{"attachments": match},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+	_, err = r.MongoColl.UpdateOne(ctx, bson.M{"_id": oid}, update)
+	return err
+}
+
+// SetAttachmentPreview menyimpan hasil preview ke lampiran att tanpa menyentuh updatedAt konten.
+// Lampiran lama tanpa id dicocokkan lewat fileUrl.
+func (r *AchievementRepository) SetAttachmentPreview(ctx context.Context, hexID string, att mongodb.Attachment, preview mongodb.AttachmentPreview) error {
+	oid, err := primitive.ObjectIDFromHex(hexID)
+	if err != nil { return err }
+	filter := bson.M{"_id": oid, "attachments.id": att.ID}
+	if att.ID == "" {
+		filter = bson.M{"_id": oid, "attachments.fileUrl": att.FileURL}
+	}
+	_, err = r.MongoColl.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"attachments.$.preview": preview}})
+	return err
+}
+
+// FindPendingPreviews mengembalikan dokumen (bukan di tong sampah) yang punya lampiran berstatus
+// preview pending atau belum pernah dibuatkan preview, paling lama dulu.
+func (r *AchievementRepository) FindPendingPreviews(ctx context.Context, limit int64) ([]mongodb.Achievement, error) {
+	filter := bson.M{
+		"deleted": bson.M{"$ne": true},
+		"attachments": bson.M{"$elemMatch": bson.M{"$or": bson.A{
+			bson.M{"preview": bson.M{"$exists": false}},
+			bson.M{"preview.status": mongodb.PreviewPending},
+		}}},
+	}
+	opts := options.Find().SetSort(bson.M{"createdAt": 1}).SetLimit(limit).SetProjection(bson.M{"_id": 1, "attachments": 1})
+	cursor, err := r.MongoColl.Find(ctx, filter, opts)
+	if err != nil { return nil, err }
+	defer cursor.Close(ctx)
+
+	list := []mongodb.Achievement{}
+	if err := cursor.All(ctx, &list); err != nil { return nil, err }
+	return list, nil
+}
+
+// SetDeletedMongo menandai (atau membatalkan tanda) soft delete di dokumen konten.
+func (r *AchievementRepository) SetDeletedMongo(ctx context.Context, hexID string, deleted bool, deletedBy string, deletedAt time.Time) error {
+	oid, err := primitive.ObjectIDFromHex(hexID)
+	if err != nil { return err }
+	update := bson.M{
+		"$set": bson.M{"deleted": true, "deletedBy": deletedBy, "deletedAt": deletedAt},
+	}
+	if !deleted {
+		update = bson.M{
+			"$set":   bson.M{"deleted": false},
+			"$unset": bson.M{"deletedBy": "", "deletedAt": ""},
+		}
+	}
+	_, err = r.MongoColl.UpdateOne(ctx, bson.M{"_id": oid}, update)
+	return err
+}
+
 // --- POSTGRES OPERATIONS ---
 
 func (r *AchievementRepository) InsertPostgres(ctx context.Context, ref *postgres.AchievementReference) error {
@@ -79,46 +250,425 @@ func (r *AchievementRepository) InsertPostgres(ctx context.Context, ref *postgre
 	return r.PgPool.QueryRow(ctx, query, ref.StudentID, ref.MongoAchievementID).Scan(&ref.ID)
 }
 
+// CreateRefWithHistory menyimpan referensi (beserta ringkasan kontennya), anggota tim dan history pertama
+// dalam satu transaksi Postgres. members harus memuat pembuat prestasi (ref.StudentID).
+func (r *AchievementRepository) CreateRefWithHistory(ctx context.Context, ref *postgres.AchievementReference, sum ContentSummary, h postgres.AchievementHistory, members []postgres.AchievementMember) error {
+	tx, err := r.PgPool.Begin(ctx)
+	if err != nil { return err }
+	defer tx.Rollback(ctx)
+
+	query := `INSERT INTO achievement_references (student_id, mongo_achievement_id, status, title, achievement_type, event_id, tags, points, content_updated_at, created_at, updated_at)
+		VALUES ($1, $2, 'DRAFT', $3, $4, $5, $6, $7, $8, NOW(), NOW()) RETURNING id, status`
+	err = tx.QueryRow(ctx, query, ref.StudentID, ref.MongoAchievementID, sum.Title, sum.AchievementType, sum.EventID, sum.Tags, sum.Points, sum.UpdatedAt).Scan(&ref.ID, &ref.Status)
+	if err != nil {
+		return err
+	}
+	if err := insertMembers(ctx, tx, ref.ID, members); err != nil {
+		return err
+	}
+
+	histQuery := `INSERT INTO achievement_histories (achievement_id, changed_by, previous_status, new_status, remarks, created_at) VALUES ($1, $2, $3, $4, $5, NOW())`
+	if _, err := tx.Exec(ctx, histQuery, ref.ID, h.ChangedBy, h.PreviousStatus, h.NewStatus, h.Remarks); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
 func (r *AchievementRepository) FindRefByID(ctx context.Context, id string) (*postgres.AchievementReference, error) {
-	query := `SELECT id, student_id, mongo_achievement_id, status, rejection_note FROM achievement_references WHERE id = $1`
+	query := `SELECT id, student_id, mongo_achievement_id, status, rejection_note FROM achievement_references WHERE id = $1 AND deleted_at IS NULL`
 	var ref postgres.AchievementReference
 	err := r.PgPool.QueryRow(ctx, query, id).Scan(&ref.ID, &ref.StudentID, &ref.MongoAchievementID, &ref.Status, &ref.RejectionNote)
-	if err != nil { return nil, err }
+	if err != nil { return nil, notFoundOr(err) }
 	return &ref, nil
 }
 
+// Prestasi tim terlihat oleh setiap anggotanya dan oleh dosen wali setiap anggota. Dipakai sebagai
+// kondisi WHERE atas achievement_references ar; %[1]d diganti nomor parameter (user id).
+const (
+	memberOfCond  = `EXISTS (SELECT 1 FROM achievement_members am WHERE am.achievement_id = ar.id AND am.student_id = $%[1]d)`
+	advisedByCond = `EXISTS (SELECT 1 FROM achievement_members am JOIN mahasiswa mm ON am.student_id = mm.user_id
+		JOIN dosen dd ON mm.advisor_id = dd.id WHERE am.achievement_id = ar.id AND dd.user_id = $%[1]d)`
+	visibleToCond = `(` + memberOfCond + ` OR ` + advisedByCond + `)`
+	// programStudyCond: salah satu anggota tim berasal dari program studi tersebut
+	programStudyCond = `EXISTS (SELECT 1 FROM achievement_members am JOIN mahasiswa mp ON am.student_id = mp.user_id
+		WHERE am.achievement_id = ar.id AND mp.program_study ILIKE $%[1]d)`
+)
+
+// RefFilter menyaring referensi di Postgres: scope akses, kolom referensi, dan ringkasan konten
+// (AchievementType sampai MaxPoints) yang disalin dari Mongo.
+type RefFilter struct {
+	StudentID string
+	// AdvisorID adalah user id dosen wali
+	AdvisorID string
+	// VisibleTo membatasi ke prestasi yang diikuti user ini atau yang anggotanya mahasiswa bimbingannya
+	VisibleTo       string
+	Status          string
+	ProgramStudy    string
+	CreatedFrom     *time.Time
+	CreatedTo       *time.Time
+	AchievementType string
+	EventID         string
+	Tags            []string // semua harus cocok
+	MinPoints       *int
+	MaxPoints       *int
+}
+
+// where membangun FROM + WHERE atas achievement_references ar beserta argumennya.
+func (f RefFilter) where() (string, []interface{}) {
+	query := ` FROM achievement_references ar WHERE ar.deleted_at IS NULL`
+	var args []interface{}
+	add := func(cond string, val interface{}) {
+		args = append(args, val)
+		query += fmt.Sprintf(" AND "+cond, len(args))
+	}
+
+	if f.StudentID != "" { add(memberOfCond, f.StudentID) }
+	if f.AdvisorID != "" { add(advisedByCond, f.AdvisorID) }
+	if f.VisibleTo != "" { add(visibleToCond, f.VisibleTo) }
+	if f.Status != "" { add("ar.status = $%d", f.Status) }
+	if f.ProgramStudy != "" { add(programStudyCond, f.ProgramStudy) }
+	if f.CreatedFrom != nil { add("ar.created_at >= $%d", *f.CreatedFrom) }
+	if f.CreatedTo != nil { add("ar.created_at < $%d", *f.CreatedTo) }
+	if f.AchievementType != "" { add("ar.achievement_type = $%d", f.AchievementType) }
+	if f.EventID != "" { add("ar.event_id = $%d", f.EventID) }
+	if len(f.Tags) > 0 { add("ar.tags @> $%d", f.Tags) }
+	if f.MinPoints != nil { add("ar.points >= $%d", *f.MinPoints) }
+	if f.MaxPoints != nil { add("ar.points <= $%d", *f.MaxPoints) }
+	return query, args
+}
+
+// FindRefs mengembalikan semua referensi yang cocok dengan filter, terbaru dulu.
+func (r *AchievementRepository) FindRefs(ctx context.Context, f RefFilter) ([]postgres.AchievementReference, error) {
+	where, args := f.where()
+	query := `SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status` + where + ` ORDER BY ar.created_at DESC`
+	return r.fetchRefs(ctx, query, args...)
+}
+
+// EventStat adalah rekap referensi yang menautkan satu event.
+type EventStat struct {
+	ByStatus       map[string]int
+	Total          int
+	Students       int
+	VerifiedPoints int
+}
+
+// FindEventStats merekap referensi yang cocok dengan filter per event_id dari ringkasan konten.
+// Students menghitung anggota tim berbeda, bukan hanya pembuat prestasi.
+func (r *AchievementRepository) FindEventStats(ctx context.Context, f RefFilter, eventIDs []string) (map[string]*EventStat, error) {
+	where, args := f.where()
+	args = append(args, eventIDs)
+	where += fmt.Sprintf(" AND ar.event_id = ANY($%d)", len(args))
+
+	stats := map[string]*EventStat{}
+	rows, err := r.PgPool.Query(ctx, `SELECT ar.event_id, ar.status, COUNT(*), COALESCE(SUM(ar.points), 0)`+where+` GROUP BY ar.event_id, ar.status`, args...)
+	if err != nil { return nil, err }
+	defer rows.Close()
+	for rows.Next() {
+		var eventID, status string
+		var count, points int
+		if err := rows.Scan(&eventID, &status, &count, &points); err != nil { return nil, err }
+		st := stats[eventID]
+		if st == nil {
+			st = &EventStat{ByStatus: map[string]int{}}
+			stats[eventID] = st
+		}
+		st.ByStatus[status] = count
+		st.Total += count
+		if status == "VERIFIED" { st.VerifiedPoints += points }
+	}
+	if err := rows.Err(); err != nil { return nil, err }
+
+	query := `SELECT ar.event_id, COUNT(DISTINCT am.student_id) FROM achievement_members am
+		JOIN (SELECT ar.id, ar.event_id` + where + `) ar ON am.achievement_id = ar.id GROUP BY ar.event_id`
+	rows, err = r.PgPool.Query(ctx, query, args...)
+	if err != nil { return nil, err }
+	defer rows.Close()
+	for rows.Next() {
+		var eventID string
+		var students int
+		if err := rows.Scan(&eventID, &students); err != nil { return nil, err }
+		if st := stats[eventID]; st != nil { st.Students = students }
+	}
+	return stats, rows.Err()
+}
+
+// RefPage adalah sort + pagination daftar referensi.
+type RefPage struct {
+	SortColumn string // kolom achievement_references, mis. "ar.created_at"
+	Desc       bool
+	Offset     int64
+	Limit      int64
+}
+
+// FindRefPage mengembalikan satu halaman referensi yang cocok dengan filter beserta total seluruhnya.
+// Baris yang ringkasan kontennya belum disalin (kolom sort NULL) diurutkan paling akhir.
+func (r *AchievementRepository) FindRefPage(ctx context.Context, f RefFilter, page RefPage) ([]postgres.AchievementReference, int64, error) {
+	where, args := f.where()
+
+	var total int64
+	if err := r.PgPool.QueryRow(ctx, `SELECT COUNT(*)`+where, args...).Scan(&total); err != nil { return nil, 0, err }
+
+	dir := "ASC"
+	if page.Desc { dir = "DESC" }
+	query := fmt.Sprintf(`SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status%s ORDER BY %s %s NULLS LAST, ar.id %s LIMIT %d OFFSET %d`,
+		where, page.SortColumn, dir, dir, page.Limit, page.Offset)
+	refs, err := r.fetchRefs(ctx, query, args...)
+	if err != nil { return nil, 0, err }
+	return refs, total, nil
+}
+
 func (r *AchievementRepository) FindRefsByStudentID(ctx context.Context, studentID string) ([]postgres.AchievementReference, error) {
-	query := `SELECT id, student_id, mongo_achievement_id, status FROM achievement_references WHERE student_id = $1 ORDER BY created_at DESC`
+	query := `SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status FROM achievement_references ar WHERE ar.deleted_at IS NULL AND ` +
+		fmt.Sprintf(memberOfCond, 1) + ` ORDER BY ar.created_at DESC`
 	return r.fetchRefs(ctx, query, studentID)
 }
 
-func (r *AchievementRepository) FindRefsByAdvisorID(ctx context.Context, advisorID string) ([]postgres.AchievementReference, error) {
-	query := `SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status FROM achievement_references ar JOIN mahasiswa m ON ar.student_id = m.user_id WHERE m.advisor_id = $1 ORDER BY ar.created_at DESC`
-	return r.fetchRefs(ctx, query, advisorID)
+// FindRefsByAdvisorID: advisorUserID adalah user id dosen wali, bukan dosen.id.
+func (r *AchievementRepository) FindRefsByAdvisorID(ctx context.Context, advisorUserID string) ([]postgres.AchievementReference, error) {
+	query := `SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status FROM achievement_references ar WHERE ar.deleted_at IS NULL AND ` +
+		fmt.Sprintf(advisedByCond, 1) + ` ORDER BY ar.created_at DESC`
+	return r.fetchRefs(ctx, query, advisorUserID)
 }
 
 func (r *AchievementRepository) FindAllRefs(ctx context.Context) ([]postgres.AchievementReference, error) {
-	query := `SELECT id, student_id, mongo_achievement_id, status FROM achievement_references ORDER BY created_at DESC`
+	query := `SELECT id, student_id, mongo_achievement_id, status FROM achievement_references WHERE deleted_at IS NULL ORDER BY created_at DESC`
 	return r.fetchRefs(ctx, query)
 }
 
-func (r *AchievementRepository) UpdateStatus(ctx context.Context, id, status string, notes *string) error {
-	query := `UPDATE achievement_references SET status=$1, rejection_note=$2, updated_at=NOW() WHERE id=$3`
-	_, err := r.PgPool.Exec(ctx, query, status, notes, id)
+// ContentSummary adalah salinan field konten Mongo yang dipakai filter dan sort daftar prestasi.
+type ContentSummary struct {
+	Title           string
+	AchievementType string
+	EventID         string
+	Tags            []string
+	Points          int
+	UpdatedAt       time.Time // sama dengan updatedAt dokumen Mongo (presisi milidetik, UTC)
+}
+
+// SummaryOf mengambil ringkasan dari dokumen konten.
+func SummaryOf(a mongodb.Achievement) ContentSummary {
+	tags := a.Tags
+	if tags == nil { tags = []string{} }
+	return ContentSummary{
+		Title:           a.Title,
+		AchievementType: a.AchievementType,
+		EventID:         a.EventID,
+		Tags:            tags,
+		Points:          a.Points,
+		UpdatedAt:       a.UpdatedAt.UTC().Truncate(time.Millisecond),
+	}
+}
+
+// SaveSummary menimpa ringkasan konten pada referensi dokumen hexID (termasuk yang di tong sampah).
+func (r *AchievementRepository) SaveSummary(ctx context.Context, hexID string, sum ContentSummary) error {
+	query := `UPDATE achievement_references SET title = $2, achievement_type = $3, event_id = $4, tags = $5, points = $6, content_updated_at = $7
+		WHERE mongo_achievement_id = $1`
+	_, err := r.PgPool.Exec(ctx, query, hexID, sum.Title, sum.AchievementType, sum.EventID, sum.Tags, sum.Points, sum.UpdatedAt)
 	return err
 }
 
-func (r *AchievementRepository) UpdateVerified(ctx context.Context, id, verifierID string) error {
-	query := `UPDATE achievement_references SET status='VERIFIED', verified_by=$1, verified_at=NOW(), updated_at=NOW() WHERE id=$2`
-	_, err := r.PgPool.Exec(ctx, query, verifierID, id)
+// FindSummaryStamps mengembalikan peta mongo_achievement_id -> content_updated_at (nil = belum disalin).
+func (r *AchievementRepository) FindSummaryStamps(ctx context.Context) (map[string]*time.Time, error) {
+	rows, err := r.PgPool.Query(ctx, `SELECT mongo_achievement_id, content_updated_at FROM achievement_references`)
+	if err != nil { return nil, err }
+	defer rows.Close()
+
+	stamps := make(map[string]*time.Time)
+	for rows.Next() {
+		var mongoID string
+		var stamp *time.Time
+		if err := rows.Scan(&mongoID, &stamp); err != nil { return nil, err }
+		stamps[mongoID] = stamp
+	}
+	return stamps, rows.Err()
+}
+
+// StatusChange adalah satu perpindahan status beserta baris history-nya.
+type StatusChange struct {
+	RefID     string
+	From      string
+	To        string
+	ChangedBy string
+	Remarks   string
+	Note      *string // disimpan ke rejection_note (nil = dikosongkan)
+}
+
+// TransitionStatus mengubah status dan mencatat history dalam satu transaksi; perpindahan ke
+// VERIFIED sekaligus mencatat verification_tasks.
+// Update hanya berhasil jika status di DB masih sama dengan From; selain itu ErrStatusConflict.
+func (r *AchievementRepository) TransitionStatus(ctx context.Context, ch StatusChange) error {
+	tx, err := r.PgPool.Begin(ctx)
+	if err != nil { return err }
+	defer tx.Rollback(ctx)
+
+	if err := transitionTx(ctx, tx, ch); err != nil { return err }
+	return tx.Commit(ctx)
+}
+
+func transitionTx(ctx context.Context, tx pgx.Tx, ch StatusChange) error {
+	query := `UPDATE achievement_references SET status=$1, rejection_note=$2, updated_at=NOW() WHERE id=$3 AND status=$4`
+	if ch.To == "VERIFIED" {
+		query = `UPDATE achievement_references SET status=$1, rejection_note=$2, verified_by=$5, verified_at=NOW(), updated_at=NOW() WHERE id=$3 AND status=$4`
+	}
+	args := []interface{}{ch.To, ch.Note, ch.RefID, ch.From}
+	if ch.To == "VERIFIED" {
+		args = append(args, ch.ChangedBy)
+	}
+
+	tag, err := tx.Exec(ctx, query, args...)
+	if err != nil { return err }
+	if tag.RowsAffected() == 0 { return ErrStatusConflict }
+
+	if ch.To == "VERIFIED" {
+		if err := insertVerifiedTasksTx(ctx, tx, ch.RefID, ch.ChangedBy); err != nil { return err }
+	}
+	// Putaran verifikasi baru: persetujuan dosen wali dari putaran sebelumnya tidak berlaku lagi
+	if ch.To == "PENDING" {
+		if _, err := tx.Exec(ctx, `DELETE FROM achievement_advisor_approvals WHERE achievement_id = $1`, ch.RefID); err != nil {
+			return err
+		}
+	}
+	return insertHistoryTx(ctx, tx, ch)
+}
+
+func insertHistoryTx(ctx context.Context, tx pgx.Tx, ch StatusChange) error {
+	histQuery := `INSERT INTO achievement_histories (achievement_id, changed_by, previous_status, new_status, remarks, created_at) VALUES ($1, $2, $3, $4, $5, NOW())`
+	_, err := tx.Exec(ctx, histQuery, ch.RefID, ch.ChangedBy, ch.From, ch.To, ch.Remarks)
 	return err
 }
 
+// DeleteRef menghapus referensi beserta history-nya dalam satu transaksi.
 func (r *AchievementRepository) DeleteRef(ctx context.Context, id string) error {
-	_, err := r.PgPool.Exec(ctx, "DELETE FROM achievement_references WHERE id=$1", id)
+	tx, err := r.PgPool.Begin(ctx)
+	if err != nil { return err }
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "DELETE FROM achievement_histories WHERE achievement_id=$1", id); err != nil { return err }
+	if _, err := tx.Exec(ctx, "DELETE FROM achievement_references WHERE id=$1", id); err != nil { return err }
+	return tx.Commit(ctx)
+}
+
+// SoftDeleteRef memindahkan referensi ke tong sampah. ErrNotFound jika tidak ada / sudah di tong sampah.
+func (r *AchievementRepository) SoftDeleteRef(ctx context.Context, id, deletedBy string) (time.Time, error) {
+	var deletedAt time.Time
+	query := `UPDATE achievement_references SET deleted_at = NOW(), deleted_by = $2 WHERE id = $1 AND deleted_at IS NULL RETURNING deleted_at`
+	err := r.PgPool.QueryRow(ctx, query, id, deletedBy).Scan(&deletedAt)
+	return deletedAt, notFoundOr(err)
+}
+
+// RestoreRef mengeluarkan referensi dari tong sampah jika dihapus setelah deletedSince.
+// ErrNotFound jika tidak ada di tong sampah atau sudah lewat masa retensi.
+func (r *AchievementRepository) RestoreRef(ctx context.Context, id string, deletedSince time.Time) error {
+	query := `UPDATE achievement_references SET deleted_at = NULL, deleted_by = NULL, updated_at = NOW() WHERE id = $1 AND deleted_at >= $2`
+	tag, err := r.PgPool.Exec(ctx, query, id, deletedSince)
+	if err != nil { return err }
+	if tag.RowsAffected() == 0 { return ErrNotFound }
+	return nil
+}
+
+// FindTrashedRefByID memuat referensi yang ada di tong sampah.
+func (r *AchievementRepository) FindTrashedRefByID(ctx context.Context, id string) (*postgres.AchievementReference, error) {
+	query := `SELECT id, student_id, mongo_achievement_id, status, deleted_at, deleted_by FROM achievement_references WHERE id = $1 AND deleted_at IS NOT NULL`
+	var ref postgres.AchievementReference
+	err := r.PgPool.QueryRow(ctx, query, id).Scan(&ref.ID, &ref.StudentID, &ref.MongoAchievementID, &ref.Status, &ref.DeletedAt, &ref.DeletedBy)
+	if err != nil { return nil, notFoundOr(err) }
+	return &ref, nil
+}
+
+// FindTrashedRefs mengembalikan isi tong sampah yang dihapus setelah deletedSince (studentID kosong = semua),
+// terbaru dihapus dulu.
+func (r *AchievementRepository) FindTrashedRefs(ctx context.Context, studentID string, deletedSince time.Time) ([]postgres.AchievementReference, error) {
+	query := `SELECT id, student_id, mongo_achievement_id, status, deleted_at, deleted_by FROM achievement_references
+		WHERE deleted_at >= $1 AND ($2 = '' OR student_id::text = $2) ORDER BY deleted_at DESC`
+	return r.fetchTrashedRefs(ctx, query, deletedSince, studentID)
+}
+
+// FindExpiredTrash mengembalikan referensi yang dihapus sebelum deletedBefore dan siap dipurge.
+func (r *AchievementRepository) FindExpiredTrash(ctx context.Context, deletedBefore time.Time) ([]postgres.AchievementReference, error) {
+	query := `SELECT id, student_id, mongo_achievement_id, status, deleted_at, deleted_by FROM achievement_references WHERE deleted_at < $1 ORDER BY deleted_at`
+	return r.fetchTrashedRefs(ctx, query, deletedBefore)
+}
+
+func (r *AchievementRepository) fetchTrashedRefs(ctx context.Context, query string, args ...interface{}) ([]postgres.AchievementReference, error) {
+	rows, err := r.PgPool.Query(ctx, query, args...)
+	if err != nil { return nil, err }
+	defer rows.Close()
+	list := []postgres.AchievementReference{}
+	for rows.Next() {
+		var ref postgres.AchievementReference
+		if err := rows.Scan(&ref.ID, &ref.StudentID, &ref.MongoAchievementID, &ref.Status, &ref.DeletedAt, &ref.DeletedBy); err != nil { return nil, err }
+		list = append(list, ref)
+	}
+	return list, rows.Err()
+}
+
+// MongoRef adalah referensi Postgres milik satu dokumen Mongo, untuk rekonsiliasi.
+type MongoRef struct {
+	ID       string
+	Trashed  bool
+	Orphaned bool
+}
+
+// FindAllMongoRefs mengembalikan peta mongo_achievement_id -> referensi, termasuk yang di tong sampah.
+func (r *AchievementRepository) FindAllMongoRefs(ctx context.Context) (map[string]MongoRef, error) {
+	rows, err := r.PgPool.Query(ctx, `SELECT id, mongo_achievement_id, deleted_at IS NOT NULL, orphaned_at IS NOT NULL FROM achievement_references`)
+	if err != nil { return nil, err }
+	defer rows.Close()
+
+	refs := make(map[string]MongoRef)
+	for rows.Next() {
+		var mongoID string
+		var ref MongoRef
+		if err := rows.Scan(&ref.ID, &mongoID, &ref.Trashed, &ref.Orphaned); err != nil { return nil, err }
+		refs[mongoID] = ref
+	}
+	return refs, rows.Err()
+}
+
+// SetOrphaned menandai (atau membatalkan tanda) referensi yang dokumen Mongo-nya hilang.
+func (r *AchievementRepository) SetOrphaned(ctx context.Context, id string, orphaned bool) error {
+	query := `UPDATE achievement_references SET orphaned_at = NOW() WHERE id = $1 AND orphaned_at IS NULL`
+	if !orphaned {
+		query = `UPDATE achievement_references SET orphaned_at = NULL WHERE id = $1`
+	}
+	_, err := r.PgPool.Exec(ctx, query, id)
 	return err
 }
 
+// OrphanRef adalah referensi yang ditandai yatim oleh rekonsiliasi.
+type OrphanRef struct {
+	ID                 string    `json:"id"`
+	StudentID          string    `json:"student_id"`
+	MongoAchievementID string    `json:"mongo_achievement_id"`
+	Status             string    `json:"status"`
+	Title              *string   `json:"title"`
+	OrphanedAt         time.Time `json:"orphaned_at"`
+}
+
+// FindOrphanedRefs mengembalikan referensi bertanda yatim, paling lama dulu.
+func (r *AchievementRepository) FindOrphanedRefs(ctx context.Context) ([]OrphanRef, error) {
+	rows, err := r.PgPool.Query(ctx, `SELECT id, student_id, mongo_achievement_id, status, title, orphaned_at
+		FROM achievement_references WHERE orphaned_at IS NOT NULL ORDER BY orphaned_at`)
+	if err != nil { return nil, err }
+	defer rows.Close()
+
+	list := []OrphanRef{}
+	for rows.Next() {
+		var o OrphanRef
+		if err := rows.Scan(&o.ID, &o.StudentID, &o.MongoAchievementID, &o.Status, &o.Title, &o.OrphanedAt); err != nil { return nil, err }
+		list = append(list, o)
+	}
+	return list, rows.Err()
+}
+
+// FindOrphanedRef mengembalikan satu referensi bertanda yatim; ErrNotFound jika tidak ada atau tidak ditandai.
+func (r *AchievementRepository) FindOrphanedRef(ctx context.Context, id string) (*postgres.AchievementReference, error) {
+	var ref postgres.AchievementReference
+	err := r.PgPool.QueryRow(ctx, `SELECT id, student_id, mongo_achievement_id, status FROM achievement_references
+		WHERE id = $1 AND orphaned_at IS NOT NULL`, id).Scan(&ref.ID, &ref.StudentID, &ref.MongoAchievementID, &ref.Status)
+	if err != nil { return nil, notFoundOr(err) }
+	return &ref, nil
+}
 
 func (r *AchievementRepository) AddHistory(ctx context.Context, h postgres.AchievementHistory) error {
 	query := `INSERT INTO achievement_histories (achievement_id, changed_by, previous_status, new_status, remarks, created_at) VALUES ($1, $2, $3, $4, $5, NOW())`
@@ -145,7 +695,8 @@ func (r *AchievementRepository) IsAdvisorOfRef(ctx context.Context, refID, advis
         SELECT EXISTS (
             SELECT 1 
             FROM achievement_references ar 
-            JOIN mahasiswa m ON ar.student_id = m.user_id
+            JOIN achievement_members am ON am.achievement_id = ar.id
+            JOIN mahasiswa m ON am.student_id = m.user_id
             JOIN dosen d ON m.advisor_id = d.id 
             WHERE ar.id = $1 
             AND d.user_id = $2
@@ -167,4 +718,240 @@ func (r *AchievementRepository) fetchRefs(ctx context.Context, query string, arg
 		list = append(list, ref)
 	}
 	return list, nil
-}
\ No newline at end of file
+}
+// --- TEAM MEMBERS & ADVISOR APPROVALS ---
+
+// ErrNotStudent: salah satu anggota tim bukan mahasiswa terdaftar.
+var ErrNotStudent = errors.New("member is not a registered student")
+
+// insertMembers menyimpan anggota tim; hanya user yang punya baris mahasiswa yang diterima.
+func insertMembers(ctx context.Context, tx pgx.Tx, refID string, members []postgres.AchievementMember) error {
+	query := `INSERT INTO achievement_members (achievement_id, student_id, role, added_at)
+		SELECT $1, m.user_id, $3, NOW() FROM mahasiswa m WHERE m.user_id = $2`
+	for _, mb := range members {
+		tag, err := tx.Exec(ctx, query, refID, mb.StudentID, mb.Role)
+		if err != nil { return err }
+		if tag.RowsAffected() == 0 { return fmt.Errorf("%w: %s", ErrNotStudent, mb.StudentID) }
+	}
+	return nil
+}
+
+// ReplaceMembers mengganti seluruh anggota tim dalam satu transaksi.
+func (r *AchievementRepository) ReplaceMembers(ctx context.Context, refID string, members []postgres.AchievementMember) error {
+	tx, err := r.PgPool.Begin(ctx)
+	if err != nil { return err }
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM achievement_members WHERE achievement_id = $1`, refID); err != nil { return err }
+	if err := insertMembers(ctx, tx, refID, members); err != nil { return err }
+	return tx.Commit(ctx)
+}
+
+// FindMembers mengembalikan anggota tim beserta dosen walinya, ketua lebih dulu.
+func (r *AchievementRepository) FindMembers(ctx context.Context, refID string) ([]postgres.AchievementMember, error) {
+	query := `
+		SELECT am.student_id, u.full_name, COALESCE(m.nim, ''), am.role, d.user_id, ud.full_name
+		FROM achievement_members am
+		JOIN users u ON u.id = am.student_id
+		LEFT JOIN mahasiswa m ON m.user_id = am.student_id
+		LEFT JOIN dosen d ON m.advisor_id = d.id
+		LEFT JOIN users ud ON d.user_id = ud.id
+		WHERE am.achievement_id = $1
+		ORDER BY am.role DESC, am.added_at, u.full_name`
+	rows, err := r.PgPool.Query(ctx, query, refID)
+	if err != nil { return nil, err }
+	defer rows.Close()
+
+	list := []postgres.AchievementMember{}
+	for rows.Next() {
+		var mb postgres.AchievementMember
+		if err := rows.Scan(&mb.StudentID, &mb.Name, &mb.NIM, &mb.Role, &mb.AdvisorID, &mb.AdvisorName); err != nil { return nil, err }
+		list = append(list, mb)
+	}
+	return list, rows.Err()
+}
+
+// RemoveMember mengeluarkan studentID dari tim; ErrNotFound jika ia bukan anggota. Prestasi yang
+// sudah VERIFIED dijadwalkan ulang verification_tasks-nya supaya atestasi mengikuti tim yang baru.
+func (r *AchievementRepository) RemoveMember(ctx context.Context, refID, studentID string) error {
+	tx, err := r.PgPool.Begin(ctx)
+	if err != nil { return err }
+	defer tx.Rollback(ctx)
+
+	var status string
+	var verifiedBy *string
+	err = tx.QueryRow(ctx, `SELECT status, verified_by FROM achievement_references WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, refID).
+		Scan(&status, &verifiedBy)
+	if err != nil { return notFoundOr(err) }
+
+	tag, err := tx.Exec(ctx, `DELETE FROM achievement_members WHERE achievement_id = $1 AND student_id = $2`, refID, studentID)
+	if err != nil { return err }
+	if tag.RowsAffected() == 0 { return ErrNotFound }
+
+	if status == "VERIFIED" && verifiedBy != nil {
+		if err := insertVerifiedTasksTx(ctx, tx, refID, *verifiedBy); err != nil { return err }
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *AchievementRepository) IsMemberOfRef(ctx context.Context, refID, studentID string) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM achievement_members WHERE achievement_id = $1 AND student_id = $2)`
+	err := r.PgPool.QueryRow(ctx, query, refID, studentID).Scan(&exists)
+	return exists, err
+}
+
+// requiredAdvisorsQuery: dosen wali (user id) dari setiap anggota tim yang punya dosen wali.
+const requiredAdvisorsQuery = `
+	SELECT DISTINCT d.user_id FROM achievement_members am
+	JOIN mahasiswa m ON am.student_id = m.user_id
+	JOIN dosen d ON m.advisor_id = d.id
+	WHERE am.achievement_id = $1`
+
+// FindApprovals mengembalikan status persetujuan setiap dosen wali anggota tim.
+func (r *AchievementRepository) FindApprovals(ctx context.Context, refID string) ([]postgres.AdvisorApproval, error) {
+	query := `
+		SELECT ra.user_id, u.full_name, aa.advisor_id IS NOT NULL, aa.notes, aa.approved_at
+		FROM (` + requiredAdvisorsQuery + `) ra
+		JOIN users u ON u.id = ra.user_id
+		LEFT JOIN achievement_advisor_approvals aa ON aa.achievement_id = $1 AND aa.advisor_id = ra.user_id
+		ORDER BY u.full_name`
+	rows, err := r.PgPool.Query(ctx, query, refID)
+	if err != nil { return nil, err }
+	defer rows.Close()
+
+	list := []postgres.AdvisorApproval{}
+	for rows.Next() {
+		var a postgres.AdvisorApproval
+		if err := rows.Scan(&a.AdvisorID, &a.AdvisorName, &a.Approved, &a.Notes, &a.ApprovedAt); err != nil { return nil, err }
+		list = append(list, a)
+	}
+	return list, rows.Err()
+}
+
+// ApproveAsAdvisor mencatat persetujuan ch.ChangedBy atas prestasi PENDING. Jika semua dosen wali
+// anggota tim sudah setuju, status langsung dipindah ke VERIFIED dalam transaksi yang sama; jika
+// belum, history PENDING -> PENDING dicatat. Mengembalikan jumlah dosen wali yang belum setuju.
+func (r *AchievementRepository) ApproveAsAdvisor(ctx context.Context, ch StatusChange) (int, error) {
+	tx, err := r.PgPool.Begin(ctx)
+	if err != nil { return 0, err }
+	defer tx.Rollback(ctx)
+
+	// Kunci baris referensi supaya dua persetujuan terakhir yang bersamaan tidak saling melewatkan
+	var status string
+	err = tx.QueryRow(ctx, `SELECT status FROM achievement_references WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, ch.RefID).Scan(&status)
+	if err != nil { return 0, notFoundOr(err) }
+	if status != "PENDING" { return 0, ErrStatusConflict }
+
+	upsert := `INSERT INTO achievement_advisor_approvals (achievement_id, advisor_id, notes, approved_at) VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (achievement_id, advisor_id) DO UPDATE SET notes = EXCLUDED.notes, approved_at = EXCLUDED.approved_at`
+	if _, err := tx.Exec(ctx, upsert, ch.RefID, ch.ChangedBy, ch.Note); err != nil { return 0, err }
+
+	var remaining int
+	countQuery := `SELECT COUNT(*) FROM (` + requiredAdvisorsQuery + `) ra
+		WHERE ra.user_id NOT IN (SELECT advisor_id FROM achievement_advisor_approvals WHERE achievement_id = $1)`
+	if err := tx.QueryRow(ctx, countQuery, ch.RefID).Scan(&remaining); err != nil { return 0, err }
+
+	if remaining == 0 {
+		ch.From, ch.To, ch.Note = "PENDING", "VERIFIED", nil
+		err = transitionTx(ctx, tx, ch)
+	} else {
+		ch.From, ch.To = "PENDING", "PENDING"
+		err = insertHistoryTx(ctx, tx, ch)
+	}
+	if err != nil { return 0, err }
+	return remaining, tx.Commit(ctx)
+}
+
+// --- DUPLICATE DETECTION ---
+
+// DuplicateQuery adalah sinyal pencarian kandidat duplikat untuk satu dokumen.
+type DuplicateQuery struct {
+	MongoID    string
+	Hashes     []string // sha256 lampiran
+	EventID    string
+	StudentIDs []string // pembuat + anggota tim; semua prestasi mereka dibandingkan judul/tanggalnya
+	Limit      int64
+}
+
+// FindDuplicateCandidates mencari dokumen lain (bukan di tong sampah) yang berbagi lampiran,
+// event, atau pemilik dengan q; terbaru dulu.
+func (r *AchievementRepository) FindDuplicateCandidates(ctx context.Context, q DuplicateQuery) ([]mongodb.Achievement, error) {
+	oid, err := primitive.ObjectIDFromHex(q.MongoID)
+	if err != nil { return nil, err }
+
+	or := bson.A{bson.M{"studentId": bson.M{"$in": q.StudentIDs}}}
+	if len(q.Hashes) > 0 { or = append(or, bson.M{"attachments.sha256": bson.M{"$in": q.Hashes}}) }
+	if q.EventID != "" { or = append(or, bson.M{"eventId": q.EventID}) }
+
+	filter := bson.M{"_id": bson.M{"$ne": oid}, "deleted": bson.M{"$ne": true}, "$or": or}
+	opts := options.Find().SetSort(bson.M{"createdAt": -1}).SetLimit(q.Limit)
+	cursor, err := r.MongoColl.Find(ctx, filter, opts)
+	if err != nil { return nil, err }
+	defer cursor.Close(ctx)
+
+	list := []mongodb.Achievement{}
+	if err := cursor.All(ctx, &list); err != nil { return nil, err }
+	return list, nil
+}
+
+// FindRefsByMongoIDs mengembalikan referensi aktif untuk dokumen hexIDs.
+func (r *AchievementRepository) FindRefsByMongoIDs(ctx context.Context, hexIDs []string) ([]postgres.AchievementReference, error) {
+	query := `SELECT id, student_id, mongo_achievement_id, status FROM achievement_references WHERE mongo_achievement_id = ANY($1) AND deleted_at IS NULL`
+	return r.fetchRefs(ctx, query, hexIDs)
+}
+
+// DuplicateSuspect adalah hasil deteksi satu pasangan.
+type DuplicateSuspect struct {
+	OtherID string
+	Score   float64
+	Reasons []string
+}
+
+// UpsertDuplicates menyimpan pasangan mirip untuk refID. Keputusan dosen/admin yang sudah ada
+// dipertahankan; hanya skor dan alasan yang diperbarui.
+func (r *AchievementRepository) UpsertDuplicates(ctx context.Context, refID string, suspects []DuplicateSuspect) error {
+	query := `
+		INSERT INTO achievement_duplicates (achievement_a, achievement_b, score, reasons, decision, detected_at)
+		VALUES (LEAST($1::uuid, $2::uuid), GREATEST($1::uuid, $2::uuid), $3, $4, 'suspected', NOW())
+		ON CONFLICT (achievement_a, achievement_b) DO UPDATE SET score = EXCLUDED.score, reasons = EXCLUDED.reasons, detected_at = NOW()`
+	for _, sp := range suspects {
+		if _, err := r.PgPool.Exec(ctx, query, refID, sp.OtherID, sp.Score, sp.Reasons); err != nil { return err }
+	}
+	return nil
+}
+
+// FindDuplicates mengembalikan prestasi aktif yang dipasangkan dengan refID, skor tertinggi dulu.
+func (r *AchievementRepository) FindDuplicates(ctx context.Context, refID string) ([]postgres.DuplicateFlag, error) {
+	query := `
+		SELECT ar.id, ar.mongo_achievement_id, ar.student_id, COALESCE(u.full_name, ''), ar.status,
+			d.score, d.reasons, d.decision, d.decided_by, d.decided_at, d.notes, d.detected_at
+		FROM achievement_duplicates d
+		JOIN achievement_references ar ON ar.id = CASE WHEN d.achievement_a = $1::uuid THEN d.achievement_b ELSE d.achievement_a END
+		LEFT JOIN users u ON u.id = ar.student_id
+		WHERE (d.achievement_a = $1::uuid OR d.achievement_b = $1::uuid) AND ar.deleted_at IS NULL
+		ORDER BY d.score DESC, d.detected_at DESC`
+	rows, err := r.PgPool.Query(ctx, query, refID)
+	if err != nil { return nil, err }
+	defer rows.Close()
+
+	list := []postgres.DuplicateFlag{}
+	for rows.Next() {
+		var f postgres.DuplicateFlag
+		if err := rows.Scan(&f.AchievementID, &f.MongoAchievementID, &f.StudentID, &f.StudentName, &f.Status,
+			&f.Score, &f.Reasons, &f.Decision, &f.DecidedBy, &f.DecidedAt, &f.Notes, &f.DetectedAt); err != nil { return nil, err }
+		list = append(list, f)
+	}
+	return list, rows.Err()
+}
+
+// DecideDuplicate mencatat keputusan atas pasangan refID-otherID. ErrNotFound jika pasangan tidak ada.
+func (r *AchievementRepository) DecideDuplicate(ctx context.Context, refID, otherID, decision, decidedBy string, notes *string) error {
+	query := `
+		UPDATE achievement_duplicates SET decision = $3, decided_by = $4, decided_at = NOW(), notes = $5
+		WHERE achievement_a = LEAST($1::uuid, $2::uuid) AND achievement_b = GREATEST($1::uuid, $2::uuid)`
+	tag, err := r.PgPool.Exec(ctx, query, refID, otherID, decision, decidedBy, notes)
+	if err != nil { return err }
+	if tag.RowsAffected() == 0 { return ErrNotFound }
+	return nil
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+
+	"pelaporan_prestasi/app/models/postgres"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AttestationRepository struct {
+	PgPool *pgxpool.Pool
+}
+
+func NewAttestationRepository(pg *pgxpool.Pool) *AttestationRepository {
+	return &AttestationRepository{PgPool: pg}
+}
+
+const attestationColumns = `id, achievement_id, key_id, payload, signature, content_sha256, student_id, verifier_id,
+	issued_at, revoked_at, revoked_by, revocation_reason`
+
+// FindParty mengambil nama (dan NIM untuk mahasiswa) user yang dicantumkan di atestasi.
+func (r *AttestationRepository) FindParty(ctx context.Context, userID string) (postgres.AttestationParty, error) {
+	p := postgres.AttestationParty{ID: userID}
+	err := r.PgPool.QueryRow(ctx, `
+		SELECT u.full_name, COALESCE(m.nim, '')
+		FROM users u LEFT JOIN mahasiswa m ON m.user_id = u.id
+		WHERE u.id = $1`, userID).Scan(&p.Name, &p.NIM)
+	return p, notFoundOr(err)
+}
+
+// Create menyimpan atestasi baru dan mencabut atestasi aktif sebelumnya untuk prestasi yang sama
+// dengan alasan "superseded". Dua Create yang berebut untuk prestasi yang sama: yang kalah gagal di
+// unique index atestasi aktif, tidak mencabut atestasi pemenang.
+func (r *AttestationRepository) Create(ctx context.Context, a *postgres.Attestation) error {
+	tx, err := r.PgPool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		UPDATE achievement_attestations SET revoked_at = NOW(), revocation_reason = 'superseded'
+		WHERE achievement_id = $1 AND revoked_at IS NULL`, a.AchievementID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO achievement_attestations (id, achievement_id, key_id, payload, signature, content_sha256, student_id, verifier_id, issued_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		a.ID, a.AchievementID, a.KeyID, a.Payload, a.Signature, a.ContentSHA256, a.StudentID, a.VerifierID, a.IssuedAt)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *AttestationRepository) FindByID(ctx context.Context, id string) (*postgres.Attestation, error) {
+	return r.fetchOne(ctx, `SELECT `+attestationColumns+` FROM achievement_attestations WHERE id = $1`, id)
+}
+
+// FindLatestByAchievement mengembalikan atestasi terbaru prestasi (aktif maupun sudah dicabut).
+func (r *AttestationRepository) FindLatestByAchievement(ctx context.Context, refID string) (*postgres.Attestation, error) {
+	return r.fetchOne(ctx, `SELECT `+attestationColumns+` FROM achievement_attestations
+		WHERE achievement_id = $1 ORDER BY issued_at DESC LIMIT 1`, refID)
+}
+
+// Revoke mencabut atestasi yang masih aktif; ErrNotFound jika tidak ada atau sudah dicabut.
+func (r *AttestationRepository) Revoke(ctx context.Context, id, revokedBy, reason string) error {
+	tag, err := r.PgPool.Exec(ctx, `
+		UPDATE achievement_attestations SET revoked_at = NOW(), revoked_by = $2, revocation_reason = $3
+		WHERE id = $1 AND revoked_at IS NULL`, id, revokedBy, reason)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// RegisterKey mencatat kunci aktif dan menandai kunci lain yang masih aktif sebagai retired.
+func (r *AttestationRepository) RegisterKey(ctx context.Context, keyID, publicKey string) error {
+	tx, err := r.PgPool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO attestation_keys (key_id, public_key, created_at) VALUES ($1, $2, NOW())
+		ON CONFLICT (key_id) DO UPDATE SET retired_at = NULL`, keyID, publicKey)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, `UPDATE attestation_keys SET retired_at = NOW() WHERE key_id <> $1 AND retired_at IS NULL`, keyID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// FindKey mengembalikan kunci publik keyID, aktif maupun sudah retired.
+func (r *AttestationRepository) FindKey(ctx context.Context, keyID string) (*postgres.AttestationKey, error) {
+	var k postgres.AttestationKey
+	err := r.PgPool.QueryRow(ctx, `SELECT key_id, public_key, created_at, retired_at FROM attestation_keys WHERE key_id = $1`, keyID).
+		Scan(&k.KeyID, &k.PublicKey, &k.CreatedAt, &k.RetiredAt)
+	if err != nil {
+		return nil, notFoundOr(err)
+	}
+	return &k, nil
+}
+
+// FindKeys mengembalikan semua kunci, terbaru dulu.
+func (r *AttestationRepository) FindKeys(ctx context.Context) ([]postgres.AttestationKey, error) {
+	rows, err := r.PgPool.Query(ctx, `SELECT key_id, public_key, created_at, retired_at FROM attestation_keys ORDER BY created_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []postgres.AttestationKey{}
+	for rows.Next() {
+		var k postgres.AttestationKey
+		if err := rows.Scan(&k.KeyID, &k.PublicKey, &k.CreatedAt, &k.RetiredAt); err != nil {
+			return nil, err
+		}
+		list = append(list, k)
+	}
+	return list, rows.Err()
+}
+
+func (r *AttestationRepository) fetchOne(ctx context.Context, query string, args ...interface{}) (*postgres.Attestation, error) {
+	var a postgres.Attestation
+	err := r.PgPool.QueryRow(ctx, query, args...).Scan(&a.ID, &a.AchievementID, &a.KeyID, &a.Payload, &a.Signature,
+		&a.ContentSHA256, &a.StudentID, &a.VerifierID, &a.IssuedAt, &a.RevokedAt, &a.RevokedBy, &a.RevocationReason)
+	if err != nil {
+		return nil, notFoundOr(err)
+	}
+	return &a, nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+
+	"pelaporan_prestasi/app/models/postgres"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type BadgeRepository struct {
+	PgPool *pgxpool.Pool
+}
+
+func NewBadgeRepository(pg *pgxpool.Pool) *BadgeRepository {
+	return &BadgeRepository{PgPool: pg}
+}
+
+const badgeColumns = `id, achievement_id, student_id, salt, issued_on, created_at`
+
+// FindOrCreate mengembalikan assertion (achievementID, studentID), membuatnya dengan salt dan tanggal
+// terbit verified_at prestasi jika belum ada.
+func (r *BadgeRepository) FindOrCreate(ctx context.Context, achievementID, studentID, salt string) (*postgres.BadgeAssertion, error) {
+	_, err := r.PgPool.Exec(ctx, `
+		INSERT INTO badge_assertions (achievement_id, student_id, salt, issued_on)
+		SELECT id, $2, $3, COALESCE(verified_at, NOW()) FROM achievement_references WHERE id = $1
+		ON CONFLICT (achievement_id, student_id) DO NOTHING`, achievementID, studentID, salt)
+	if err != nil {
+		return nil, err
+	}
+	return r.fetchOne(ctx, `SELECT `+badgeColumns+` FROM badge_assertions WHERE achievement_id = $1 AND student_id = $2`, achievementID, studentID)
+}
+
+func (r *BadgeRepository) FindByID(ctx context.Context, id string) (*postgres.BadgeAssertion, error) {
+	return r.fetchOne(ctx, `SELECT `+badgeColumns+` FROM badge_assertions WHERE id = $1`, id)
+}
+
+// HasAssertions: badge class prestasi hanya dipublikasikan jika sudah ada assertion-nya.
+func (r *BadgeRepository) HasAssertions(ctx context.Context, achievementID string) (bool, error) {
+	var exists bool
+	err := r.PgPool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM badge_assertions WHERE achievement_id = $1)`, achievementID).Scan(&exists)
+	return exists, err
+}
+
+// FindRecipientEmail mengambil email penerima untuk identity hash.
+func (r *BadgeRepository) FindRecipientEmail(ctx context.Context, studentID string) (string, error) {
+	var email string
+	err := r.PgPool.QueryRow(ctx, `SELECT email FROM users WHERE id = $1`, studentID).Scan(&email)
+	return email, notFoundOr(err)
+}
+
+func (r *BadgeRepository) fetchOne(ctx context.Context, query string, args ...interface{}) (*postgres.BadgeAssertion, error) {
+	var b postgres.BadgeAssertion
+	err := r.PgPool.QueryRow(ctx, query, args...).Scan(&b.ID, &b.AchievementID, &b.StudentID, &b.Salt, &b.IssuedOn, &b.CreatedAt)
+	if err != nil {
+		return nil, notFoundOr(err)
+	}
+	return &b, nil
+}
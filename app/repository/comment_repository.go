@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+
+	"pelaporan_prestasi/app/models/postgres"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CommentRepository struct {
+	PgPool *pgxpool.Pool
+}
+
+func NewCommentRepository(pg *pgxpool.Pool) *CommentRepository {
+	return &CommentRepository{PgPool: pg}
+}
+
+const commentColumns = `c.id, c.achievement_id, c.parent_id, c.author_id, COALESCE(u.full_name, ''), COALESCE(u.role_id::text, ''),
+	c.anchor_field, c.anchor_attachment_id, c.body, c.created_at`
+
+func (r *CommentRepository) Create(ctx context.Context, cm *postgres.AchievementComment) error {
+	query := `
+		INSERT INTO achievement_comments (achievement_id, parent_id, author_id, anchor_field, anchor_attachment_id, body, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		RETURNING id, created_at`
+	return r.PgPool.QueryRow(ctx, query, cm.AchievementID, cm.ParentID, cm.AuthorID, cm.AnchorField, cm.AnchorAttachmentID, cm.Body).
+		Scan(&cm.ID, &cm.CreatedAt)
+}
+
+func (r *CommentRepository) FindByID(ctx context.Context, id string) (*postgres.AchievementComment, error) {
+	query := `SELECT ` + commentColumns + ` FROM achievement_comments c LEFT JOIN users u ON u.id = c.author_id WHERE c.id = $1`
+	list, err := r.fetch(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, ErrNotFound
+	}
+	return &list[0], nil
+}
+
+// FindByAchievement mengembalikan semua komentar satu prestasi (datar), terlama dulu.
+func (r *CommentRepository) FindByAchievement(ctx context.Context, achievementID string) ([]postgres.AchievementComment, error) {
+	query := `SELECT ` + commentColumns + ` FROM achievement_comments c LEFT JOIN users u ON u.id = c.author_id
+		WHERE c.achievement_id = $1 ORDER BY c.created_at, c.id`
+	return r.fetch(ctx, query, achievementID)
+}
+
+func (r *CommentRepository) fetch(ctx context.Context, query string, args ...interface{}) ([]postgres.AchievementComment, error) {
+	rows, err := r.PgPool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []postgres.AchievementComment{}
+	for rows.Next() {
+		var cm postgres.AchievementComment
+		if err := rows.Scan(&cm.ID, &cm.AchievementID, &cm.ParentID, &cm.AuthorID, &cm.AuthorName, &cm.AuthorRoleID,
+			&cm.AnchorField, &cm.AnchorAttachmentID, &cm.Body, &cm.CreatedAt); err != nil {
+			return nil, err
+		}
+		cm.Replies = []*postgres.AchievementComment{}
+		list = append(list, cm)
+	}
+	return list, rows.Err()
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"pelaporan_prestasi/app/models/postgres"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrEventExists: sudah ada event dengan nama dan tahun yang sama.
+var ErrEventExists = errors.New("event with the same name and year already exists")
+
+type EventRepository struct {
+	PgPool *pgxpool.Pool
+}
+
+func NewEventRepository(pg *pgxpool.Pool) *EventRepository {
+	return &EventRepository{PgPool: pg}
+}
+
+const eventColumns = `id, name, organizer, level, year, nationally_recognized, created_at, updated_at`
+
+// EventFilter menyaring katalog; Query dicocokkan ke nama dan penyelenggara.
+type EventFilter struct {
+	Query      string
+	Level      string
+	Year       int
+	Recognized *bool
+}
+
+func (r *EventRepository) Find(ctx context.Context, f EventFilter) ([]postgres.Event, error) {
+	query := `SELECT ` + eventColumns + ` FROM events WHERE TRUE`
+	var args []interface{}
+	add := func(cond string, val interface{}) {
+		args = append(args, val)
+		query += fmt.Sprintf(" AND "+cond, len(args))
+	}
+
+	if f.Query != "" {
+		add("(name ILIKE $%[1]d OR organizer ILIKE $%[1]d)", "%"+f.Query+"%")
+	}
+	if f.Level != "" {
+		add("level = $%d", f.Level)
+	}
+	if f.Year != 0 {
+		add("year = $%d", f.Year)
+	}
+	if f.Recognized != nil {
+		add("nationally_recognized = $%d", *f.Recognized)
+	}
+
+	query += " ORDER BY year DESC, name"
+	return r.fetch(ctx, query, args...)
+}
+
+// isEventID: id yang bukan UUID dianggap tidak ada, supaya query tetap memakai index primary key.
+func isEventID(id string) bool {
+	_, err := uuid.Parse(id)
+	return err == nil
+}
+
+func (r *EventRepository) FindByID(ctx context.Context, id string) (*postgres.Event, error) {
+	if !isEventID(id) {
+		return nil, ErrNotFound
+	}
+	list, err := r.fetch(ctx, `SELECT `+eventColumns+` FROM events WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, ErrNotFound
+	}
+	return &list[0], nil
+}
+
+func (r *EventRepository) Create(ctx context.Context, ev *postgres.Event) error {
+	query := `
+		INSERT INTO events (name, organizer, level, year, nationally_recognized, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		RETURNING id, created_at, updated_at`
+	err := r.PgPool.QueryRow(ctx, query, ev.Name, ev.Organizer, ev.Level, ev.Year, ev.NationallyRecognized).
+		Scan(&ev.ID, &ev.CreatedAt, &ev.UpdatedAt)
+	return uniqueEventOr(err)
+}
+
+func (r *EventRepository) Update(ctx context.Context, ev *postgres.Event) error {
+	if !isEventID(ev.ID) {
+		return ErrNotFound
+	}
+	query := `
+		UPDATE events SET name = $1, organizer = $2, level = $3, year = $4, nationally_recognized = $5, updated_at = NOW()
+		WHERE id = $6
+		RETURNING created_at, updated_at`
+	err := r.PgPool.QueryRow(ctx, query, ev.Name, ev.Organizer, ev.Level, ev.Year, ev.NationallyRecognized, ev.ID).
+		Scan(&ev.CreatedAt, &ev.UpdatedAt)
+	return uniqueEventOr(notFoundOr(err))
+}
+
+func (r *EventRepository) Delete(ctx context.Context, id string) error {
+	if !isEventID(id) {
+		return ErrNotFound
+	}
+	tag, err := r.PgPool.Exec(ctx, `DELETE FROM events WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func uniqueEventOr(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return ErrEventExists
+	}
+	return err
+}
+
+func (r *EventRepository) fetch(ctx context.Context, query string, args ...interface{}) ([]postgres.Event, error) {
+	rows, err := r.PgPool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []postgres.Event{}
+	for rows.Next() {
+		var ev postgres.Event
+		if err := rows.Scan(&ev.ID, &ev.Name, &ev.Organizer, &ev.Level, &ev.Year, &ev.NationallyRecognized, &ev.CreatedAt, &ev.UpdatedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, ev)
+	}
+	return list, rows.Err()
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+
+	"pelaporan_prestasi/app/models/postgres"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PointRuleRepository struct {
+	PgPool *pgxpool.Pool
+}
+
+func NewPointRuleRepository(pg *pgxpool.Pool) *PointRuleRepository {
+	return &PointRuleRepository{PgPool: pg}
+}
+
+const pointRuleColumns = `id, achievement_type, level, rank, points, COALESCE(description, ''), created_at, updated_at`
+
+func (r *PointRuleRepository) FindAll(ctx context.Context) ([]postgres.PointRule, error) {
+	query := `SELECT ` + pointRuleColumns + ` FROM point_rules ORDER BY achievement_type, level NULLS FIRST, rank NULLS FIRST`
+	return r.fetch(ctx, query)
+}
+
+func (r *PointRuleRepository) FindByType(ctx context.Context, achievementType string) ([]postgres.PointRule, error) {
+	query := `SELECT ` + pointRuleColumns + ` FROM point_rules WHERE achievement_type = $1`
+	return r.fetch(ctx, query, achievementType)
+}
+
+func (r *PointRuleRepository) Create(ctx context.Context, rule *postgres.PointRule) error {
+	query := `
+		INSERT INTO point_rules (achievement_type, level, rank, points, description, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		RETURNING id, created_at, updated_at`
+	return r.PgPool.QueryRow(ctx, query, rule.AchievementType, rule.Level, rule.Rank, rule.Points, rule.Description).
+		Scan(&rule.ID, &rule.CreatedAt, &rule.UpdatedAt)
+}
+
+func (r *PointRuleRepository) Update(ctx context.Context, rule *postgres.PointRule) error {
+	query := `
+		UPDATE point_rules SET achievement_type = $1, level = $2, rank = $3, points = $4, description = $5, updated_at = NOW()
+		WHERE id = $6
+		RETURNING created_at, updated_at`
+	err := r.PgPool.QueryRow(ctx, query, rule.AchievementType, rule.Level, rule.Rank, rule.Points, rule.Description, rule.ID).
+		Scan(&rule.CreatedAt, &rule.UpdatedAt)
+	if err != nil {
+		return notFoundOr(err)
+	}
+	return nil
+}
+
+func (r *PointRuleRepository) Delete(ctx context.Context, id string) error {
+	tag, err := r.PgPool.Exec(ctx, `DELETE FROM point_rules WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PointRuleRepository) fetch(ctx context.Context, query string, args ...interface{}) ([]postgres.PointRule, error) {
+	rows, err := r.PgPool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []postgres.PointRule{}
+	for rows.Next() {
+		var rule postgres.PointRule
+		if err := rows.Scan(&rule.ID, &rule.AchievementType, &rule.Level, &rule.Rank, &rule.Points, &rule.Description, &rule.CreatedAt, &rule.UpdatedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, rule)
+	}
+	return list, rows.Err()
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+
+	"pelaporan_prestasi/app/models/postgres"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PortfolioRepository struct {
+	PgPool *pgxpool.Pool
+}
+
+func NewPortfolioRepository(pg *pgxpool.Pool) *PortfolioRepository {
+	return &PortfolioRepository{PgPool: pg}
+}
+
+// PortfolioOwner adalah identitas mahasiswa yang ditampilkan di portofolio publik.
+type PortfolioOwner struct {
+	Name         string `json:"name"`
+	ProgramStudy string `json:"program_study"`
+	AcademicYear string `json:"academic_year"`
+}
+
+const portfolioColumns = `p.id, p.student_id, p.slug, p.published, p.headline, p.created_at, p.updated_at`
+
+func (r *PortfolioRepository) FindByStudent(ctx context.Context, studentID string) (*postgres.Portfolio, error) {
+	return r.fetchOne(ctx, `SELECT `+portfolioColumns+` FROM portfolios p WHERE p.student_id = $1`, studentID)
+}
+
+// FindPublished memuat portofolio yang sedang dipublikasikan beserta identitas pemiliknya.
+func (r *PortfolioRepository) FindPublished(ctx context.Context, slug string) (*postgres.Portfolio, *PortfolioOwner, error) {
+	var p postgres.Portfolio
+	var o PortfolioOwner
+	err := r.PgPool.QueryRow(ctx, `
+		SELECT `+portfolioColumns+`, u.full_name, COALESCE(m.program_study, ''), COALESCE(m.academic_year, '')
+		FROM portfolios p
+		JOIN users u ON u.id = p.student_id
+		LEFT JOIN mahasiswa m ON m.user_id = p.student_id
+		WHERE p.slug = $1 AND p.published AND u.is_active`, slug).
+		Scan(&p.ID, &p.StudentID, &p.Slug, &p.Published, &p.Headline, &p.CreatedAt, &p.UpdatedAt, &o.Name, &o.ProgramStudy, &o.AcademicYear)
+	if err != nil {
+		return nil, nil, notFoundOr(err)
+	}
+	return &p, &o, nil
+}
+
+// Save membuat atau memperbarui portofolio mahasiswa. slug hanya dipakai jika portofolio belum
+// punya slug, sehingga tautan yang sudah dibagikan tetap sama.
+func (r *PortfolioRepository) Save(ctx context.Context, studentID string, published bool, headline *string, slug string) (*postgres.Portfolio, error) {
+	return r.fetchOne(ctx, `
+		INSERT INTO portfolios AS p (student_id, slug, published, headline) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (student_id) DO UPDATE SET
+			slug = COALESCE(p.slug, EXCLUDED.slug), published = EXCLUDED.published, headline = EXCLUDED.headline, updated_at = NOW()
+		RETURNING `+portfolioColumns, studentID, slug, published, headline)
+}
+
+// RotateSlug mengganti slug sehingga tautan lama langsung tidak berlaku.
+func (r *PortfolioRepository) RotateSlug(ctx context.Context, studentID, slug string) (*postgres.Portfolio, error) {
+	return r.fetchOne(ctx, `
+		UPDATE portfolios p SET slug = $2, updated_at = NOW() WHERE p.student_id = $1
+		RETURNING `+portfolioColumns, studentID, slug)
+}
+
+func (r *PortfolioRepository) FindItems(ctx context.Context, portfolioID string) (map[string]postgres.PortfolioItem, error) {
+	rows, err := r.PgPool.Query(ctx, `
+		SELECT achievement_id, visible, show_attachments, show_private_details, updated_at FROM portfolio_items WHERE portfolio_id = $1`, portfolioID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := map[string]postgres.PortfolioItem{}
+	for rows.Next() {
+		var it postgres.PortfolioItem
+		if err := rows.Scan(&it.AchievementID, &it.Visible, &it.ShowAttachments, &it.ShowPrivateDetails, &it.UpdatedAt); err != nil {
+			return nil, err
+		}
+		items[it.AchievementID] = it
+	}
+	return items, rows.Err()
+}
+
+func (r *PortfolioRepository) SaveItem(ctx context.Context, portfolioID string, item *postgres.PortfolioItem) error {
+	return r.PgPool.QueryRow(ctx, `
+		INSERT INTO portfolio_items (portfolio_id, achievement_id, visible, show_attachments, show_private_details) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (portfolio_id, achievement_id) DO UPDATE SET
+			visible = EXCLUDED.visible, show_attachments = EXCLUDED.show_attachments,
+			show_private_details = EXCLUDED.show_private_details, updated_at = NOW()
+		RETURNING updated_at`, portfolioID, item.AchievementID, item.Visible, item.ShowAttachments, item.ShowPrivateDetails).Scan(&item.UpdatedAt)
+}
+
+func (r *PortfolioRepository) fetchOne(ctx context.Context, query string, args ...interface{}) (*postgres.Portfolio, error) {
+	var p postgres.Portfolio
+	err := r.PgPool.QueryRow(ctx, query, args...).Scan(&p.ID, &p.StudentID, &p.Slug, &p.Published, &p.Headline, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		return nil, notFoundOr(err)
+	}
+	return &p, nil
+}
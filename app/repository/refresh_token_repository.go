@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"pelaporan_prestasi/app/models/postgres"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrRefreshTokenRevoked  = errors.New("refresh token revoked")
+	// ErrRefreshTokenReused dikembalikan saat token yang sudah dirotasi dipakai lagi.
+	// Seluruh family token sudah dicabut ketika error ini muncul.
+	ErrRefreshTokenReused = errors.New("refresh token reuse detected")
+)
+
+type RefreshTokenRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewRefreshTokenRepository(db *pgxpool.Pool) *RefreshTokenRepository {
+	return &RefreshTokenRepository{DB: db}
+}
+
+func (r *RefreshTokenRepository) Create(ctx context.Context, t *postgres.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (id, user_id, family_id, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		RETURNING created_at
+	`
+	return r.DB.QueryRow(ctx, query, t.ID, t.UserID, t.FamilyID, t.ExpiresAt).Scan(&t.CreatedAt)
+}
+
+func (r *RefreshTokenRepository) FindByID(ctx context.Context, id string) (*postgres.RefreshToken, error) {
+	query := `
+		SELECT id, user_id, family_id, expires_at, revoked_at, replaced_by, created_at
+		FROM refresh_tokens
+		WHERE id = $1
+	`
+	var t postgres.RefreshToken
+	err := r.DB.QueryRow(ctx, query, id).Scan(
+		&t.ID, &t.UserID, &t.FamilyID, &t.ExpiresAt, &t.RevokedAt, &t.ReplacedBy, &t.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrRefreshTokenNotFound
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Rotate menukar token oldID dengan token next dalam satu transaksi.
+// Jika oldID sudah pernah dirotasi, seluruh family dicabut dan ErrRefreshTokenReused dikembalikan.
+func (r *RefreshTokenRepository) Rotate(ctx context.Context, oldID string, next *postgres.RefreshToken) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var old postgres.RefreshToken
+	err = tx.QueryRow(ctx, `
+		SELECT id, user_id, family_id, revoked_at, replaced_by
+		FROM refresh_tokens
+		WHERE id = $1
+		FOR UPDATE
+	`, oldID).Scan(&old.ID, &old.UserID, &old.FamilyID, &old.RevokedAt, &old.ReplacedBy)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrRefreshTokenNotFound
+		}
+		return err
+	}
+
+	if old.RevokedAt != nil {
+		if old.ReplacedBy == nil {
+			return ErrRefreshTokenRevoked
+		}
+		if _, err := tx.Exec(ctx, `UPDATE refresh_tokens SET revoked_at = NOW() WHERE family_id = $1 AND revoked_at IS NULL`, old.FamilyID); err != nil {
+			return err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return err
+		}
+		return ErrRefreshTokenReused
+	}
+
+	next.UserID = old.UserID
+	next.FamilyID = old.FamilyID
+	err = tx.QueryRow(ctx, `
+		INSERT INTO refresh_tokens (id, user_id, family_id, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		RETURNING created_at
+	`, next.ID, next.UserID, next.FamilyID, next.ExpiresAt).Scan(&next.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE refresh_tokens SET revoked_at = NOW(), replaced_by = $1 WHERE id = $2`, next.ID, oldID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	query := `UPDATE refresh_tokens SET revoked_at = NOW() WHERE family_id = $1 AND revoked_at IS NULL`
+	_, err := r.DB.Exec(ctx, query, familyID)
+	return err
+}
+
+// RevokeAccessToken memasukkan jti access token ke denylist sampai expiresAt.
+// Entry yang sudah kadaluarsa ikut dibersihkan.
+func (r *RefreshTokenRepository) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	if _, err := r.DB.Exec(ctx, `DELETE FROM revoked_access_tokens WHERE expires_at < NOW()`); err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO revoked_access_tokens (jti, expires_at)
+		VALUES ($1, $2)
+		ON CONFLICT (jti) DO NOTHING
+	`
+	_, err := r.DB.Exec(ctx, query, jti, expiresAt)
+	return err
+}
+
+func (r *RefreshTokenRepository) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	var revoked bool
+	err := r.DB.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM revoked_access_tokens WHERE jti = $1)`, jti).Scan(&revoked)
+	return revoked, err
+}
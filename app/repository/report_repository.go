@@ -2,6 +2,9 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"time"
+
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -27,10 +30,10 @@ func (r *ReportRepository) GetGlobalStats(ctx context.Context) (*GlobalStatistic
 	err := r.PgPool.QueryRow(ctx, "SELECT COUNT(*) FROM mahasiswa").Scan(&stats.TotalMahasiswa)
 	if err != nil { return nil, err }
 
-	err = r.PgPool.QueryRow(ctx, "SELECT COUNT(*) FROM achievement_references").Scan(&stats.TotalPrestasi)
+	err = r.PgPool.QueryRow(ctx, "SELECT COUNT(*) FROM achievement_references WHERE deleted_at IS NULL").Scan(&stats.TotalPrestasi)
 	if err != nil { return nil, err }
 
-	rows, err := r.PgPool.Query(ctx, "SELECT status, COUNT(*) FROM achievement_references GROUP BY status")
+	rows, err := r.PgPool.Query(ctx, "SELECT status, COUNT(*) FROM achievement_references WHERE deleted_at IS NULL GROUP BY status")
 	if err != nil { return nil, err }
 	defer rows.Close()
 
@@ -46,7 +49,7 @@ func (r *ReportRepository) GetGlobalStats(ctx context.Context) (*GlobalStatistic
 
 func (r *ReportRepository) GetStudentStats(ctx context.Context, userID string) (map[string]int, error) {
 	stats := make(map[string]int)
-	query := `SELECT status, COUNT(*) FROM achievement_references WHERE student_id = $1 GROUP BY status`
+	query := `SELECT ar.status, COUNT(*) FROM achievement_references ar WHERE ar.deleted_at IS NULL AND ` + fmt.Sprintf(memberOfCond, 1) + ` GROUP BY ar.status`
 	
 	rows, err := r.PgPool.Query(ctx, query, userID)
 	if err != nil { return nil, err }
@@ -59,4 +62,143 @@ func (r *ReportRepository) GetStudentStats(ctx context.Context, userID string) (
 		stats[status] = count
 	}
 	return stats, nil
-}
\ No newline at end of file
+}
+
+// AdvisorStatistics adalah statistik prestasi mahasiswa bimbingan seorang dosen wali. Prestasi tim
+// dihitung sekali di total/status/tipe, dan dihitung untuk setiap anggota di rincian per mahasiswa.
+type AdvisorStatistics struct {
+	TotalMahasiswa        int                  `json:"total_mahasiswa"`
+	TotalPrestasi         int                  `json:"total_prestasi"`
+	PrestasiByStatus      map[string]int       `json:"prestasi_by_status"`
+	PendingQueue          int                  `json:"pending_queue"`
+	OldestPendingAt       *time.Time           `json:"oldest_pending_at"`
+	OldestPendingAgeHours *float64             `json:"oldest_pending_age_hours"`
+	ByAchievementType     map[string]*TypeStat `json:"by_achievement_type"`
+	Advisees              []*AdviseeStat       `json:"advisees"`
+}
+
+type TypeStat struct {
+	Total    int `json:"total"`
+	Verified int `json:"verified"`
+	Points   int `json:"points"`
+}
+
+type AdviseeStat struct {
+	MahasiswaID      string         `json:"mahasiswa_id"`
+	Name             string         `json:"name"`
+	NIM              string         `json:"nim"`
+	TotalPrestasi    int            `json:"total_prestasi"`
+	PrestasiByStatus map[string]int `json:"prestasi_by_status"`
+	TotalPoints      int            `json:"total_points"`
+}
+
+// FindDosenID mengambil dosen.id dari user id dosen; mahasiswa.advisor_id merujuk ke dosen.id.
+func (r *ReportRepository) FindDosenID(ctx context.Context, userID string) (string, error) {
+	var id string
+	err := r.PgPool.QueryRow(ctx, `SELECT id FROM dosen WHERE user_id = $1`, userID).Scan(&id)
+	return id, notFoundOr(err)
+}
+
+// GetAdvisorStats menghitung jumlah per status, per tipe, per mahasiswa bimbingan, dan antrian
+// PENDING dengan join dosen wali yang sama dengan FindRefsByAdvisorID. Antrian tidak memuat prestasi
+// tim yang sudah disetujui dosen ini dan tinggal menunggu dosen wali lain; umur antrian dihitung
+// sejak prestasi terakhir masuk PENDING.
+func (r *ReportRepository) GetAdvisorStats(ctx context.Context, dosenID, advisorUserID string) (*AdvisorStatistics, error) {
+	stats := &AdvisorStatistics{
+		PrestasiByStatus:  make(map[string]int),
+		ByAchievementType: make(map[string]*TypeStat),
+		Advisees:          []*AdviseeStat{},
+	}
+
+	err := r.PgPool.QueryRow(ctx, "SELECT COUNT(*) FROM mahasiswa WHERE advisor_id = $1", dosenID).Scan(&stats.TotalMahasiswa)
+	if err != nil { return nil, err }
+
+	rows, err := r.PgPool.Query(ctx, `SELECT ar.status, COUNT(*) FROM achievement_references ar WHERE ar.deleted_at IS NULL AND `+
+		fmt.Sprintf(advisedByCond, 1)+` GROUP BY ar.status`, advisorUserID)
+	if err != nil { return nil, err }
+	defer rows.Close()
+
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil { return nil, err }
+		stats.PrestasiByStatus[status] = count
+		stats.TotalPrestasi += count
+	}
+	if err := rows.Err(); err != nil { return nil, err }
+
+	query := `
+		SELECT COUNT(*), MIN(q.since), (EXTRACT(EPOCH FROM LOCALTIMESTAMP - MIN(q.since)) / 3600)::float8
+		FROM (
+			SELECT COALESCE((SELECT MAX(h.created_at) FROM achievement_histories h
+				WHERE h.achievement_id = ar.id AND h.new_status = 'PENDING'), ar.updated_at) AS since
+			FROM achievement_references ar
+			WHERE ar.deleted_at IS NULL AND ar.status = 'PENDING' AND ` + fmt.Sprintf(advisedByCond, 1) + `
+			AND NOT EXISTS (SELECT 1 FROM achievement_advisor_approvals aa WHERE aa.achievement_id = ar.id AND aa.advisor_id = $1)
+		) q`
+	err = r.PgPool.QueryRow(ctx, query, advisorUserID).Scan(&stats.PendingQueue, &stats.OldestPendingAt, &stats.OldestPendingAgeHours)
+	if err != nil { return nil, err }
+
+	if err := r.fillAdvisorTypeStats(ctx, stats, advisorUserID); err != nil { return nil, err }
+	if err := r.fillAdviseeStats(ctx, stats, dosenID); err != nil { return nil, err }
+	return stats, nil
+}
+
+// fillAdvisorTypeStats menghitung per tipe dari ringkasan konten di achievement_references (sama
+// dengan yang dipakai daftar prestasi). Prestasi tim dihitung sekali; poin hanya dari VERIFIED.
+// Referensi yang kontennya belum disalin (achievement_type NULL) dilewati.
+func (r *ReportRepository) fillAdvisorTypeStats(ctx context.Context, stats *AdvisorStatistics, advisorUserID string) error {
+	query := `
+		SELECT ar.achievement_type, COUNT(*), COUNT(*) FILTER (WHERE ar.status = 'VERIFIED'),
+			COALESCE(SUM(ar.points) FILTER (WHERE ar.status = 'VERIFIED'), 0)
+		FROM achievement_references ar
+		WHERE ar.deleted_at IS NULL AND ar.achievement_type IS NOT NULL AND ` + fmt.Sprintf(advisedByCond, 1) + `
+		GROUP BY ar.achievement_type`
+	rows, err := r.PgPool.Query(ctx, query, advisorUserID)
+	if err != nil { return err }
+	defer rows.Close()
+
+	for rows.Next() {
+		var achievementType string
+		var t TypeStat
+		if err := rows.Scan(&achievementType, &t.Total, &t.Verified, &t.Points); err != nil { return err }
+		stats.ByAchievementType[achievementType] = &t
+	}
+	return rows.Err()
+}
+
+// fillAdviseeStats mengisi rincian setiap mahasiswa bimbingan (urut NIM), termasuk yang belum punya
+// prestasi. Prestasi tim dihitung untuk setiap anggota bimbingannya.
+func (r *ReportRepository) fillAdviseeStats(ctx context.Context, stats *AdvisorStatistics, dosenID string) error {
+	query := `
+		SELECT m.id, u.full_name, m.nim, COALESCE(ar.status, ''), COUNT(ar.id),
+			COALESCE(SUM(ar.points) FILTER (WHERE ar.status = 'VERIFIED'), 0)
+		FROM mahasiswa m
+		JOIN users u ON m.user_id = u.id
+		LEFT JOIN achievement_members am ON am.student_id = m.user_id
+		LEFT JOIN achievement_references ar ON ar.id = am.achievement_id AND ar.deleted_at IS NULL
+		WHERE m.advisor_id = $1
+		GROUP BY m.id, u.full_name, m.nim, ar.status
+		ORDER BY m.nim ASC, m.id`
+	rows, err := r.PgPool.Query(ctx, query, dosenID)
+	if err != nil { return err }
+	defer rows.Close()
+
+	for rows.Next() {
+		var a AdviseeStat
+		var status string
+		var count, points int
+		if err := rows.Scan(&a.MahasiswaID, &a.Name, &a.NIM, &status, &count, &points); err != nil { return err }
+		if n := len(stats.Advisees); n == 0 || stats.Advisees[n-1].MahasiswaID != a.MahasiswaID {
+			a.PrestasiByStatus = make(map[string]int)
+			stats.Advisees = append(stats.Advisees, &a)
+		}
+		current := stats.Advisees[len(stats.Advisees)-1]
+		if count > 0 {
+			current.PrestasiByStatus[status] = count
+			current.TotalPrestasi += count
+			current.TotalPoints += points
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	mongodb "pelaporan_prestasi/app/models/mongo"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrRevisionConflict: nomor revisi sudah dipakai oleh edit lain yang berjalan bersamaan.
+var ErrRevisionConflict = errors.New("revision number already taken")
+
+// RevisionRepository menyimpan revisi konten prestasi. Koleksi ini insert-only.
+type RevisionRepository struct {
+	Coll *mongo.Collection
+}
+
+func NewRevisionRepository(mongoDB *mongo.Database) *RevisionRepository {
+	coll := mongoDB.Collection("achievement_revisions")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "achievementId", Value: 1}, {Key: "revision", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		log.Printf("⚠️ Gagal membuat index achievement_revisions: %v", err)
+	}
+	return &RevisionRepository{Coll: coll}
+}
+
+// Insert menulis revisi baru; unique index (achievementId, revision) menolak nomor ganda.
+func (r *RevisionRepository) Insert(ctx context.Context, rev *mongodb.AchievementRevision) error {
+	if rev.CreatedAt.IsZero() {
+		rev.CreatedAt = time.Now()
+	}
+	if rev.Tags == nil {
+		rev.Tags = []string{}
+	}
+	if rev.Details == nil {
+		rev.Details = mongodb.AchievementDetails{}
+	}
+	if rev.ChangedFields == nil {
+		rev.ChangedFields = []string{}
+	}
+
+	res, err := r.Coll.InsertOne(ctx, rev)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrRevisionConflict
+	}
+	if err != nil {
+		return err
+	}
+	rev.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *RevisionRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.Coll.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+// DeleteByAchievement dipakai saat prestasi dihapus permanen.
+func (r *RevisionRepository) DeleteByAchievement(ctx context.Context, achievementID string) error {
+	_, err := r.Coll.DeleteMany(ctx, bson.M{"achievementId": achievementID})
+	return err
+}
+
+// FindByAchievement mengembalikan semua revisi, terbaru dulu.
+func (r *RevisionRepository) FindByAchievement(ctx context.Context, achievementID string) ([]mongodb.AchievementRevision, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "revision", Value: -1}})
+	cur, err := r.Coll.Find(ctx, bson.M{"achievementId": achievementID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	list := []mongodb.AchievementRevision{}
+	if err := cur.All(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *RevisionRepository) FindOne(ctx context.Context, achievementID string, revision int) (*mongodb.AchievementRevision, error) {
+	var rev mongodb.AchievementRevision
+	err := r.Coll.FindOne(ctx, bson.M{"achievementId": achievementID, "revision": revision}).Decode(&rev)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// FindLatest mengembalikan revisi terakhir; ErrNotFound bila prestasi belum punya revisi
+// (dibuat sebelum fitur revisi ada).
+func (r *RevisionRepository) FindLatest(ctx context.Context, achievementID string) (*mongodb.AchievementRevision, error) {
+	var rev mongodb.AchievementRevision
+	opts := options.FindOne().SetSort(bson.D{{Key: "revision", Value: -1}})
+	err := r.Coll.FindOne(ctx, bson.M{"achievementId": achievementID}, opts).Decode(&rev)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
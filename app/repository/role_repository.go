@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"pelaporan_prestasi/app/models/postgres"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrNotFound = errors.New("not found")
+
+// notFoundOr memetakan pgx.ErrNoRows ke ErrNotFound.
+func notFoundOr(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
+
+type RoleRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewRoleRepository(db *pgxpool.Pool) *RoleRepository {
+	return &RoleRepository{DB: db}
+}
+
+// --- ROLES ---
+
+func (r *RoleRepository) FindAllRoles(ctx context.Context) ([]postgres.Role, error) {
+	rows, err := r.DB.Query(ctx, `SELECT id, name, COALESCE(description, ''), created_at FROM roles ORDER BY name ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []postgres.Role{}
+	for rows.Next() {
+		var role postgres.Role
+		if err := rows.Scan(&role.ID, &role.Name, &role.Description, &role.CreatedAt); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, rows.Err()
+}
+
+func (r *RoleRepository) FindRoleByID(ctx context.Context, id string) (*postgres.Role, error) {
+	var role postgres.Role
+	err := r.DB.QueryRow(ctx, `SELECT id, name, COALESCE(description, ''), created_at FROM roles WHERE id = $1`, id).
+		Scan(&role.ID, &role.Name, &role.Description, &role.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *RoleRepository) CreateRole(ctx context.Context, role *postgres.Role) error {
+	query := `INSERT INTO roles (id, name, description, created_at) VALUES (gen_random_uuid(), $1, $2, NOW()) RETURNING id, created_at`
+	return r.DB.QueryRow(ctx, query, role.Name, role.Description).Scan(&role.ID, &role.CreatedAt)
+}
+
+func (r *RoleRepository) UpdateRole(ctx context.Context, role *postgres.Role) error {
+	tag, err := r.DB.Exec(ctx, `UPDATE roles SET name = $1, description = $2 WHERE id = $3`, role.Name, role.Description, role.ID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *RoleRepository) DeleteRole(ctx context.Context, id string) error {
+	tag, err := r.DB.Exec(ctx, `DELETE FROM roles WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *RoleRepository) CountUsersByRole(ctx context.Context, roleID string) (int, error) {
+	var count int
+	err := r.DB.QueryRow(ctx, `SELECT COUNT(*) FROM users WHERE role_id = $1`, roleID).Scan(&count)
+	return count, err
+}
+
+// --- PERMISSIONS ---
+
+func (r *RoleRepository) FindAllPermissions(ctx context.Context) ([]postgres.Permission, error) {
+	query := `SELECT id, name, resource, action, COALESCE(description, '') FROM permissions ORDER BY resource, action`
+	return r.fetchPermissions(ctx, query)
+}
+
+func (r *RoleRepository) CreatePermission(ctx context.Context, p *postgres.Permission) error {
+	query := `INSERT INTO permissions (name, resource, action, description) VALUES ($1, $2, $3, $4) RETURNING id`
+	return r.DB.QueryRow(ctx, query, p.Name, p.Resource, p.Action, p.Description).Scan(&p.ID)
+}
+
+func (r *RoleRepository) DeletePermission(ctx context.Context, id string) error {
+	tag, err := r.DB.Exec(ctx, `DELETE FROM permissions WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *RoleRepository) FindPermissionsByRoleID(ctx context.Context, roleID string) ([]postgres.Permission, error) {
+	query := `
+		SELECT p.id, p.name, p.resource, p.action, COALESCE(p.description, '')
+		FROM permissions p
+		JOIN role_permissions rp ON rp.permission_id = p.id
+		WHERE rp.role_id = $1
+		ORDER BY p.resource, p.action`
+	return r.fetchPermissions(ctx, query, roleID)
+}
+
+// AssignPermission mengembalikan ErrNotFound jika permissionID bukan UUID atau tidak ada.
+func (r *RoleRepository) AssignPermission(ctx context.Context, roleID, permissionID string) error {
+	query := `INSERT INTO role_permissions (role_id, permission_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	_, err := r.DB.Exec(ctx, query, roleID, permissionID)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && (pgErr.Code == "23503" || pgErr.Code == "22P02") {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (r *RoleRepository) RevokePermission(ctx context.Context, roleID, permissionID string) error {
+	tag, err := r.DB.Exec(ctx, `DELETE FROM role_permissions WHERE role_id = $1 AND permission_id = $2`, roleID, permissionID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *RoleRepository) fetchPermissions(ctx context.Context, query string, args ...interface{}) ([]postgres.Permission, error) {
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []postgres.Permission{}
+	for rows.Next() {
+		var p postgres.Permission
+		if err := rows.Scan(&p.ID, &p.Name, &p.Resource, &p.Action, &p.Description); err != nil {
+			return nil, err
+		}
+		list = append(list, p)
+	}
+	return list, rows.Err()
+}
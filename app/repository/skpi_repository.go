@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"pelaporan_prestasi/app/models/postgres"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SkpiRepository struct {
+	PgPool *pgxpool.Pool
+}
+
+func NewSkpiRepository(pg *pgxpool.Pool) *SkpiRepository {
+	return &SkpiRepository{PgPool: pg}
+}
+
+const skpiColumns = `id, mahasiswa_id, version, document_number, storage_key, sha256, size, achievement_count, total_points,
+	signatory_name, signatory_title, signatory_nip, generated_by, generated_at`
+
+// NextSequence mengambil nomor urut dokumen SKPI berikutnya (global, tidak pernah dipakai ulang).
+func (r *SkpiRepository) NextSequence(ctx context.Context) (int64, error) {
+	var seq int64
+	err := r.PgPool.QueryRow(ctx, `SELECT nextval('skpi_document_seq')`).Scan(&seq)
+	return seq, err
+}
+
+// Create menyimpan dokumen sebagai versi berikutnya milik mahasiswa. Generate bersamaan untuk
+// mahasiswa yang sama bisa berebut nomor versi, jadi insert diulang beberapa kali.
+func (r *SkpiRepository) Create(ctx context.Context, doc *postgres.SkpiDocument) error {
+	query := `
+		INSERT INTO skpi_documents (mahasiswa_id, version, document_number, storage_key, sha256, size, achievement_count,
+			total_points, signatory_name, signatory_title, signatory_nip, generated_by, generated_at)
+		VALUES ($1, (SELECT COALESCE(MAX(version), 0) + 1 FROM skpi_documents WHERE mahasiswa_id = $1),
+			$2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NOW())
+		RETURNING id, version, generated_at`
+	var err error
+	for attempt := 0; attempt < 3; attempt++ {
+		err = r.PgPool.QueryRow(ctx, query, doc.MahasiswaID, doc.DocumentNumber, doc.StorageKey, doc.SHA256, doc.Size,
+			doc.AchievementCount, doc.TotalPoints, doc.SignatoryName, doc.SignatoryTitle, doc.SignatoryNIP, doc.GeneratedBy).
+			Scan(&doc.ID, &doc.Version, &doc.GeneratedAt)
+		var pgErr *pgconn.PgError
+		if !errors.As(err, &pgErr) || pgErr.Code != "23505" || pgErr.ConstraintName == "skpi_documents_document_number_key" {
+			break
+		}
+	}
+	return err
+}
+
+// FindByMahasiswa mengembalikan semua versi SKPI mahasiswa, terbaru dulu.
+func (r *SkpiRepository) FindByMahasiswa(ctx context.Context, mahasiswaID string) ([]postgres.SkpiDocument, error) {
+	return r.fetch(ctx, `SELECT `+skpiColumns+` FROM skpi_documents WHERE mahasiswa_id = $1 ORDER BY version DESC`, mahasiswaID)
+}
+
+func (r *SkpiRepository) FindVersion(ctx context.Context, mahasiswaID string, version int) (*postgres.SkpiDocument, error) {
+	list, err := r.fetch(ctx, `SELECT `+skpiColumns+` FROM skpi_documents WHERE mahasiswa_id = $1 AND version = $2`, mahasiswaID, version)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, ErrNotFound
+	}
+	return &list[0], nil
+}
+
+func (r *SkpiRepository) fetch(ctx context.Context, query string, args ...interface{}) ([]postgres.SkpiDocument, error) {
+	rows, err := r.PgPool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []postgres.SkpiDocument{}
+	for rows.Next() {
+		var d postgres.SkpiDocument
+		if err := rows.Scan(&d.ID, &d.MahasiswaID, &d.Version, &d.DocumentNumber, &d.StorageKey, &d.SHA256, &d.Size,
+			&d.AchievementCount, &d.TotalPoints, &d.SignatoryName, &d.SignatoryTitle, &d.SignatoryNIP, &d.GeneratedBy, &d.GeneratedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, d)
+	}
+	return list, rows.Err()
+}
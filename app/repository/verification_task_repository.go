@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"pelaporan_prestasi/app/models/postgres"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Jenis verification_tasks yang dibuat setiap kali prestasi berpindah ke VERIFIED.
+const (
+	TaskPoints      = "points"
+	TaskAttestation = "attestation"
+)
+
+var verifiedTaskKinds = []string{TaskPoints, TaskAttestation}
+
+type VerificationTaskRepository struct {
+	PgPool *pgxpool.Pool
+}
+
+func NewVerificationTaskRepository(pg *pgxpool.Pool) *VerificationTaskRepository {
+	return &VerificationTaskRepository{PgPool: pg}
+}
+
+// insertVerifiedTasksTx mencatat pekerjaan lanjutan VERIFIED di transaksi perpindahan status.
+// Verifikasi ulang setelah REJECTED mengganti task lama yang mungkin masih tertunda.
+func insertVerifiedTasksTx(ctx context.Context, tx pgx.Tx, refID, verifierID string) error {
+	query := `
+		INSERT INTO verification_tasks (achievement_id, kind, verifier_id, attempts, last_error, next_run_at, created_at)
+		SELECT $1, kind, $2, 0, NULL, NOW(), NOW() FROM UNNEST($3::text[]) AS kind
+		ON CONFLICT (achievement_id, kind) DO UPDATE
+		SET verifier_id = EXCLUDED.verifier_id, attempts = 0, last_error = NULL, next_run_at = NOW(), created_at = NOW()`
+	_, err := tx.Exec(ctx, query, refID, verifierID, verifiedTaskKinds)
+	return err
+}
+
+const taskColumns = `id, achievement_id, kind, verifier_id, attempts, last_error, next_run_at, created_at`
+
+// ClaimDue mengklaim task yang sudah waktunya dijalankan, paling lama dulu. Task yang diklaim
+// digeser next_run_at-nya sejauh lease supaya tidak diambil worker lain; jika proses mati sebelum
+// Done/Fail, task otomatis bisa diklaim lagi setelah lease habis.
+func (r *VerificationTaskRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]postgres.VerificationTask, error) {
+	return r.claim(ctx, `SELECT id FROM verification_tasks WHERE next_run_at <= NOW()
+		ORDER BY next_run_at LIMIT $2 FOR UPDATE SKIP LOCKED`, lease, limit)
+}
+
+// ClaimByAchievement mengklaim task prestasi refID yang sudah waktunya dijalankan.
+func (r *VerificationTaskRepository) ClaimByAchievement(ctx context.Context, refID string, lease time.Duration) ([]postgres.VerificationTask, error) {
+	return r.claim(ctx, `SELECT id FROM verification_tasks WHERE achievement_id = $2 AND next_run_at <= NOW()
+		FOR UPDATE SKIP LOCKED`, lease, refID)
+}
+
+// claim menggeser next_run_at baris hasil selectIDs dalam satu statement, sehingga dua worker
+// tidak pernah mendapat task yang sama. $1 adalah lease dalam detik.
+func (r *VerificationTaskRepository) claim(ctx context.Context, selectIDs string, lease time.Duration, arg interface{}) ([]postgres.VerificationTask, error) {
+	query := `
+		UPDATE verification_tasks SET next_run_at = NOW() + $1 * INTERVAL '1 second'
+		WHERE id IN (` + selectIDs + `)
+		RETURNING ` + taskColumns
+	return r.fetch(ctx, query, int64(lease/time.Second), arg)
+}
+
+// Done menghapus task yang sudah berhasil. createdAt mencegah task yang dibuat ulang oleh verifikasi
+// berikutnya ikut terhapus.
+func (r *VerificationTaskRepository) Done(ctx context.Context, id string, createdAt time.Time) error {
+	_, err := r.PgPool.Exec(ctx, `DELETE FROM verification_tasks WHERE id = $1 AND created_at = $2`, id, createdAt)
+	return err
+}
+
+// Fail mencatat kegagalan dan menjadwalkan percobaan berikutnya setelah backoff.
+func (r *VerificationTaskRepository) Fail(ctx context.Context, id string, cause error, backoff time.Duration) error {
+	query := `UPDATE verification_tasks SET attempts = attempts + 1, last_error = $2, next_run_at = NOW() + $3 * INTERVAL '1 second' WHERE id = $1`
+	_, err := r.PgPool.Exec(ctx, query, id, cause.Error(), int64(backoff/time.Second))
+	return err
+}
+
+func (r *VerificationTaskRepository) fetch(ctx context.Context, query string, args ...interface{}) ([]postgres.VerificationTask, error) {
+	rows, err := r.PgPool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []postgres.VerificationTask{}
+	for rows.Next() {
+		var t postgres.VerificationTask
+		if err := rows.Scan(&t.ID, &t.AchievementID, &t.Kind, &t.VerifierID, &t.Attempts, &t.LastError, &t.NextRunAt, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, t)
+	}
+	return list, rows.Err()
+}
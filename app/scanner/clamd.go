@@ -0,0 +1,85 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+const clamdChunkSize = 64 * 1024
+
+// ClamdScanner memakai perintah INSTREAM clamd lewat socket unix/tcp.
+// Untuk development, server apa pun yang bicara protokol yang sama bisa dipakai sebagai stub.
+type ClamdScanner struct {
+	Network string
+	Address string
+	Timeout time.Duration
+}
+
+func (s *ClamdScanner) Scan(ctx context.Context, r io.Reader) (Result, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, s.Network, s.Address)
+	if err != nil {
+		return Result{}, fmt.Errorf("clamd dial: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	} else if s.Timeout > 0 {
+		conn.SetDeadline(time.Now().Add(s.Timeout))
+	}
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return Result{}, fmt.Errorf("clamd write: %w", err)
+	}
+
+	// Setiap chunk diawali panjang 4 byte big-endian; chunk kosong menandai akhir stream
+	buf := make([]byte, clamdChunkSize)
+	size := make([]byte, 4)
+	for {
+		n, rerr := r.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if _, err := conn.Write(size); err != nil {
+				return Result{}, fmt.Errorf("clamd write: %w", err)
+			}
+			if _, err := conn.Write(buf[:n]); err != nil {
+				return Result{}, fmt.Errorf("clamd write: %w", err)
+			}
+		}
+		if rerr == io.EOF {
+			break
+		}
+		if rerr != nil {
+			return Result{}, rerr
+		}
+	}
+	binary.BigEndian.PutUint32(size, 0)
+	if _, err := conn.Write(size); err != nil {
+		return Result{}, fmt.Errorf("clamd write: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && reply == "" {
+		return Result{}, fmt.Errorf("clamd read: %w", err)
+	}
+	return parseClamdReply(strings.TrimRight(reply, "\x00\n"))
+}
+
+// parseClamdReply membaca balasan "stream: OK" / "stream: <nama> FOUND" / "... ERROR".
+func parseClamdReply(reply string) (Result, error) {
+	_, status, _ := strings.Cut(reply, ": ")
+	switch {
+	case status == "OK":
+		return Result{Clean: true}, nil
+	case strings.HasSuffix(status, " FOUND"):
+		return Result{Clean: false, Signature: strings.TrimSuffix(status, " FOUND")}, nil
+	default:
+		return Result{}, fmt.Errorf("clamd: %s", reply)
+	}
+}
@@ -0,0 +1,49 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Result adalah hasil pemindaian satu file.
+type Result struct {
+	Clean     bool
+	Signature string // nama malware bila Clean == false
+}
+
+// Scanner memindai isi file sebelum file dilepas dari karantina.
+type Scanner interface {
+	Scan(ctx context.Context, r io.Reader) (Result, error)
+}
+
+// NoopScanner menganggap semua file bersih; dipakai bila SCANNER_DRIVER kosong/"none".
+type NoopScanner struct{}
+
+func (NoopScanner) Scan(ctx context.Context, r io.Reader) (Result, error) {
+	return Result{Clean: true}, nil
+}
+
+// NewFromEnv memilih scanner dari SCANNER_DRIVER ("none" default, atau "clamd").
+// CLAMD_ADDRESS berbentuk "unix:/run/clamav/clamd.ctl" atau "tcp:localhost:3310".
+func NewFromEnv() (Scanner, error) {
+	switch driver := os.Getenv("SCANNER_DRIVER"); driver {
+	case "", "none":
+		return NoopScanner{}, nil
+	case "clamd":
+		addr := os.Getenv("CLAMD_ADDRESS")
+		if addr == "" {
+			addr = "tcp:localhost:3310"
+		}
+		network, address, ok := strings.Cut(addr, ":")
+		if !ok || (network != "unix" && network != "tcp") {
+			return nil, fmt.Errorf("CLAMD_ADDRESS tidak valid: %s", addr)
+		}
+		return &ClamdScanner{Network: network, Address: address, Timeout: time.Minute}, nil
+	default:
+		return nil, fmt.Errorf("SCANNER_DRIVER tidak dikenal: %s", driver)
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxBulkDecisionItems = 100
+
+type BulkDecisionItem struct {
+	ID    string `json:"id" binding:"required" example:"b3f1c2d4-..."`
+	Notes string `json:"notes" example:"Sertifikat tidak terbaca"`
+}
+
+type BulkDecisionRequest struct {
+	Status string `json:"status" binding:"required,oneof=VERIFIED REJECTED" example:"VERIFIED"`
+	// Notes dipakai untuk item yang tidak membawa notes sendiri
+	Notes string             `json:"notes" example:"Oke bagus"`
+	Items []BulkDecisionItem `json:"items" binding:"required,min=1,max=100,dive"`
+}
+
+// BulkDecisionResult adalah hasil satu item; Code mengikuti status HTTP endpoint verify satuan.
+type BulkDecisionResult struct {
+	ID      string `json:"id"`
+	Success bool   `json:"success"`
+	Code    int    `json:"code"`
+	Status  string `json:"status,omitempty"`
+	// PendingAdvisors: jumlah dosen wali anggota tim yang belum menyetujui (mode per_advisor)
+	PendingAdvisors int    `json:"pending_advisors,omitempty"`
+	Error           string `json:"error,omitempty"`
+}
+
+type BulkDecisionResponse struct {
+	Succeeded int                  `json:"succeeded"`
+	Failed    int                  `json:"failed"`
+	Results   []BulkDecisionResult `json:"results"`
+}
+
+// BulkDecide godoc
+// @Summary Bulk Verify/Reject Achievements (Dosen)
+// @Description Keputusan yang sama untuk banyak prestasi sekaligus (maks 100). Tiap item diperiksa hak dosen walinya dan dicatat di history seperti verify satuan; kegagalan satu item tidak membatalkan item lain.
+// @Tags Achievements
+// @Security BearerAuth
+// @Param body body BulkDecisionRequest true "Body"
+// @Success 200 {object} BulkDecisionResponse
+// @Router /achievements/bulk-decision [post]
+func (s *AchievementService) BulkDecide(c *gin.Context) {
+	var req BulkDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := BulkDecisionResponse{Results: make([]BulkDecisionResult, 0, len(req.Items))}
+	seen := make(map[string]bool, len(req.Items))
+	for _, item := range req.Items {
+		result := BulkDecisionResult{ID: item.ID}
+		notes := strings.TrimSpace(item.Notes)
+		if notes == "" {
+			notes = strings.TrimSpace(req.Notes)
+		}
+
+		switch {
+		case seen[item.ID]:
+			result.Code, result.Error = 400, "ID duplikat dalam permintaan"
+		case req.Status == StatusRejected && notes == "":
+			result.Code, result.Error = 400, "Catatan wajib diisi untuk penolakan"
+		default:
+			if res, err := s.decideRef(c, item.ID, req.Status, notes); err != nil {
+				code, body := decisionErrorResponse(err)
+				result.Code, result.Error = code, body["error"].(string)
+			} else {
+				result.Success, result.Code, result.Status, result.PendingAdvisors = true, 200, res.Status, res.PendingAdvisors
+			}
+		}
+		seen[item.ID] = true
+
+		if result.Success {
+			resp.Succeeded++
+		} else {
+			resp.Failed++
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	c.JSON(200, gin.H{"data": resp})
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"pelaporan_prestasi/app/models/postgres"
+	"pelaporan_prestasi/app/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxCommentLength dihitung dalam karakter (rune), bukan byte.
+const maxCommentLength = 5000
+
+type CreateCommentRequest struct {
+	Body     string  `json:"body" binding:"required" example:"Mohon lampirkan sertifikat yang terbaca"`
+	ParentID *string `json:"parent_id" example:""`
+	// AnchorField mengaitkan komentar ke field konten: title, description, achievementType, tags, eventId, atau details.<nama>
+	AnchorField *string `json:"anchor_field" example:"details.rank"`
+	// AnchorAttachmentID mengaitkan komentar ke satu lampiran
+	AnchorAttachmentID *string `json:"anchor_attachment_id"`
+}
+
+// anchorableFields adalah field konten di luar details yang boleh dijadikan anchor komentar.
+var anchorableFields = map[string]bool{"title": true, "description": true, "achievementType": true, "tags": true, "eventId": true}
+
+// buildCommentThreads menyusun komentar datar (terlama dulu) menjadi pohon balasan.
+func buildCommentThreads(comments []postgres.AchievementComment) []*postgres.AchievementComment {
+	byID := make(map[string]*postgres.AchievementComment, len(comments))
+	for i := range comments {
+		byID[comments[i].ID] = &comments[i]
+	}
+
+	roots := []*postgres.AchievementComment{}
+	for i := range comments {
+		cm := &comments[i]
+		if cm.ParentID != nil {
+			if parent, ok := byID[*cm.ParentID]; ok {
+				parent.Replies = append(parent.Replies, cm)
+				continue
+			}
+		}
+		roots = append(roots, cm)
+	}
+	return roots
+}
+
+func emptyToNil(s *string) *string {
+	if s == nil || strings.TrimSpace(*s) == "" {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*s)
+	return &trimmed
+}
+
+// GetComments godoc
+// @Summary List Review Comments
+// @Description Thread komentar antara mahasiswa, dosen wali dan admin, tersusun sebagai pohon balasan.
+// @Tags Achievements
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /achievements/{id}/comments [get]
+func (s *AchievementService) GetComments(c *gin.Context) {
+	ref, ok := s.loadRefForRead(c)
+	if !ok {
+		return
+	}
+	comments, err := s.Comments.FindByAchievement(c.Request.Context(), ref.ID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"data": buildCommentThreads(comments)})
+}
+
+// CreateComment godoc
+// @Summary Post Review Comment
+// @Description Mahasiswa pemilik, dosen wali, dan admin boleh berkomentar. Komentar bisa membalas komentar lain (parent_id) dan dikaitkan ke field atau lampiran.
+// @Tags Achievements
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param request body CreateCommentRequest true "Komentar"
+// @Success 201 {object} postgres.AchievementComment
+// @Router /achievements/{id}/comments [post]
+func (s *AchievementService) CreateComment(c *gin.Context) {
+	var req CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	req.Body = strings.TrimSpace(req.Body)
+	if req.Body == "" || utf8.RuneCountInString(req.Body) > maxCommentLength {
+		c.JSON(400, gin.H{"error": "Komentar wajib diisi dan maksimal 5000 karakter"})
+		return
+	}
+	req.ParentID = emptyToNil(req.ParentID)
+	req.AnchorField = emptyToNil(req.AnchorField)
+	req.AnchorAttachmentID = emptyToNil(req.AnchorAttachmentID)
+
+	ref, ok := s.loadRefForRead(c)
+	if !ok {
+		return
+	}
+
+	if req.ParentID != nil {
+		parent, err := s.Comments.FindByID(c.Request.Context(), *req.ParentID)
+		if err != nil || parent.AchievementID != ref.ID {
+			c.JSON(400, gin.H{"error": "parent_id bukan komentar di prestasi ini"})
+			return
+		}
+	}
+
+	if req.AnchorField != nil || req.AnchorAttachmentID != nil {
+		content, err := s.Repo.FindContentByMongoID(c.Request.Context(), ref.MongoAchievementID)
+		if err != nil {
+			c.JSON(500, gin.H{"error": "Content missing"})
+			return
+		}
+		if req.AnchorField != nil {
+			field := *req.AnchorField
+			valid := anchorableFields[field]
+			if name, isDetail := strings.CutPrefix(field, "details."); isDetail {
+				schema, _ := FindDetailSchema(content.AchievementType)
+				for _, f := range schema.Fields {
+					valid = valid || f.Name == name
+				}
+			}
+			if !valid {
+				c.JSON(400, gin.H{"error": "anchor_field tidak dikenal: " + field})
+				return
+			}
+		}
+		if req.AnchorAttachmentID != nil {
+			if _, found := findAttachment(content, *req.AnchorAttachmentID); !found {
+				c.JSON(400, gin.H{"error": "anchor_attachment_id tidak ditemukan"})
+				return
+			}
+		}
+	}
+
+	comment := postgres.AchievementComment{
+		AchievementID:      ref.ID,
+		ParentID:           req.ParentID,
+		AuthorID:           c.GetString("user_id"),
+		AnchorField:        req.AnchorField,
+		AnchorAttachmentID: req.AnchorAttachmentID,
+		Body:               req.Body,
+	}
+	if err := s.Comments.Create(c.Request.Context(), &comment); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	saved, err := s.Comments.FindByID(c.Request.Context(), comment.ID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if saved == nil {
+		saved = &comment
+	}
+	c.JSON(201, gin.H{"status": "success", "data": saved})
+}
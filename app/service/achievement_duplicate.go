@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	mongodb "pelaporan_prestasi/app/models/mongo"
+	"pelaporan_prestasi/app/models/postgres"
+	"pelaporan_prestasi/app/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// duplicateThreshold adalah skor minimal sepasang prestasi ditandai kemungkinan duplikat.
+	duplicateThreshold = 0.5
+	// maxDuplicateCandidates membatasi jumlah dokumen yang dibandingkan per deteksi.
+	maxDuplicateCandidates = 300
+)
+
+// Bobot sinyal kemiripan. Lampiran identik langsung bernilai 1.
+const (
+	weightTitle = 0.5
+	weightEvent = 0.3
+	weightDate  = 0.2
+)
+
+var titleTokenRe = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// titleTokens menormalkan judul menjadi himpunan kata huruf kecil.
+func titleTokens(title string) map[string]bool {
+	tokens := map[string]bool{}
+	for _, t := range titleTokenRe.FindAllString(strings.ToLower(title), -1) {
+		tokens[t] = true
+	}
+	return tokens
+}
+
+// titleSimilarity adalah indeks Jaccard kata-kata kedua judul (0..1).
+func titleSimilarity(a, b string) float64 {
+	ta, tb := titleTokens(a), titleTokens(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	shared := 0
+	for t := range ta {
+		if tb[t] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(ta)+len(tb)-shared)
+}
+
+// contentDates mengembalikan nilai semua field tanggal details sesuai schema tipenya.
+func contentDates(content mongodb.Achievement) map[string]bool {
+	dates := map[string]bool{}
+	schema, _ := FindDetailSchema(content.AchievementType)
+	for _, f := range schema.Fields {
+		if f.Type != FieldDate {
+			continue
+		}
+		if d := detailString(content.Details, f.Name); d != "" {
+			dates[d] = true
+		}
+	}
+	return dates
+}
+
+func attachmentHashes(content mongodb.Achievement) map[string]bool {
+	hashes := map[string]bool{}
+	for _, att := range content.Attachments {
+		if att.SHA256 != "" {
+			hashes[att.SHA256] = true
+		}
+	}
+	return hashes
+}
+
+// duplicateScore menilai kemiripan dua prestasi beserta alasannya.
+func duplicateScore(a, b mongodb.Achievement) (float64, []string) {
+	hashesA := attachmentHashes(a)
+	for h := range attachmentHashes(b) {
+		if hashesA[h] {
+			return 1, []string{"lampiran identik (sha256 " + h[:12] + ")"}
+		}
+	}
+
+	score := 0.0
+	reasons := []string{}
+	if sim := titleSimilarity(a.Title, b.Title); sim > 0 {
+		score += weightTitle * sim
+		if sim >= 0.5 {
+			reasons = append(reasons, fmt.Sprintf("judul mirip (%.0f%%)", sim*100))
+		}
+	}
+	if a.EventID != "" && a.EventID == b.EventID {
+		score += weightEvent
+		reasons = append(reasons, "event katalog sama")
+	}
+	datesA := contentDates(a)
+	for d := range contentDates(b) {
+		if datesA[d] {
+			score += weightDate
+			reasons = append(reasons, "tanggal sama ("+d+")")
+			break
+		}
+	}
+	return score, reasons
+}
+
+// detectDuplicates membandingkan prestasi ref dengan prestasi pembuat, anggota timnya, dan prestasi
+// lain yang berbagi lampiran atau event, lalu menyimpan pasangan yang skornya melewati ambang.
+// Mengembalikan jumlah pasangan yang belum dinyatakan bukan duplikat.
+func (s *AchievementService) detectDuplicates(ctx context.Context, ref *postgres.AchievementReference, content mongodb.Achievement) (int, error) {
+	members, err := s.Repo.FindMembers(ctx, ref.ID)
+	if err != nil {
+		return 0, err
+	}
+	studentIDs := []string{ref.StudentID}
+	for _, m := range members {
+		studentIDs = append(studentIDs, m.StudentID)
+	}
+	hashes := []string{}
+	for h := range attachmentHashes(content) {
+		hashes = append(hashes, h)
+	}
+
+	candidates, err := s.Repo.FindDuplicateCandidates(ctx, repository.DuplicateQuery{
+		MongoID:    ref.MongoAchievementID,
+		Hashes:     hashes,
+		EventID:    content.EventID,
+		StudentIDs: studentIDs,
+		Limit:      maxDuplicateCandidates,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	refs, err := s.Repo.FindRefsByMongoIDs(ctx, mongoIDsOfContents(candidates))
+	if err != nil {
+		return 0, err
+	}
+	refByMongoID := make(map[string]string, len(refs))
+	for _, r := range refs {
+		refByMongoID[r.MongoAchievementID] = r.ID
+	}
+
+	suspects := []repository.DuplicateSuspect{}
+	for _, cand := range candidates {
+		otherID, ok := refByMongoID[cand.ID.Hex()]
+		if !ok {
+			continue
+		}
+		if score, reasons := duplicateScore(content, cand); score >= duplicateThreshold {
+			suspects = append(suspects, repository.DuplicateSuspect{OtherID: otherID, Score: score, Reasons: reasons})
+		}
+	}
+	if err := s.Repo.UpsertDuplicates(ctx, ref.ID, suspects); err != nil {
+		return 0, err
+	}
+
+	flags, err := s.Repo.FindDuplicates(ctx, ref.ID)
+	if err != nil {
+		return 0, err
+	}
+	open := 0
+	for _, f := range flags {
+		if f.Decision != postgres.DuplicateDismissed {
+			open++
+		}
+	}
+	return open, nil
+}
+
+func mongoIDsOfContents(contents []mongodb.Achievement) []string {
+	ids := make([]string, 0, len(contents))
+	for _, c := range contents {
+		ids = append(ids, c.ID.Hex())
+	}
+	return ids
+}
+
+// checkDuplicates menjalankan deteksi tanpa menggagalkan request; error hanya dicatat.
+func (s *AchievementService) checkDuplicates(ctx context.Context, ref *postgres.AchievementReference, content *mongodb.Achievement) int {
+	if content == nil {
+		loaded, err := s.Repo.FindContentByMongoID(ctx, ref.MongoAchievementID)
+		if err != nil {
+			log.Printf("⚠️ Deteksi duplikat %s: %v", ref.ID, err)
+			return 0
+		}
+		content = loaded
+	}
+	n, err := s.detectDuplicates(ctx, ref, *content)
+	if err != nil {
+		log.Printf("⚠️ Deteksi duplikat %s: %v", ref.ID, err)
+	}
+	return n
+}
+
+// duplicateFlags memuat pasangan duplikat prestasi ref lengkap dengan judulnya.
+func (s *AchievementService) duplicateFlags(ctx context.Context, ref *postgres.AchievementReference) ([]postgres.DuplicateFlag, error) {
+	flags, err := s.Repo.FindDuplicates(ctx, ref.ID)
+	if err != nil || len(flags) == 0 {
+		return flags, err
+	}
+	mongoIDs := make([]string, 0, len(flags))
+	for _, f := range flags {
+		mongoIDs = append(mongoIDs, f.MongoAchievementID)
+	}
+	contents, err := s.Repo.FindContentByMongoIDs(ctx, mongoIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range flags {
+		flags[i].Title = contents[flags[i].MongoAchievementID].Title
+	}
+	return flags, nil
+}
+
+type DuplicateDecisionRequest struct {
+	Decision string `json:"decision" binding:"required,oneof=confirmed dismissed suspected" example:"dismissed"`
+	Notes    string `json:"notes" example:"Sertifikat berbeda, kegiatan berulang tiap tahun"`
+}
+
+// DecideDuplicate godoc
+// @Summary Mark Duplicate Decision (Dosen)
+// @Description Tandai pasangan prestasi sebagai duplikat (confirmed), bukan duplikat (dismissed; tidak ditandai lagi), atau kembalikan ke suspected.
+// @Tags Achievements
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param otherId path string true "ID prestasi pasangan"
+// @Param body body DuplicateDecisionRequest true "Keputusan"
+// @Success 200 {object} map[string]interface{}
+// @Router /achievements/{id}/duplicates/{otherId} [put]
+func (s *AchievementService) DecideDuplicate(c *gin.Context) {
+	var req DuplicateDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	ref, ok := s.loadRefForRead(c)
+	if !ok {
+		return
+	}
+	otherID := c.Param("otherId")
+	if _, err := uuid.Parse(otherID); err != nil {
+		c.JSON(404, gin.H{"error": "Pasangan duplikat tidak ditemukan"})
+		return
+	}
+
+	var notes *string
+	if strings.TrimSpace(req.Notes) != "" {
+		notes = &req.Notes
+	}
+	if err := s.Repo.DecideDuplicate(c.Request.Context(), ref.ID, otherID, req.Decision, c.GetString("user_id"), notes); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(404, gin.H{"error": "Pasangan duplikat tidak ditemukan"})
+			return
+		}
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	flags, err := s.duplicateFlags(c.Request.Context(), ref)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"message": "Duplicate decision saved", "data": flags})
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	mongodb "pelaporan_prestasi/app/models/mongo"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// contentETag membentuk ETag dari versi konten, mis. "v3".
+func contentETag(version int) string {
+	return `"v` + strconv.Itoa(version) + `"`
+}
+
+// checkIfMatch memeriksa header If-Match terhadap versi konten sekarang. Header kosong dianggap
+// cocok kecuali required (428); selain itu minimal satu ETag (atau "*") harus cocok, 412 bila tidak.
+func checkIfMatch(c *gin.Context, version int, required bool) bool {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	current := contentETag(version)
+	if header == "" && !required {
+		return true
+	}
+	if header == "" {
+		c.Header("ETag", current)
+		c.JSON(http.StatusPreconditionRequired, gin.H{"error": "Header If-Match wajib dikirim, ambil ETag dari GET detail"})
+		return false
+	}
+	if header == "*" {
+		return true
+	}
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == current {
+			return true
+		}
+	}
+	c.Header("ETag", current)
+	writePreconditionFailed(c, "Versi konten tidak cocok dengan If-Match, muat ulang data")
+	return false
+}
+
+func writePreconditionFailed(c *gin.Context, msg string) {
+	c.JSON(http.StatusPreconditionFailed, gin.H{"error": msg})
+}
+
+// mergePatch menerapkan JSON Merge Patch (RFC 7386): object digabung rekursif, null menghapus
+// key, nilai lain (termasuk array) menggantikan seluruhnya.
+func mergePatch(target, patch interface{}) interface{} {
+	patchObj, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObj, ok := target.(map[string]interface{})
+	if !ok {
+		targetObj = map[string]interface{}{}
+	}
+	for k, v := range patchObj {
+		if v == nil {
+			delete(targetObj, k)
+			continue
+		}
+		targetObj[k] = mergePatch(targetObj[k], v)
+	}
+	return targetObj
+}
+
+// contentDocument adalah representasi konten yang menjadi target merge patch, dengan nama field
+// yang sama seperti UpdateAchievementRequest.
+func contentDocument(content mongodb.Achievement) (map[string]interface{}, error) {
+	raw, err := json.Marshal(UpdateAchievementRequest{
+		Title:           content.Title,
+		Description:     content.Description,
+		AchievementType: content.AchievementType,
+		Tags:            content.Tags,
+		Details:         content.Details,
+		EventID:         content.EventID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	doc := map[string]interface{}{}
+	return doc, json.Unmarshal(raw, &doc)
+}
+
+// Patch godoc
+// @Summary Patch Achievement Content
+// @Description Update sebagian dengan JSON Merge Patch (RFC 7386): field yang tidak dikirim tidak berubah, null menghapus (mis. details.doi). Hasil akhir divalidasi dengan aturan yang sama seperti create. Wajib kirim If-Match berisi ETag dari GET detail (428 jika tidak ada); 412 jika konten sudah diubah orang lain.
+// @Tags Achievements
+// @Security BearerAuth
+// @Accept json
+// @Param id path string true "ID"
+// @Param If-Match header string true "ETag versi konten"
+// @Param body body object true "Merge patch, mis. {\"title\":\"Baru\",\"details\":{\"doi\":null}}"
+// @Success 200 {object} map[string]interface{}
+// @Failure 412 {object} map[string]interface{}
+// @Failure 428 {object} map[string]interface{}
+// @Router /achievements/{id} [patch]
+func (s *AchievementService) Patch(c *gin.Context) {
+	var patch map[string]interface{}
+	if err := json.NewDecoder(c.Request.Body).Decode(&patch); err != nil || patch == nil {
+		c.JSON(400, gin.H{"error": "Body harus berupa JSON object (merge patch)"})
+		return
+	}
+
+	// PATCH baru, jadi If-Match bisa langsung diwajibkan tanpa merusak klien lama
+	ref, content, ok := s.loadForEdit(c, true)
+	if !ok {
+		return
+	}
+
+	doc, err := contentDocument(*content)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	merged, err := json.Marshal(mergePatch(doc, patch))
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req UpdateAchievementRequest
+	if err := json.Unmarshal(merged, &req); err != nil {
+		c.JSON(400, gin.H{"error": "Tipe field tidak valid: " + err.Error()})
+		return
+	}
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	s.saveContent(c, ref, content, req)
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+
+	mongodb "pelaporan_prestasi/app/models/mongo"
+	"pelaporan_prestasi/app/models/postgres"
+	"pelaporan_prestasi/app/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FieldChange adalah satu perbedaan antara dua revisi. Field details ditulis "details.<nama>".
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+type RevisionDiff struct {
+	From    int           `json:"from"`
+	To      int           `json:"to"`
+	Changes []FieldChange `json:"changes"`
+}
+
+func revisionOf(content mongodb.Achievement, number int, changed []string, changedBy string) mongodb.AchievementRevision {
+	return mongodb.AchievementRevision{
+		AchievementID:   content.ID.Hex(),
+		Revision:        number,
+		Title:           content.Title,
+		Description:     content.Description,
+		AchievementType: content.AchievementType,
+		Tags:            content.Tags,
+		Details:         content.Details,
+		EventID:         content.EventID,
+		ChangedFields:   changed,
+		ChangedBy:       changedBy,
+	}
+}
+
+// contentFields meratakan field konten yang direvisi menjadi peta nama -> nilai.
+func contentFields(title, description, achievementType, eventID string, tags []string, details mongodb.AchievementDetails) map[string]interface{} {
+	if tags == nil {
+		tags = []string{}
+	}
+	fields := map[string]interface{}{
+		"title":           title,
+		"description":     description,
+		"achievementType": achievementType,
+		"tags":            tags,
+	}
+	if eventID != "" {
+		fields["eventId"] = eventID
+	}
+	for k, v := range details {
+		fields["details."+k] = v
+	}
+	return fields
+}
+
+// diffFields membandingkan dua peta field; nilai dibandingkan lewat JSON supaya primitive.A
+// hasil decode Mongo sama dengan []string hasil validasi.
+func diffFields(from, to map[string]interface{}) []FieldChange {
+	names := map[string]bool{}
+	for k := range from {
+		names[k] = true
+	}
+	for k := range to {
+		names[k] = true
+	}
+
+	changes := []FieldChange{}
+	for name := range names {
+		a, _ := json.Marshal(from[name])
+		b, _ := json.Marshal(to[name])
+		if !bytes.Equal(a, b) {
+			changes = append(changes, FieldChange{Field: name, From: from[name], To: to[name]})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+func achievementFields(a mongodb.Achievement) map[string]interface{} {
+	return contentFields(a.Title, a.Description, a.AchievementType, a.EventID, a.Tags, a.Details)
+}
+
+func revisionFields(r mongodb.AchievementRevision) map[string]interface{} {
+	return contentFields(r.Title, r.Description, r.AchievementType, r.EventID, r.Tags, r.Details)
+}
+
+func fieldNames(changes []FieldChange) []string {
+	names := make([]string, 0, len(changes))
+	for _, ch := range changes {
+		names = append(names, ch.Field)
+	}
+	return names
+}
+
+func changedContentFields(current, update mongodb.Achievement) []string {
+	return fieldNames(diffFields(achievementFields(current), achievementFields(update)))
+}
+
+func allContentFields(content mongodb.Achievement) []string {
+	return fieldNames(diffFields(map[string]interface{}{}, achievementFields(content)))
+}
+
+// loadRefForRead memuat referensi dan memeriksa hak baca; response error sudah ditulis jika ok=false.
+func (s *AchievementService) loadRefForRead(c *gin.Context) (*postgres.AchievementReference, bool) {
+	ref, err := s.Repo.FindRefByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(404, gin.H{"error": "Not found"})
+		return nil, false
+	}
+	if !s.canAccess(c, ref) {
+		c.JSON(403, gin.H{"error": "Forbidden"})
+		return nil, false
+	}
+	return ref, true
+}
+
+// GetRevisions godoc
+// @Summary List Content Revisions
+// @Description Semua revisi konten prestasi (terbaru dulu), masing-masing dengan field yang berubah.
+// @Tags Achievements
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /achievements/{id}/revisions [get]
+func (s *AchievementService) GetRevisions(c *gin.Context) {
+	ref, ok := s.loadRefForRead(c)
+	if !ok {
+		return
+	}
+	revs, err := s.Writer.Revisions.FindByAchievement(c.Request.Context(), ref.MongoAchievementID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"data": revs})
+}
+
+// GetRevision godoc
+// @Summary Get Content Revision
+// @Tags Achievements
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param rev path int true "Nomor revisi"
+// @Success 200 {object} mongodb.AchievementRevision
+// @Router /achievements/{id}/revisions/{rev} [get]
+func (s *AchievementService) GetRevision(c *gin.Context) {
+	number, err := strconv.Atoi(c.Param("rev"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Nomor revisi tidak valid"})
+		return
+	}
+	ref, ok := s.loadRefForRead(c)
+	if !ok {
+		return
+	}
+
+	rev, err := s.Writer.Revisions.FindOne(c.Request.Context(), ref.MongoAchievementID, number)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(404, gin.H{"error": "Revisi tidak ditemukan"})
+			return
+		}
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"data": rev})
+}
+
+// DiffRevisions godoc
+// @Summary Diff Two Content Revisions
+// @Tags Achievements
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param from query int true "Revisi awal"
+// @Param to query int true "Revisi akhir"
+// @Success 200 {object} RevisionDiff
+// @Router /achievements/{id}/revisions/diff [get]
+func (s *AchievementService) DiffRevisions(c *gin.Context) {
+	from, errFrom := strconv.Atoi(c.Query("from"))
+	to, errTo := strconv.Atoi(c.Query("to"))
+	if errFrom != nil || errTo != nil {
+		c.JSON(400, gin.H{"error": "Query from dan to wajib berupa nomor revisi"})
+		return
+	}
+	ref, ok := s.loadRefForRead(c)
+	if !ok {
+		return
+	}
+
+	revs := map[int]*mongodb.AchievementRevision{}
+	for _, n := range []int{from, to} {
+		rev, err := s.Writer.Revisions.FindOne(c.Request.Context(), ref.MongoAchievementID, n)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.JSON(404, gin.H{"error": "Revisi " + strconv.Itoa(n) + " tidak ditemukan"})
+				return
+			}
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		revs[n] = rev
+	}
+
+	c.JSON(200, gin.H{"data": RevisionDiff{
+		From:    from,
+		To:      to,
+		Changes: diffFields(revisionFields(*revs[from]), revisionFields(*revs[to])),
+	}})
+}
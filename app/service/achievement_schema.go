@@ -0,0 +1,271 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	mongodb "pelaporan_prestasi/app/models/mongo"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	FieldString     = "string"
+	FieldDate       = "date" // YYYY-MM-DD
+	FieldEnum       = "enum"
+	FieldStringList = "string_list"
+)
+
+// Level kompetisi, dipakai juga oleh rulebook poin.
+var CompetitionLevels = []string{"campus", "regional", "national", "international"}
+
+type DetailField struct {
+	Name     string   `json:"name"`
+	Label    string   `json:"label"`
+	Type     string   `json:"type"`
+	Required bool     `json:"required"`
+	Options  []string `json:"options,omitempty"`
+	Pattern  string   `json:"pattern,omitempty"`
+	// pattern adalah Pattern yang sudah dikompilasi oleh compileSchemas
+	pattern *regexp.Regexp
+	// Private: tidak ditampilkan di portofolio publik kecuali dibagikan eksplisit
+	Private bool `json:"private,omitempty"`
+}
+
+type DetailSchema struct {
+	Type   string        `json:"type"`
+	Label  string        `json:"label"`
+	Fields []DetailField `json:"fields"`
+	// DateOrder berisi pasangan [awal, akhir] yang akhir-nya tidak boleh sebelum awal.
+	DateOrder [][2]string `json:"-"`
+}
+
+var detailSchemas = compileSchemas(map[string]DetailSchema{
+	"competition": {
+		Type:  "competition",
+		Label: "Kompetisi / Lomba",
+		Fields: []DetailField{
+			{Name: "eventName", Label: "Nama Kegiatan", Type: FieldString, Required: true},
+			{Name: "level", Label: "Tingkat", Type: FieldEnum, Required: true, Options: CompetitionLevels},
+			{Name: "rank", Label: "Peringkat", Type: FieldEnum, Required: true, Options: []string{"juara_1", "juara_2", "juara_3", "harapan", "finalis", "peserta"}},
+			{Name: "organizer", Label: "Penyelenggara", Type: FieldString, Required: true},
+			{Name: "date", Label: "Tanggal", Type: FieldDate, Required: true},
+		},
+	},
+	"publication": {
+		Type:  "publication",
+		Label: "Publikasi Ilmiah",
+		Fields: []DetailField{
+			{Name: "journal", Label: "Jurnal / Prosiding", Type: FieldString, Required: true},
+			{Name: "doi", Label: "DOI", Type: FieldString, Pattern: `^10\.\d{4,9}/\S+$`},
+			{Name: "indexing", Label: "Indeksasi", Type: FieldEnum, Required: true, Options: []string{"scopus", "wos", "sinta_1", "sinta_2", "sinta_3", "sinta_4", "sinta_5", "sinta_6", "other"}},
+			{Name: "authors", Label: "Penulis", Type: FieldStringList, Required: true},
+			{Name: "publishedDate", Label: "Tanggal Terbit", Type: FieldDate},
+		},
+	},
+	"organization": {
+		Type:  "organization",
+		Label: "Organisasi",
+		Fields: []DetailField{
+			{Name: "organizationName", Label: "Nama Organisasi", Type: FieldString, Required: true},
+			{Name: "position", Label: "Jabatan", Type: FieldEnum, Required: true, Options: []string{"ketua", "wakil_ketua", "sekretaris", "bendahara", "koordinator", "anggota"}},
+			{Name: "periodStart", Label: "Mulai Periode", Type: FieldDate, Required: true},
+			{Name: "periodEnd", Label: "Akhir Periode", Type: FieldDate},
+		},
+		DateOrder: [][2]string{{"periodStart", "periodEnd"}},
+	},
+	"certification": {
+		Type:  "certification",
+		Label: "Sertifikasi",
+		Fields: []DetailField{
+			{Name: "issuer", Label: "Penerbit", Type: FieldString, Required: true},
+			{Name: "number", Label: "Nomor Sertifikat", Type: FieldString, Required: true, Private: true},
+			{Name: "issuedDate", Label: "Tanggal Terbit", Type: FieldDate},
+			{Name: "expiryDate", Label: "Berlaku Sampai", Type: FieldDate},
+		},
+		DateOrder: [][2]string{{"issuedDate", "expiryDate"}},
+	},
+})
+
+// compileSchemas mengompilasi Pattern setiap field sekali saat schema didefinisikan.
+func compileSchemas(schemas map[string]DetailSchema) map[string]DetailSchema {
+	for _, schema := range schemas {
+		for i := range schema.Fields {
+			if schema.Fields[i].Pattern != "" {
+				schema.Fields[i].pattern = regexp.MustCompile(schema.Fields[i].Pattern)
+			}
+		}
+	}
+	return schemas
+}
+
+// DetailValidationError berisi pesan error per field details.
+type DetailValidationError struct {
+	Fields map[string]string
+}
+
+func (e *DetailValidationError) Error() string {
+	keys := make([]string, 0, len(e.Fields))
+	for k := range e.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+": "+e.Fields[k])
+	}
+	return "details tidak valid (" + strings.Join(parts, "; ") + ")"
+}
+
+func FindDetailSchema(achievementType string) (DetailSchema, bool) {
+	schema, ok := detailSchemas[achievementType]
+	return schema, ok
+}
+
+// ValidateDetails memeriksa details terhadap schema achievementType dan mengembalikan versi yang sudah dinormalisasi.
+func ValidateDetails(achievementType string, details map[string]interface{}) (mongodb.AchievementDetails, error) {
+	schema, ok := FindDetailSchema(achievementType)
+	if !ok {
+		return nil, fmt.Errorf("achievement_type tidak dikenal: %s", achievementType)
+	}
+
+	errs := map[string]string{}
+	known := map[string]bool{}
+	out := mongodb.AchievementDetails{}
+
+	for _, f := range schema.Fields {
+		known[f.Name] = true
+		raw, present := details[f.Name]
+		if !present || raw == nil || raw == "" {
+			if f.Required {
+				errs[f.Name] = "wajib diisi"
+			}
+			continue
+		}
+
+		val, err := validateDetailField(f, raw)
+		if err != nil {
+			errs[f.Name] = err.Error()
+			continue
+		}
+		out[f.Name] = val
+	}
+
+	for key := range details {
+		if !known[key] {
+			errs[key] = "field tidak dikenal untuk tipe " + achievementType
+		}
+	}
+
+	for _, pair := range schema.DateOrder {
+		start, okStart := out[pair[0]].(string)
+		end, okEnd := out[pair[1]].(string)
+		if okStart && okEnd && end < start {
+			errs[pair[1]] = "tidak boleh sebelum " + pair[0]
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, &DetailValidationError{Fields: errs}
+	}
+	return out, nil
+}
+
+func validateDetailField(f DetailField, raw interface{}) (interface{}, error) {
+	switch f.Type {
+	case FieldString, FieldDate, FieldEnum:
+		str, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("harus berupa teks")
+		}
+		str = strings.TrimSpace(str)
+		switch f.Type {
+		case FieldDate:
+			if _, err := time.Parse("2006-01-02", str); err != nil {
+				return nil, fmt.Errorf("format tanggal harus YYYY-MM-DD")
+			}
+		case FieldEnum:
+			for _, opt := range f.Options {
+				if opt == str {
+					return str, nil
+				}
+			}
+			return nil, fmt.Errorf("harus salah satu dari %s", strings.Join(f.Options, ", "))
+		}
+		if f.pattern != nil && !f.pattern.MatchString(str) {
+			return nil, fmt.Errorf("format tidak valid")
+		}
+		return str, nil
+
+	case FieldStringList:
+		items, ok := raw.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("harus berupa daftar teks")
+		}
+		list := []string{}
+		for _, item := range items {
+			str, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("harus berupa daftar teks")
+			}
+			if str = strings.TrimSpace(str); str != "" {
+				list = append(list, str)
+			}
+		}
+		if f.Required && len(list) == 0 {
+			return nil, fmt.Errorf("wajib diisi")
+		}
+		return list, nil
+	}
+	return nil, fmt.Errorf("tipe field tidak dikenal")
+}
+
+// writeDetailError menulis 400 dengan rincian per field bila tersedia.
+func writeDetailError(c *gin.Context, err error) {
+	if verr, ok := err.(*DetailValidationError); ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Details tidak valid", "fields": verr.Fields})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+// GetSchemas godoc
+// @Summary List Achievement Detail Schemas
+// @Description Schema field details per achievement_type, untuk merender form secara dinamis.
+// @Tags Achievements
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Router /achievements/schemas [get]
+func (s *AchievementService) GetSchemas(c *gin.Context) {
+	types := make([]string, 0, len(detailSchemas))
+	for t := range detailSchemas {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	list := make([]DetailSchema, 0, len(types))
+	for _, t := range types {
+		list = append(list, detailSchemas[t])
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list})
+}
+
+// GetSchema godoc
+// @Summary Get Achievement Detail Schema
+// @Tags Achievements
+// @Security BearerAuth
+// @Param type path string true "achievement_type"
+// @Success 200 {object} DetailSchema
+// @Router /achievements/schemas/{type} [get]
+func (s *AchievementService) GetSchema(c *gin.Context) {
+	schema, ok := FindDetailSchema(c.Param("type"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Schema tidak ditemukan"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": schema})
+}
@@ -1,13 +1,15 @@
 package service
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
 	"pelaporan_prestasi/app/models/dto"
 	mongodb "pelaporan_prestasi/app/models/mongo"
 	"pelaporan_prestasi/app/models/postgres"
 	"pelaporan_prestasi/app/repository"
+	"pelaporan_prestasi/app/storage"
+	"pelaporan_prestasi/middleware"
 	"strings"
 	"time"
 
@@ -16,64 +18,249 @@ import (
 )
 
 type AchievementService struct {
-	Repo *repository.AchievementRepository
+	Repo   *repository.AchievementRepository
+	Writer *AchievementWriter
+	// Tasks menjalankan pekerjaan lanjutan VERIFIED yang tercatat di verification_tasks
+	Tasks    *VerificationTaskService
+	Files    *AttachmentFiles
+	Previews *PreviewService
+	Comments *repository.CommentRepository
+	Events   *repository.EventRepository
+	// Attestations membaca atestasi prestasi; penerbitannya lewat Tasks
+	Attestations *AttestationService
+	// TeamVerification adalah TeamVerificationSingle atau TeamVerificationPerAdvisor
+	TeamVerification string
+	Perms            *middleware.PermissionCache
 }
 
-func NewAchievementService(repo *repository.AchievementRepository) *AchievementService {
-	return &AchievementService{Repo: repo}
+func NewAchievementService(repo *repository.AchievementRepository, writer *AchievementWriter, tasks *VerificationTaskService, files *AttachmentFiles, previews *PreviewService, comments *repository.CommentRepository, events *repository.EventRepository, attestations *AttestationService, teamVerification string, perms *middleware.PermissionCache) *AchievementService {
+	return &AchievementService{Repo: repo, Writer: writer, Tasks: tasks, Files: files, Previews: previews, Comments: comments, Events: events, Attestations: attestations, TeamVerification: teamVerification, Perms: perms}
+}
+
+// attachmentStorageKey membuat key unik lampiran per mahasiswa, mis. achievements/<userID>/<uuid>.pdf.
+func attachmentStorageKey(studentID, ext string) string {
+	return fmt.Sprintf("achievements/%s/%s%s", studentID, uuid.New().String(), strings.ToLower(ext))
 }
 
 type CreateAchievementRequest struct {
 	Title           string   `form:"title" binding:"required"`
 	Description     string   `form:"description" binding:"required"`
 	AchievementType string   `form:"achievement_type" binding:"required"`
-	Points          int      `form:"points"`
 	Tags            []string `form:"tags"`
+	// Details berisi JSON object sesuai schema achievement_type (lihat GET /achievements/schemas)
+	Details string `form:"details"`
+	// Members berisi JSON array anggota tim lain [{student_id, role}]; kosong = prestasi individu
+	Members string `form:"members"`
+	// EventID menautkan prestasi ke katalog event (GET /events)
+	EventID string `form:"event_id"`
+}
+
+// UpdateAchievementRequest adalah body PUT /achievements/:id, juga hasil akhir PATCH setelah
+// merge patch diterapkan. Aturan wajib-isi sama dengan CreateAchievementRequest.
+type UpdateAchievementRequest struct {
+	Title           string                 `json:"title" binding:"required"`
+	Description     string                 `json:"description" binding:"required"`
+	AchievementType string                 `json:"achievement_type" binding:"required"`
+	Tags            []string               `json:"tags"`
+	Details         map[string]interface{} `json:"details"`
+	EventID         string                 `json:"event_id"`
 }
 
 type VerifyRequest struct {
-	Status string `json:"status" binding:"required" example:"VERIFIED"`
+	Status string `json:"status" binding:"required,oneof=VERIFIED REJECTED" example:"VERIFIED"`
 	Notes  string `json:"notes" example:"Oke bagus"`
 }
 
+type RejectRequest struct {
+	Notes string `json:"notes" binding:"required" example:"Sertifikat tidak terbaca"`
+}
+
+type AchievementListQuery struct {
+	Page            int      `form:"page"`
+	Limit           int      `form:"limit"`
+	Status          string   `form:"status"`
+	AchievementType string   `form:"achievement_type"`
+	EventID         string   `form:"event_id"`
+	Tags            []string `form:"tags"`
+	DateFrom        string   `form:"date_from"`
+	DateTo          string   `form:"date_to"`
+	ProgramStudy    string   `form:"program_study"`
+	MinPoints       *int     `form:"min_points"`
+	MaxPoints       *int     `form:"max_points"`
+	Sort            string   `form:"sort"`
+	Order           string   `form:"order"`
+}
+
+// achievementSortFields memetakan sort ke kolom achievement_references; updated_at adalah waktu ubah
+// konten (salinan updatedAt Mongo), bukan waktu perubahan status.
+var achievementSortFields = map[string]string{
+	"created_at": "ar.created_at",
+	"updated_at": "ar.content_updated_at",
+	"points":     "ar.points",
+	"title":      "ar.title",
+}
+
+// build memvalidasi query dan mengubahnya jadi filter + pagination Postgres.
+func (q *AchievementListQuery) build() (repository.RefFilter, repository.RefPage, error) {
+	var rf repository.RefFilter
+	var page repository.RefPage
+
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.Limit < 1 {
+		q.Limit = 20
+	}
+	if q.Limit > 100 {
+		q.Limit = 100
+	}
+
+	switch q.Status {
+	case "", StatusDraft, StatusPending, StatusVerified, StatusRejected:
+		rf.Status = q.Status
+	default:
+		return rf, page, fmt.Errorf("status tidak dikenal: %s", q.Status)
+	}
+	rf.ProgramStudy = q.ProgramStudy
+
+	if q.DateFrom != "" {
+		from, err := time.Parse("2006-01-02", q.DateFrom)
+		if err != nil {
+			return rf, page, fmt.Errorf("date_from harus YYYY-MM-DD")
+		}
+		rf.CreatedFrom = &from
+	}
+	if q.DateTo != "" {
+		to, err := time.Parse("2006-01-02", q.DateTo)
+		if err != nil {
+			return rf, page, fmt.Errorf("date_to harus YYYY-MM-DD")
+		}
+		to = to.AddDate(0, 0, 1)
+		rf.CreatedTo = &to
+	}
+
+	rf.AchievementType = q.AchievementType
+	rf.EventID = q.EventID
+	for _, tag := range q.Tags {
+		for _, t := range strings.Split(tag, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				rf.Tags = append(rf.Tags, t)
+			}
+		}
+	}
+	rf.MinPoints = q.MinPoints
+	rf.MaxPoints = q.MaxPoints
+
+	if q.Sort == "" {
+		q.Sort = "created_at"
+	}
+	field, ok := achievementSortFields[q.Sort]
+	if !ok {
+		return rf, page, fmt.Errorf("sort tidak dikenal: %s", q.Sort)
+	}
+	switch q.Order {
+	case "", "desc":
+		page.Desc = true
+	case "asc":
+	default:
+		return rf, page, fmt.Errorf("order harus asc atau desc")
+	}
+	page.SortColumn = field
+	page.Offset = int64((q.Page - 1) * q.Limit)
+	page.Limit = int64(q.Limit)
+
+	return rf, page, nil
+}
+
 // --- 1. LIST ---
 // GetList godoc
 // @Summary List Achievements
+// @Description Daftar prestasi yang boleh dilihat (semua dengan achievement:read_all; selain itu milik sendiri dan mahasiswa bimbingan), dengan pagination, filter dan sorting. Filter dan halaman dihitung di Postgres; "missing" berisi referensi di halaman ini yang kontennya hilang.
 // @Tags Achievements
 // @Security BearerAuth
-// @Success 200 {array} dto.AchievementResponse
+// @Param page query int false "Halaman (mulai 1)" default(1)
+// @Param limit query int false "Jumlah per halaman (maks 100)" default(20)
+// @Param status query string false "DRAFT | PENDING | VERIFIED | REJECTED"
+// @Param achievement_type query string false "Tipe prestasi"
+// @Param event_id query string false "Event katalog yang ditautkan"
+// @Param tags query []string false "Tag (semua harus cocok)" collectionFormat(multi)
+// @Param date_from query string false "Dibuat sejak (YYYY-MM-DD)"
+// @Param date_to query string false "Dibuat sampai (YYYY-MM-DD, inklusif)"
+// @Param program_study query string false "Program studi mahasiswa"
+// @Param min_points query int false "Poin minimum"
+// @Param max_points query int false "Poin maksimum"
+// @Param sort query string false "created_at | updated_at | points | title" default(created_at)
+// @Param order query string false "asc | desc" default(desc)
+// @Success 200 {object} dto.AchievementListResponse
 // @Router /achievements [get]
 func (s *AchievementService) GetList(c *gin.Context) {
-	roleID := c.GetString("role_id")
-	userID := c.GetString("user_id")
+	var q AchievementListQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	refFilter, page, err := q.build()
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
 
-	var refs []postgres.AchievementReference
-	var err error
+	if !s.Perms.Can(c, "achievement", "read_all") {
+		refFilter.VisibleTo = c.GetString("user_id")
+	}
 
-	if roleID == RoleMahasiswa {
-		refs, err = s.Repo.FindRefsByStudentID(c.Request.Context(), userID)
-	} else if roleID == RoleDosen {
-		refs, err = s.Repo.FindRefsByAdvisorID(c.Request.Context(), userID)
-	} else if roleID == RoleAdmin {
-		refs, err = s.Repo.FindAllRefs(c.Request.Context())
-	} else {
-		c.JSON(403, gin.H{"error": "Role invalid"})
+	refs, total, err := s.Repo.FindRefPage(c.Request.Context(), refFilter, page)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
 		return
 	}
 
+	// Referensi halaman ini yang tidak punya dokumen Mongo langsung dilaporkan di "missing"
+	contents, err := s.Repo.FindContentByMongoIDs(c.Request.Context(), mongoIDsOf(refs))
 	if err != nil {
 		c.JSON(500, gin.H{"error": err.Error()})
 		return
 	}
+	s.Files.SignAll(c.Request.Context(), contents)
+	results, missing := dto.ToAchievementResponses(refs, contents)
+
+	c.JSON(200, dto.AchievementListResponse{
+		Data:    results,
+		Missing: missing,
+		Meta: dto.PageMeta{
+			Page:       q.Page,
+			Limit:      q.Limit,
+			Total:      total,
+			TotalPages: int((total + int64(q.Limit) - 1) / int64(q.Limit)),
+		},
+	})
+}
 
-	var results []dto.AchievementResponse
+func mongoIDsOf(refs []postgres.AchievementReference) []string {
+	ids := make([]string, 0, len(refs))
 	for _, ref := range refs {
-		content, _ := s.Repo.FindContentByMongoID(c.Request.Context(), ref.MongoAchievementID)
-		if content != nil {
-			results = append(results, dto.ToAchievementResponse(ref, *content))
-		}
+		ids = append(ids, ref.MongoAchievementID)
+	}
+	return ids
+}
+
+// canAccess: achievement:read_all melihat semua prestasi; selain itu hanya prestasi yang diikuti
+// (sebagai pembuat atau anggota tim) atau yang salah satu anggotanya mahasiswa bimbingannya.
+func (s *AchievementService) canAccess(c *gin.Context, ref *postgres.AchievementReference) bool {
+	if s.Perms.Can(c, "achievement", "read_all") || s.isMember(c, ref) {
+		return true
 	}
-	c.JSON(200, gin.H{"data": results})
+	isAdvisor, _ := s.Repo.IsAdvisorOfRef(c.Request.Context(), ref.ID, c.GetString("user_id"))
+	return isAdvisor
+}
+
+// isMember: user login adalah pembuat atau anggota tim prestasi.
+func (s *AchievementService) isMember(c *gin.Context, ref *postgres.AchievementReference) bool {
+	if ref.StudentID == c.GetString("user_id") {
+		return true
+	}
+	isMember, _ := s.Repo.IsMemberOfRef(c.Request.Context(), ref.ID, c.GetString("user_id"))
+	return isMember
 }
 
 // --- 2. DETAIL ---
@@ -92,7 +279,7 @@ func (s *AchievementService) GetDetail(c *gin.Context) {
 		return
 	}
 
-	if c.GetString("role_id") == RoleMahasiswa && ref.StudentID != c.GetString("user_id") {
+	if !s.canAccess(c, ref) {
 		c.JSON(403, gin.H{"error": "Forbidden"})
 		return
 	}
@@ -103,63 +290,92 @@ func (s *AchievementService) GetDetail(c *gin.Context) {
 		return
 	}
 
-	c.JSON(200, gin.H{"data": dto.ToAchievementResponse(*ref, *content)})
+	members, err := s.Repo.FindMembers(c.Request.Context(), ref.ID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	s.Files.Sign(c.Request.Context(), content)
+	resp := dto.ToAchievementResponse(*ref, *content)
+	resp.Members = members
+	if s.Perms.Can(c, "achievement", "verify") {
+		resp.Duplicates, err = s.duplicateFlags(c.Request.Context(), ref)
+		if err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	c.Header("ETag", contentETag(content.CurrentVersion()))
+	c.JSON(200, gin.H{"data": resp})
 }
 
 // --- 3. CREATE ---
 // Create godoc
 // @Summary      Create Achievement with File
-// @Description  Kirim data + file sekaligus (Multipart Form)
+// @Description  Kirim data + file sekaligus (Multipart Form). possible_duplicates berisi jumlah kemungkinan duplikat (judul/event/tanggal mirip atau lampiran identik).
 // @Tags         Achievements
 // @Security     BearerAuth
 // @Accept       multipart/form-data
 // @Param        title formData string true "Judul"
 // @Param        description formData string true "Deskripsi"
 // @Param        achievement_type formData string true "Tipe"
-// @Param        points formData int false "Poin"
+// @Param        details formData string false "Details (JSON object sesuai schema achievement_type)"
+// @Param        event_id formData string false "Event katalog; untuk competition mengisi default eventName/organizer/level"
+// @Param        members formData string false "Anggota tim lain (JSON array [{student_id, role: ketua|anggota}])"
 // @Param        file formData file false "File Bukti (PDF/Image)"
 // @Success      201 {object} map[string]interface{}
 // @Router       /achievements [post]
 func (s *AchievementService) Create(c *gin.Context) {
-	if c.GetString("role_id") != RoleMahasiswa {
-		c.JSON(403, gin.H{"error": "Hanya mahasiswa yang boleh upload"})
-		return
-	}
+	s.Files.LimitBody(c)
 
 	var req CreateAchievementRequest
 	if err := c.ShouldBind(&req); err != nil {
+		if uerr, ok := s.Files.bodyTooLarge(err); ok {
+			writeUploadError(c, uerr)
+			return
+		}
 		c.JSON(400, gin.H{"error": "Input Salah: " + err.Error()})
 		return
 	}
 
-	var attachments []mongodb.Attachment
-	file, errFile := c.FormFile("file") 
-
-	if errFile == nil { 
-		ext := strings.ToLower(filepath.Ext(file.Filename))
-		if ext != ".pdf" && ext != ".jpg" && ext != ".jpeg" && ext != ".png" {
-			c.JSON(400, gin.H{"error": "Format file harus PDF atau Gambar!"})
+	rawDetails := map[string]interface{}{}
+	if req.Details != "" {
+		if err := json.Unmarshal([]byte(req.Details), &rawDetails); err != nil {
+			c.JSON(400, gin.H{"error": "details harus berupa JSON object"})
 			return
 		}
-
-		uploadDir := "uploads"
-		if _, err := os.Stat(uploadDir); os.IsNotExist(err) {
-			os.Mkdir(uploadDir, 0755)
+		// details "null" menghasilkan map nil
+		if rawDetails == nil {
+			rawDetails = map[string]interface{}{}
 		}
+	}
+	if err := applyEvent(c.Request.Context(), s.Events, req.EventID, req.AchievementType, rawDetails); err != nil {
+		writeApplyEventError(c, err)
+		return
+	}
+	details, err := ValidateDetails(req.AchievementType, rawDetails)
+	if err != nil {
+		writeDetailError(c, err)
+		return
+	}
+	members, err := parseMembersForm(c.GetString("user_id"), req.Members)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	var attachments []mongodb.Attachment
+	file, errFile := c.FormFile("file")
 
-		filename := fmt.Sprintf("%s_%s%s", c.GetString("user_id"), uuid.New().String(), ext)
-		savePath := filepath.Join(uploadDir, filename)
-		if err := c.SaveUploadedFile(file, savePath); err != nil {
-			c.JSON(500, gin.H{"error": "Gagal simpan file"})
+	if errFile == nil {
+		att, err := s.Files.Accept(c.Request.Context(), file, c.GetString("user_id"), 0)
+		if err != nil {
+			writeUploadError(c, err)
 			return
 		}
-
-		attachments = append(attachments, mongodb.Attachment{
-			FileName:   file.Filename,
-			FileURL:    savePath,
-			FileType:   ext,
-			UploadedAt: time.Now(),
-		})
+		att.Preview = pendingPreview()
+		attachments = append(attachments, att)
 	}
 
 	mongoData := mongodb.Achievement{
@@ -168,82 +384,113 @@ func (s *AchievementService) Create(c *gin.Context) {
 		Description:     req.Description,
 		AchievementType: req.AchievementType,
 		Tags:            req.Tags,
-		Points:          req.Points,
-		Attachments:     attachments,                  
-		Details:         make(map[string]interface{}), 
-	}
-
-	mongoID, err := s.Repo.InsertMongo(c.Request.Context(), &mongoData)
-	if err != nil {
-		c.JSON(500, gin.H{"error": "Mongo Error: " + err.Error()})
-		return
+		Attachments:     attachments,
+		Details:         details,
+		EventID:         req.EventID,
 	}
 
 	pgRef := postgres.AchievementReference{
-		StudentID:          c.GetString("user_id"),
-		MongoAchievementID: mongoID,
-	}
-	if err := s.Repo.InsertPostgres(c.Request.Context(), &pgRef); err != nil {
-		c.JSON(500, gin.H{"error": "Postgres Reference Error: " + err.Error()})
-		return
+		StudentID: c.GetString("user_id"),
 	}
-
 	hist := postgres.AchievementHistory{
-		AchievementID:  pgRef.ID,
 		ChangedBy:      c.GetString("user_id"),
 		PreviousStatus: "NONE",
-		NewStatus:      "DRAFT",
+		NewStatus:      StatusDraft,
 		Remarks:        "Created with file",
 	}
-	if err := s.Repo.AddHistory(c.Request.Context(), hist); err != nil {
-		fmt.Printf("⚠️ Gagal catat history: %v\n", err)
+	if err := s.Writer.Create(c.Request.Context(), &mongoData, &pgRef, hist, members); err != nil {
+		if errors.Is(err, repository.ErrNotStudent) {
+			writeMembersError(c, err)
+			return
+		}
+		c.JSON(500, gin.H{"error": "Gagal menyimpan prestasi: " + err.Error()})
+		return
 	}
+	s.Previews.Enqueue(pgRef.MongoAchievementID, attachments...)
+	duplicates := s.checkDuplicates(c.Request.Context(), &pgRef, &mongoData)
 
 	c.JSON(201, gin.H{
-		"status":  "success",
-		"message": "Prestasi berhasil dibuat + File terupload",
-		"id":      pgRef.ID,
+		"status":              "success",
+		"message":             "Prestasi berhasil dibuat + File terupload",
+		"id":                  pgRef.ID,
+		"possible_duplicates": duplicates,
 	})
 }
 
 // --- 4. UPDATE ---
 // Update godoc
 // @Summary Update Achievement Content
+// @Description Ganti seluruh konten. If-Match opsional: jika dikirim (ETag dari GET detail) dan konten sudah diubah orang lain, update ditolak dengan 412.
 // @Tags Achievements
 // @Security BearerAuth
 // @Param id path string true "ID"
-// @Param body body CreateAchievementRequest true "Body"
+// @Param If-Match header string false "ETag versi konten"
+// @Param body body UpdateAchievementRequest true "Body"
+// @Success 200 {object} map[string]interface{}
+// @Failure 412 {object} map[string]interface{}
 // @Router /achievements/{id} [put]
 func (s *AchievementService) Update(c *gin.Context) {
-	id := c.Param("id")
-	ref, err := s.Repo.FindRefByID(c.Request.Context(), id)
+	ref, content, ok := s.loadForEdit(c, false)
+	if !ok {
+		return
+	}
+
+	var req UpdateAchievementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	s.saveContent(c, ref, content, req)
+}
+
+// loadForEdit memuat ref + konten yang boleh diedit dan memeriksa If-Match (wajib jika
+// requireIfMatch); response error sudah ditulis jika ok=false.
+func (s *AchievementService) loadForEdit(c *gin.Context, requireIfMatch bool) (*postgres.AchievementReference, *mongodb.Achievement, bool) {
+	ref, err := s.Repo.FindRefByID(c.Request.Context(), c.Param("id"))
 	if err != nil {
 		c.JSON(404, gin.H{"error": "Not found"})
-		return
+		return nil, nil, false
 	}
 
-	if c.GetString("role_id") == RoleMahasiswa {
+	// achievement:manage_all boleh mengubah prestasi siapa pun di status apa pun
+	if !s.Perms.Can(c, "achievement", "manage_all") {
 		if ref.StudentID != c.GetString("user_id") {
 			c.JSON(403, gin.H{"error": "Forbidden"})
-			return
+			return nil, nil, false
 		}
-		if ref.Status != "DRAFT" && ref.Status != "REJECTED" {
+		if ref.Status != StatusDraft && ref.Status != StatusRejected {
 			c.JSON(400, gin.H{"error": "Locked"})
-			return
+			return nil, nil, false
 		}
 	}
 
-	type UpdateReq struct {
-		Title           string   `json:"title"`
-		Description     string   `json:"description"`
-		AchievementType string   `json:"achievement_type"`
-		Points          int      `json:"points"`
-		Tags            []string `json:"tags"`
+	content, err := s.Repo.FindContentByMongoID(c.Request.Context(), ref.MongoAchievementID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Content missing"})
+		return nil, nil, false
 	}
+	if !checkIfMatch(c, content.CurrentVersion(), requireIfMatch) {
+		return nil, nil, false
+	}
+	return ref, content, true
+}
 
-	var req UpdateReq
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(400, gin.H{"error": err.Error()})
+// saveContent memvalidasi req dengan aturan create lalu menyimpannya sebagai revisi baru.
+func (s *AchievementService) saveContent(c *gin.Context, ref *postgres.AchievementReference, content *mongodb.Achievement, req UpdateAchievementRequest) {
+	if req.Details == nil {
+		req.Details = map[string]interface{}{}
+	}
+	if err := applyEvent(c.Request.Context(), s.Events, req.EventID, req.AchievementType, req.Details); err != nil {
+		writeApplyEventError(c, err)
+		return
+	}
+	var details mongodb.AchievementDetails
+	var err error
+	if _, known := FindDetailSchema(req.AchievementType); !known && req.AchievementType == content.AchievementType {
+		// Prestasi lama bertipe di luar schema tetap bisa disunting selama tipenya tidak diganti
+		details = mongodb.AchievementDetails(req.Details)
+	} else if details, err = ValidateDetails(req.AchievementType, req.Details); err != nil {
+		writeDetailError(c, err)
 		return
 	}
 
@@ -252,15 +499,35 @@ func (s *AchievementService) Update(c *gin.Context) {
 		Description:     req.Description,
 		AchievementType: req.AchievementType,
 		Tags:            req.Tags,
-		Points:          req.Points,
+		Details:         details,
+		EventID:         req.EventID,
+	}
+	if updateData.Tags == nil {
+		updateData.Tags = []string{}
+	}
+
+	rev, err := s.Writer.UpdateContent(c.Request.Context(), *content, updateData, c.GetString("user_id"))
+	if err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			writePreconditionFailed(c, "Prestasi sudah diubah bersamaan, muat ulang data")
+			return
+		}
+		c.JSON(500, gin.H{"error": "Gagal update: " + err.Error()})
+		return
+	}
+	if rev == nil {
+		c.Header("ETag", contentETag(content.CurrentVersion()))
+		c.JSON(200, gin.H{"message": "No changes", "version": content.CurrentVersion()})
+		return
 	}
-	s.Repo.UpdateContentMongo(c.Request.Context(), ref.MongoAchievementID, updateData)
-	c.JSON(200, gin.H{"message": "Updated"})
+	c.Header("ETag", contentETag(rev.Revision))
+	c.JSON(200, gin.H{"message": "Updated", "version": rev.Revision, "changed_fields": rev.ChangedFields})
 }
 
 // --- 5. DELETE ---
 // Delete godoc
 // @Summary Delete Achievement
+// @Description Memindahkan prestasi DRAFT ke tong sampah; bisa dipulihkan selama masa retensi.
 // @Tags Achievements
 // @Security BearerAuth
 // @Param id path string true "ID"
@@ -277,21 +544,30 @@ func (s *AchievementService) Delete(c *gin.Context) {
 		c.JSON(403, gin.H{"error": "Forbidden"})
 		return
 	}
-	if ref.Status != "DRAFT" {
+	if ref.Status != StatusDraft {
 		c.JSON(400, gin.H{"error": "Locked"})
 		return
 	}
 
-	s.Repo.DeleteRef(c.Request.Context(), id)
-	c.JSON(200, gin.H{"message": "Deleted"})
+	if err := s.Writer.Trash(c.Request.Context(), ref, c.GetString("user_id")); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(404, gin.H{"error": "Not found"})
+			return
+		}
+		c.JSON(500, gin.H{"error": "Gagal menghapus prestasi"})
+		return
+	}
+	c.JSON(200, gin.H{"message": "Moved to trash"})
 }
 
 // --- 6. SUBMIT ---
 // Submit godoc
 // @Summary Submit for Verification
+// @Description DRAFT atau REJECTED -> PENDING. Status lain menghasilkan 409. possible_duplicates berisi jumlah kemungkinan duplikat yang terdeteksi.
 // @Tags Achievements
 // @Security BearerAuth
 // @Param id path string true "ID"
+// @Failure 409 {object} map[string]string
 // @Router /achievements/{id}/submit [post]
 func (s *AchievementService) Submit(c *gin.Context) {
 	id := c.Param("id")
@@ -305,77 +581,226 @@ func (s *AchievementService) Submit(c *gin.Context) {
 		return
 	}
 
-	s.Repo.UpdateStatus(c.Request.Context(), id, "PENDING", nil)
-	s.Repo.AddHistory(c.Request.Context(), postgres.AchievementHistory{AchievementID: id, ChangedBy: c.GetString("user_id"), PreviousStatus: ref.Status, NewStatus: "PENDING", Remarks: "Submitted"})
-	c.JSON(200, gin.H{"message": "Submitted"})
+	remarks := "Submitted"
+	if ref.Status == StatusRejected {
+		remarks = "Resubmitted"
+	}
+	if err := s.changeStatus(c, ref, StatusPending, remarks, nil); err != nil {
+		writeTransitionError(c, err)
+		return
+	}
+	duplicates := s.checkDuplicates(c.Request.Context(), ref, nil)
+	c.JSON(200, gin.H{"message": "Submitted", "possible_duplicates": duplicates})
 }
 
 // --- 7. VERIFY ---
 // Verify godoc
 // @Summary Verify Achievement (Dosen)
+// @Description PENDING -> VERIFIED atau REJECTED. Status lain menghasilkan 409. Untuk prestasi tim pada mode per_advisor, VERIFIED baru berlaku setelah semua dosen wali anggota menyetujui (pending_advisors > 0 selama menunggu).
 // @Tags Achievements
 // @Security BearerAuth
 // @Param id path string true "ID"
 // @Param body body VerifyRequest true "Body"
+// @Failure 409 {object} map[string]string
 // @Router /achievements/{id}/verify [post]
 func (s *AchievementService) Verify(c *gin.Context) {
-	id := c.Param("id")
-	dosenID := c.GetString("user_id")
-
-	isAdvisor, _ := s.Repo.IsAdvisorOfRef(c.Request.Context(), id, dosenID)
-	if c.GetString("role_id") == RoleAdmin {
-		isAdvisor = true
-	}
-	if !isAdvisor {
-		c.JSON(403, gin.H{"error": "Forbidden"})
-		return
-	}
-
 	var req VerifyRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(400, gin.H{"error": err.Error()})
 		return
 	}
-
-	ref, _ := s.Repo.FindRefByID(c.Request.Context(), id)
-	if req.Status == "VERIFIED" {
-		s.Repo.UpdateVerified(c.Request.Context(), id, dosenID)
-	} else {
-		s.Repo.UpdateStatus(c.Request.Context(), id, "REJECTED", &req.Notes)
-	}
-	s.Repo.AddHistory(c.Request.Context(), postgres.AchievementHistory{AchievementID: id, ChangedBy: dosenID, PreviousStatus: ref.Status, NewStatus: req.Status, Remarks: req.Notes})
-	c.JSON(200, gin.H{"message": "Status updated"})
+	s.decide(c, req.Status, req.Notes)
 }
 
 // --- 8. REJECT ---
 // Reject godoc
 // @Summary Reject Achievement (Dosen)
+// @Description PENDING -> REJECTED dengan catatan wajib.
 // @Tags Achievements
 // @Security BearerAuth
 // @Param id path string true "ID"
-// @Param body body VerifyRequest true "Body"
+// @Param body body RejectRequest true "Body"
+// @Failure 409 {object} map[string]string
 // @Router /achievements/{id}/reject [post]
 func (s *AchievementService) Reject(c *gin.Context) {
-	s.Verify(c)
+	var req RejectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	s.decide(c, StatusRejected, req.Notes)
+}
+
+var (
+	errDecisionNotFound  = errors.New("achievement not found")
+	errDecisionForbidden = errors.New("not the advisor of this achievement")
+)
+
+// decisionResult adalah hasil keputusan atas satu prestasi. PendingAdvisors > 0 berarti persetujuan
+// sudah dicatat tetapi prestasi masih PENDING menunggu dosen wali anggota tim lainnya.
+type decisionResult struct {
+	Status          string
+	PendingAdvisors int
+}
+
+// decide menjalankan keputusan verifikasi dosen/admin atas prestasi :id.
+func (s *AchievementService) decide(c *gin.Context, status, notes string) {
+	res, err := s.decideRef(c, c.Param("id"), status, notes)
+	if err != nil {
+		c.JSON(decisionErrorResponse(err))
+		return
+	}
+	if res.PendingAdvisors > 0 {
+		c.JSON(200, gin.H{"message": "Approval recorded, waiting for other advisors", "status": res.Status, "pending_advisors": res.PendingAdvisors})
+		return
+	}
+	c.JSON(200, gin.H{"message": "Status updated", "status": res.Status})
+}
+
+// decideRef memeriksa hak dosen wali (atau achievement:verify_all) atas satu prestasi lalu menjalankan
+// transisinya. Pada mode per_advisor, verifikasi oleh dosen wali hanya dicatat sebagai persetujuan sampai
+// semua dosen wali anggota tim setuju; penolakan dan keputusan verify_all selalu langsung berlaku.
+func (s *AchievementService) decideRef(c *gin.Context, id, status, notes string) (decisionResult, error) {
+	res := decisionResult{Status: status}
+	ref, err := s.Repo.FindRefByID(c.Request.Context(), id)
+	if err != nil {
+		return res, errDecisionNotFound
+	}
+
+	verifyAll := s.Perms.Can(c, "achievement", "verify_all")
+	if !verifyAll {
+		isAdvisor, _ := s.Repo.IsAdvisorOfRef(c.Request.Context(), id, c.GetString("user_id"))
+		if !isAdvisor {
+			return res, errDecisionForbidden
+		}
+	}
+
+	if status == StatusVerified && !verifyAll && s.TeamVerification == TeamVerificationPerAdvisor {
+		if err := CheckTransition(ref.Status, status, s.can(c)); err != nil {
+			return res, err
+		}
+		var note *string
+		if notes != "" {
+			note = &notes
+		}
+		res.PendingAdvisors, err = s.Repo.ApproveAsAdvisor(c.Request.Context(), repository.StatusChange{
+			RefID:     ref.ID,
+			ChangedBy: c.GetString("user_id"),
+			Remarks:   notes,
+			Note:      note,
+		})
+		if err != nil {
+			return res, err
+		}
+		if res.PendingAdvisors > 0 {
+			res.Status = StatusPending
+			return res, nil
+		}
+	} else {
+		var note *string
+		if status == StatusRejected {
+			note = &notes
+		}
+		if err := s.changeStatus(c, ref, status, notes, note); err != nil {
+			return res, err
+		}
+	}
+
+	if status == StatusVerified {
+		// Hitung poin dan atestasi tercatat di verification_tasks bersama status; yang gagal diulang VerificationTaskService
+		s.Tasks.RunFor(c.Request.Context(), ref.ID)
+	}
+	return res, nil
+}
+
+func decisionErrorResponse(err error) (int, gin.H) {
+	switch {
+	case errors.Is(err, errDecisionNotFound):
+		return 404, gin.H{"error": "Not found"}
+	case errors.Is(err, errDecisionForbidden):
+		return 403, gin.H{"error": "Forbidden"}
+	default:
+		return transitionErrorResponse(err)
+	}
+}
+
+// can mengikat pengecekan permission ke user yang sedang login.
+func (s *AchievementService) can(c *gin.Context) PermissionChecker {
+	return func(resource, action string) bool { return s.Perms.Can(c, resource, action) }
+}
+
+// changeStatus memvalidasi transisi lewat state machine lalu menyimpannya bersama history.
+func (s *AchievementService) changeStatus(c *gin.Context, ref *postgres.AchievementReference, to, remarks string, note *string) error {
+	if err := CheckTransition(ref.Status, to, s.can(c)); err != nil {
+		return err
+	}
+	return s.Repo.TransitionStatus(c.Request.Context(), repository.StatusChange{
+		RefID:     ref.ID,
+		From:      ref.Status,
+		To:        to,
+		ChangedBy: c.GetString("user_id"),
+		Remarks:   remarks,
+		Note:      note,
+	})
+}
+
+// GetAttestation godoc
+// @Summary Get Achievement Attestation
+// @Description Atestasi bertanda tangan terbaru prestasi (aktif atau sudah dicabut) beserta payload QR dan URL verifikasi publik.
+// @Tags Attestations
+// @Security BearerAuth
+// @Param id path string true "ID prestasi"
+// @Success 200 {object} AttestationResponse
+// @Router /achievements/{id}/attestation [get]
+func (s *AchievementService) GetAttestation(c *gin.Context) {
+	ref, ok := s.loadRefForRead(c)
+	if !ok {
+		return
+	}
+	s.Attestations.writeLatest(c, ref)
 }
 
 // --- 9. HISTORY ---
 // GetHistory godoc
 // @Summary Get History
+// @Description Timeline prestasi: perubahan status, revisi konten dan komentar review (terbaru dulu).
 // @Tags Achievements
 // @Security BearerAuth
 // @Param id path string true "ID"
+// @Success 200 {object} map[string]interface{}
 // @Router /achievements/{id}/history [get]
 func (s *AchievementService) GetHistory(c *gin.Context) {
-	id := c.Param("id")
-	hist, _ := s.Repo.GetHistory(c.Request.Context(), id)
-	c.JSON(200, gin.H{"data": hist})
+	ref, ok := s.loadRefForRead(c)
+	if !ok {
+		return
+	}
+
+	hist, err := s.Repo.GetHistory(c.Request.Context(), ref.ID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	revs, err := s.Writer.Revisions.FindByAchievement(c.Request.Context(), ref.MongoAchievementID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	comments, err := s.Comments.FindByAchievement(c.Request.Context(), ref.ID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"data": dto.MergeTimeline(
+		dto.ToStatusTimeline(hist),
+		dto.ToRevisionTimeline(revs),
+		dto.ToCommentTimeline(comments),
+	)})
 }
 
 // --- 10. UPLOAD ---
 // UploadAttachment godoc
 // @Summary Upload Attachment
-// @Description Upload file bukti (bisa berkali-kali).
+// @Description Upload file bukti (bisa berkali-kali) oleh anggota tim selama status DRAFT atau REJECTED. Isi file diperiksa (PDF/JPG/PNG asli, bukan polyglot), dibatasi ukurannya, dan dipindai sebelum dilampirkan.
 // @Tags Achievements
 // @Security BearerAuth
 // @Accept multipart/form-data
@@ -389,35 +814,144 @@ func (s *AchievementService) UploadAttachment(c *gin.Context) {
 		c.JSON(404, gin.H{"error": "Not found"})
 		return
 	}
-	if c.GetString("role_id") == RoleMahasiswa && ref.StudentID != c.GetString("user_id") {
+	// Hanya anggota tim (mahasiswa) yang boleh menambah bukti; dosen wali cukup membaca
+	if !s.isMember(c, ref) {
 		c.JSON(403, gin.H{"error": "Forbidden"})
 		return
 	}
+	if ref.Status != StatusDraft && ref.Status != StatusRejected {
+		c.JSON(409, gin.H{"error": "Lampiran hanya bisa ditambah saat status DRAFT atau REJECTED", "status": ref.Status})
+		return
+	}
 
+	s.Files.LimitBody(c)
 	file, err := c.FormFile("file")
 	if err != nil {
+		if uerr, ok := s.Files.bodyTooLarge(err); ok {
+			writeUploadError(c, uerr)
+			return
+		}
 		c.JSON(400, gin.H{"error": "File missing"})
 		return
 	}
 
-	filename := fmt.Sprintf("%s_%s%s", id, uuid.New().String(), filepath.Ext(file.Filename))
-	savePath := filepath.Join("uploads", filename)
-	if err := c.SaveUploadedFile(file, savePath); err != nil {
-		c.JSON(500, gin.H{"error": "Save failed"})
+	content, err := s.Repo.FindContentByMongoID(c.Request.Context(), ref.MongoAchievementID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Content missing"})
 		return
 	}
 
-	newAttachment := mongodb.Attachment{
-		FileName:   file.Filename,
-		FileURL:    savePath,
-		FileType:   filepath.Ext(file.Filename),
-		UploadedAt: time.Now(),
+	newAttachment, err := s.Files.Accept(c.Request.Context(), file, ref.StudentID, attachmentsSize(content.Attachments))
+	if err != nil {
+		writeUploadError(c, err)
+		return
 	}
+	newAttachment.Preview = pendingPreview()
 
 	if err := s.Repo.AddAttachmentMongo(c.Request.Context(), ref.MongoAchievementID, newAttachment); err != nil {
+		s.Files.Remove([]mongodb.Attachment{newAttachment})
 		c.JSON(500, gin.H{"error": "DB Update failed"})
 		return
 	}
+	syncListSummary(c.Request.Context(), s.Repo, ref.MongoAchievementID)
+	s.Previews.Enqueue(ref.MongoAchievementID, newAttachment)
+	if url, err := s.Files.Store.SignedURL(c.Request.Context(), newAttachment.StorageKey, file.Filename, s.Files.URLTTL); err == nil {
+		newAttachment.FileURL = url
+	}
 
 	c.JSON(200, gin.H{"message": "File attached", "data": newAttachment})
 }
+
+// loadAttachment memuat ref + lampiran attId setelah memeriksa hak akses lewat allowed; response error sudah ditulis jika ok=false.
+func (s *AchievementService) loadAttachment(c *gin.Context, allowed func(*gin.Context, *postgres.AchievementReference) bool) (*postgres.AchievementReference, mongodb.Attachment, bool) {
+	ref, err := s.Repo.FindRefByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(404, gin.H{"error": "Not found"})
+		return nil, mongodb.Attachment{}, false
+	}
+	if !allowed(c, ref) {
+		c.JSON(403, gin.H{"error": "Forbidden"})
+		return nil, mongodb.Attachment{}, false
+	}
+
+	content, err := s.Repo.FindContentByMongoID(c.Request.Context(), ref.MongoAchievementID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Content missing"})
+		return nil, mongodb.Attachment{}, false
+	}
+	att, ok := findAttachment(content, c.Param("attId"))
+	if !ok {
+		c.JSON(404, gin.H{"error": "Lampiran tidak ditemukan"})
+		return nil, mongodb.Attachment{}, false
+	}
+	return ref, att, true
+}
+
+// --- 11. DOWNLOAD ATTACHMENT ---
+// GetAttachment godoc
+// @Summary Download Attachment
+// @Description Stream file lampiran. Aturan akses sama dengan detail prestasi.
+// @Tags Achievements
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param attId path string true "Attachment ID"
+// @Param inline query bool false "Tampilkan inline (preview) alih-alih unduh"
+// @Success 200 {file} file
+// @Router /achievements/{id}/attachments/{attId} [get]
+func (s *AchievementService) GetAttachment(c *gin.Context) {
+	_, att, ok := s.loadAttachment(c, s.canAccess)
+	if !ok {
+		return
+	}
+
+	rc, err := s.Files.Store.Open(c.Request.Context(), attachmentKey(att))
+	if err != nil {
+		if errors.Is(err, storage.ErrFileNotFound) {
+			c.JSON(404, gin.H{"error": "File lampiran hilang dari storage"})
+			return
+		}
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer rc.Close()
+
+	disposition := "attachment"
+	if c.Query("inline") == "true" {
+		disposition = "inline"
+	}
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.DataFromReader(200, -1, attachmentContentType(att), rc, map[string]string{
+		"Content-Disposition": contentDisposition(disposition, att.FileName),
+	})
+}
+
+// --- 12. DELETE ATTACHMENT ---
+// DeleteAttachment godoc
+// @Summary Delete Attachment
+// @Description Hanya untuk pembuat atau anggota tim, selama prestasi berstatus DRAFT atau REJECTED.
+// @Tags Achievements
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param attId path string true "Attachment ID"
+// @Success 200 {object} map[string]string
+// @Router /achievements/{id}/attachments/{attId} [delete]
+func (s *AchievementService) DeleteAttachment(c *gin.Context) {
+	// Hak baca (mis. dosen wali) tidak cukup untuk menghapus bukti
+	ref, att, ok := s.loadAttachment(c, s.isMember)
+	if !ok {
+		return
+	}
+	if ref.Status != StatusDraft && ref.Status != StatusRejected {
+		c.JSON(409, gin.H{"error": "Lampiran hanya bisa dihapus saat status DRAFT atau REJECTED", "status": ref.Status})
+		return
+	}
+
+	if err := s.Repo.RemoveAttachmentMongo(c.Request.Context(), ref.MongoAchievementID, att); err != nil {
+		c.JSON(500, gin.H{"error": "DB Update failed"})
+		return
+	}
+	syncListSummary(c.Request.Context(), s.Repo, ref.MongoAchievementID)
+	s.Files.Remove([]mongodb.Attachment{att})
+
+	c.JSON(200, gin.H{"message": "Attachment deleted"})
+}
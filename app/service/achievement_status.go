@@ -0,0 +1,89 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"pelaporan_prestasi/app/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	StatusDraft    = "DRAFT"
+	StatusPending  = "PENDING"
+	StatusVerified = "VERIFIED"
+	StatusRejected = "REJECTED"
+)
+
+type statusTransition struct {
+	From string
+	To   string
+}
+
+// achievementTransitions adalah state machine status prestasi beserta permission achievement:<action>
+// yang dibutuhkan untuk menjalankannya:
+//
+//	DRAFT ──submit──▶ PENDING ──verify──▶ VERIFIED
+//	                     │
+//	                     └──reject──▶ REJECTED ──resubmit──▶ PENDING
+var achievementTransitions = map[statusTransition]string{
+	{StatusDraft, StatusPending}:    "submit",
+	{StatusRejected, StatusPending}: "submit",
+	{StatusPending, StatusVerified}: "verify",
+	{StatusPending, StatusRejected}: "verify",
+}
+
+var (
+	ErrIllegalTransition    = errors.New("illegal status transition")
+	ErrTransitionNotAllowed = errors.New("permission not granted for transition")
+)
+
+// PermissionChecker mengecek permission user login, mis. PermissionCache.Can yang sudah diikat ke request.
+type PermissionChecker func(resource, action string) bool
+
+// TransitionError membungkus ErrIllegalTransition / ErrTransitionNotAllowed dengan konteks status.
+type TransitionError struct {
+	From string
+	To   string
+	Err  error
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%s: %s -> %s", e.Err, e.From, e.To)
+}
+
+func (e *TransitionError) Unwrap() error { return e.Err }
+
+// CheckTransition memvalidasi perpindahan status from -> to terhadap permission pemanggil.
+func CheckTransition(from, to string, can PermissionChecker) error {
+	action, ok := achievementTransitions[statusTransition{from, to}]
+	if !ok {
+		return &TransitionError{From: from, To: to, Err: ErrIllegalTransition}
+	}
+	if !can("achievement", action) {
+		return &TransitionError{From: from, To: to, Err: ErrTransitionNotAllowed}
+	}
+	return nil
+}
+
+// transitionErrorResponse memetakan error transisi ke HTTP status: 409 untuk transisi ilegal
+// atau status yang berubah bersamaan, 403 untuk pemanggil tanpa permission transisinya.
+func transitionErrorResponse(err error) (int, gin.H) {
+	var te *TransitionError
+	switch {
+	case errors.As(err, &te) && errors.Is(err, ErrTransitionNotAllowed):
+		return http.StatusForbidden, gin.H{"error": "Anda tidak punya permission untuk mengubah status " + te.From + " ke " + te.To}
+	case errors.As(err, &te):
+		return http.StatusConflict, gin.H{"error": "Status tidak bisa diubah dari " + te.From + " ke " + te.To, "from": te.From, "to": te.To}
+	case errors.Is(err, repository.ErrStatusConflict):
+		return http.StatusConflict, gin.H{"error": "Status prestasi sudah berubah, muat ulang data"}
+	default:
+		return http.StatusInternalServerError, gin.H{"error": err.Error()}
+	}
+}
+
+func writeTransitionError(c *gin.Context, err error) {
+	c.JSON(transitionErrorResponse(err))
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"pelaporan_prestasi/app/models/postgres"
+	"pelaporan_prestasi/app/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Mode verifikasi prestasi tim (TEAM_VERIFICATION). single: cukup satu keputusan dari dosen wali
+// salah satu anggota. per_advisor: setiap dosen wali anggota harus menyetujui sebelum VERIFIED;
+// satu penolakan langsung menolak prestasi untuk seluruh tim.
+const (
+	TeamVerificationSingle     = "single"
+	TeamVerificationPerAdvisor = "per_advisor"
+)
+
+const maxTeamMembers = 20
+
+func CheckTeamVerification(mode string) error {
+	if mode != TeamVerificationSingle && mode != TeamVerificationPerAdvisor {
+		return fmt.Errorf("TEAM_VERIFICATION harus %s atau %s, bukan %q", TeamVerificationSingle, TeamVerificationPerAdvisor, mode)
+	}
+	return nil
+}
+
+type TeamMemberInput struct {
+	StudentID string `json:"student_id" binding:"required" example:"user id mahasiswa"`
+	Role      string `json:"role" example:"anggota"`
+}
+
+type UpdateMembersRequest struct {
+	Members []TeamMemberInput `json:"members" binding:"dive"`
+}
+
+// buildMembers menyusun daftar anggota tim dari input. Pembuat prestasi selalu ikut; jika tidak ada
+// yang ditandai ketua, pembuat menjadi ketua.
+func buildMembers(ownerID string, inputs []TeamMemberInput) ([]postgres.AchievementMember, error) {
+	if len(inputs) > maxTeamMembers {
+		return nil, fmt.Errorf("anggota tim maksimal %d orang", maxTeamMembers)
+	}
+
+	members := []postgres.AchievementMember{}
+	seen := map[string]bool{}
+	ketua := 0
+	for _, in := range inputs {
+		if in.StudentID == "" {
+			return nil, errors.New("student_id anggota wajib diisi")
+		}
+		if seen[in.StudentID] {
+			return nil, fmt.Errorf("anggota %s tercantum lebih dari sekali", in.StudentID)
+		}
+		seen[in.StudentID] = true
+
+		switch in.Role {
+		case "":
+			in.Role = postgres.MemberAnggota
+		case postgres.MemberKetua:
+			ketua++
+		case postgres.MemberAnggota:
+		default:
+			return nil, fmt.Errorf("role anggota harus %s atau %s", postgres.MemberKetua, postgres.MemberAnggota)
+		}
+		members = append(members, postgres.AchievementMember{StudentID: in.StudentID, Role: in.Role})
+	}
+	if ketua > 1 {
+		return nil, errors.New("tim hanya boleh punya satu ketua")
+	}
+
+	if !seen[ownerID] {
+		role := postgres.MemberAnggota
+		if ketua == 0 {
+			role = postgres.MemberKetua
+		}
+		members = append([]postgres.AchievementMember{{StudentID: ownerID, Role: role}}, members...)
+	} else if ketua == 0 {
+		for i := range members {
+			if members[i].StudentID == ownerID {
+				members[i].Role = postgres.MemberKetua
+			}
+		}
+	}
+	return members, nil
+}
+
+// parseMembersForm membaca field form "members" (JSON array) pada Create.
+func parseMembersForm(ownerID, raw string) ([]postgres.AchievementMember, error) {
+	var inputs []TeamMemberInput
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &inputs); err != nil {
+			return nil, errors.New("members harus berupa JSON array [{student_id, role}]")
+		}
+	}
+	return buildMembers(ownerID, inputs)
+}
+
+func writeMembersError(c *gin.Context, err error) {
+	if errors.Is(err, repository.ErrNotStudent) {
+		c.JSON(400, gin.H{"error": "Anggota tim harus mahasiswa terdaftar: " + err.Error()})
+		return
+	}
+	c.JSON(500, gin.H{"error": err.Error()})
+}
+
+type TeamResponse struct {
+	Members      []postgres.AchievementMember `json:"members"`
+	Verification string                       `json:"verification"`
+	// Approvals hanya terisi saat PENDING pada mode per_advisor
+	Approvals []postgres.AdvisorApproval `json:"approvals,omitempty"`
+}
+
+// GetMembers godoc
+// @Summary List Team Members
+// @Description Anggota tim prestasi (ketua/anggota) beserta dosen walinya. Pada mode per_advisor dan status PENDING, juga daftar persetujuan setiap dosen wali.
+// @Tags Achievements
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Success 200 {object} TeamResponse
+// @Router /achievements/{id}/members [get]
+func (s *AchievementService) GetMembers(c *gin.Context) {
+	ref, ok := s.loadRefForRead(c)
+	if !ok {
+		return
+	}
+
+	members, err := s.Repo.FindMembers(c.Request.Context(), ref.ID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	resp := TeamResponse{Members: members, Verification: s.TeamVerification}
+	if ref.Status == StatusPending && s.TeamVerification == TeamVerificationPerAdvisor {
+		resp.Approvals, err = s.Repo.FindApprovals(c.Request.Context(), ref.ID)
+		if err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	c.JSON(200, gin.H{"data": resp})
+}
+
+// UpdateMembers godoc
+// @Summary Replace Team Members
+// @Description Ganti daftar anggota tim. Hanya pembuat prestasi, dan hanya saat DRAFT atau REJECTED. Pembuat selalu tetap menjadi anggota.
+// @Tags Achievements
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Param body body UpdateMembersRequest true "Anggota"
+// @Success 200 {object} map[string]interface{}
+// @Router /achievements/{id}/members [put]
+func (s *AchievementService) UpdateMembers(c *gin.Context) {
+	var req UpdateMembersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	ref, err := s.Repo.FindRefByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(404, gin.H{"error": "Not found"})
+		return
+	}
+	if ref.StudentID != c.GetString("user_id") {
+		c.JSON(403, gin.H{"error": "Forbidden"})
+		return
+	}
+	if ref.Status != StatusDraft && ref.Status != StatusRejected {
+		c.JSON(400, gin.H{"error": "Locked"})
+		return
+	}
+
+	members, err := buildMembers(ref.StudentID, req.Members)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if err := s.Repo.ReplaceMembers(c.Request.Context(), ref.ID, members); err != nil {
+		writeMembersError(c, err)
+		return
+	}
+
+	saved, err := s.Repo.FindMembers(c.Request.Context(), ref.ID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"message": "Members updated", "data": saved})
+}
+
+// LeaveTeam godoc
+// @Summary Leave Team
+// @Description Mahasiswa keluar dari tim prestasi yang mencantumkannya. Pembuat prestasi tidak bisa keluar. Pada prestasi VERIFIED, atestasi diterbitkan ulang untuk tim yang tersisa.
+// @Tags Achievements
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Success 200 {object} map[string]string
+// @Router /achievements/{id}/members/me [delete]
+func (s *AchievementService) LeaveTeam(c *gin.Context) {
+	ref, err := s.Repo.FindRefByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(404, gin.H{"error": "Not found"})
+		return
+	}
+	if ref.StudentID == c.GetString("user_id") {
+		c.JSON(400, gin.H{"error": "Pembuat prestasi tidak bisa keluar dari tim; hapus prestasinya jika perlu"})
+		return
+	}
+
+	if err := s.Repo.RemoveMember(c.Request.Context(), ref.ID, c.GetString("user_id")); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(404, gin.H{"error": "Anda bukan anggota tim prestasi ini"})
+			return
+		}
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if ref.Status == StatusVerified {
+		s.Tasks.RunFor(c.Request.Context(), ref.ID)
+	}
+	c.JSON(200, gin.H{"message": "Left team"})
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	mongodb "pelaporan_prestasi/app/models/mongo"
+	"pelaporan_prestasi/app/models/postgres"
+	"pelaporan_prestasi/app/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AchievementWriter mengoordinasikan penulisan konten (Mongo) dan referensi (Postgres) sebagai saga.
+// Setiap langkah yang gagal memicu kompensasi atas langkah sebelumnya; kompensasi yang ikut gagal
+// dibiarkan untuk dibereskan ReconcileService.
+type AchievementWriter struct {
+	Repo      *repository.AchievementRepository
+	Revisions *repository.RevisionRepository
+	Files     *AttachmentFiles
+}
+
+func NewAchievementWriter(repo *repository.AchievementRepository, revisions *repository.RevisionRepository, files *AttachmentFiles) *AchievementWriter {
+	return &AchievementWriter{Repo: repo, Revisions: revisions, Files: files}
+}
+
+// Create menyimpan konten ke Mongo lalu referensi, anggota tim + history ke Postgres.
+// Jika salah satu gagal, dokumen Mongo dan file lampiran yang sudah tersimpan dihapus kembali.
+func (w *AchievementWriter) Create(ctx context.Context, content *mongodb.Achievement, ref *postgres.AchievementReference, hist postgres.AchievementHistory, members []postgres.AchievementMember) error {
+	mongoID, err := w.Repo.InsertMongo(ctx, content)
+	if err != nil {
+		w.Files.Remove(content.Attachments)
+		return fmt.Errorf("mongo insert: %w", err)
+	}
+
+	ref.MongoAchievementID = mongoID
+	if err := w.Repo.CreateRefWithHistory(ctx, ref, repository.SummaryOf(*content), hist, members); err != nil {
+		w.compensateMongo(mongoID, content.Attachments)
+		return fmt.Errorf("postgres insert: %w", err)
+	}
+
+	// Revisi awal tidak kritis: jika gagal, UpdateContent membuatnya dari konten saat itu
+	content.ID, _ = primitive.ObjectIDFromHex(mongoID)
+	first := revisionOf(*content, 1, allContentFields(*content), ref.StudentID)
+	first.CreatedAt = content.CreatedAt
+	if err := w.Revisions.Insert(ctx, &first); err != nil {
+		log.Printf("⚠️ Gagal simpan revisi awal %s: %v", mongoID, err)
+	}
+	return nil
+}
+
+// UpdateContent menyimpan revisi baru lalu menimpa konten bila versinya masih current.Version.
+// Mengembalikan nil jika tidak ada field yang berubah. Revisi ditulis lebih dulu: unique index
+// nomor revisi menolak edit bersamaan sebelum konten tertimpa, dan jika update konten gagal
+// revisi itu dihapus lagi. Konflik dilaporkan sebagai repository.ErrVersionConflict.
+func (w *AchievementWriter) UpdateContent(ctx context.Context, current mongodb.Achievement, update mongodb.Achievement, changedBy string) (*mongodb.AchievementRevision, error) {
+	mongoID := current.ID.Hex()
+	version := current.CurrentVersion()
+
+	_, err := w.Revisions.FindLatest(ctx, mongoID)
+	if errors.Is(err, repository.ErrNotFound) {
+		// Prestasi lama tanpa revisi: jadikan konten sekarang revisi 1
+		base := revisionOf(current, 1, allContentFields(current), current.StudentID)
+		base.CreatedAt = current.UpdatedAt
+		if err := w.Revisions.Insert(ctx, &base); err != nil && !errors.Is(err, repository.ErrRevisionConflict) {
+			return nil, fmt.Errorf("revision baseline: %w", err)
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	changed := changedContentFields(current, update)
+	if len(changed) == 0 {
+		return nil, nil
+	}
+
+	rev := revisionOf(update, version+1, changed, changedBy)
+	rev.AchievementID = mongoID
+	if err := w.Revisions.Insert(ctx, &rev); err != nil {
+		if errors.Is(err, repository.ErrRevisionConflict) {
+			return nil, repository.ErrVersionConflict
+		}
+		return nil, err
+	}
+	if err := w.Repo.UpdateContentMongo(ctx, mongoID, update, version); err != nil {
+		if derr := w.Revisions.Delete(context.Background(), rev.ID); derr != nil {
+			log.Printf("⚠️ Gagal hapus revisi %s setelah update gagal: %v", rev.ID.Hex(), derr)
+		}
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("mongo update: %w", err)
+	}
+	syncListSummary(ctx, w.Repo, mongoID)
+	return &rev, nil
+}
+
+// syncListSummary menyalin ulang ringkasan konten (dipakai filter/sort daftar prestasi) ke Postgres
+// setelah dokumen Mongo berubah. Kegagalan hanya dicatat; ReconcileService menyalin ringkasan yang tertinggal.
+func syncListSummary(ctx context.Context, repo *repository.AchievementRepository, mongoID string) {
+	content, err := repo.FindContentByMongoID(ctx, mongoID)
+	if err == nil {
+		err = repo.SaveSummary(ctx, mongoID, repository.SummaryOf(*content))
+	}
+	if err != nil {
+		log.Printf("⚠️ Gagal menyalin ringkasan konten %s ke Postgres: %v", mongoID, err)
+	}
+}
+
+// Trash memindahkan prestasi ke tong sampah. Postgres adalah sumber kebenaran (semua query
+// mengabaikan baris dengan deleted_at); tanda di dokumen Mongo hanya pelengkap, jadi kegagalannya dicatat saja.
+func (w *AchievementWriter) Trash(ctx context.Context, ref *postgres.AchievementReference, deletedBy string) error {
+	deletedAt, err := w.Repo.SoftDeleteRef(ctx, ref.ID, deletedBy)
+	if err != nil {
+		return err
+	}
+	if err := w.Repo.SetDeletedMongo(ctx, ref.MongoAchievementID, true, deletedBy, deletedAt); err != nil {
+		log.Printf("⚠️ Gagal menandai dokumen Mongo %s terhapus: %v", ref.MongoAchievementID, err)
+	}
+	return nil
+}
+
+// Restore mengeluarkan prestasi dari tong sampah bila dihapus setelah deletedSince.
+func (w *AchievementWriter) Restore(ctx context.Context, ref *postgres.AchievementReference, deletedSince time.Time) error {
+	if err := w.Repo.RestoreRef(ctx, ref.ID, deletedSince); err != nil {
+		return err
+	}
+	if err := w.Repo.SetDeletedMongo(ctx, ref.MongoAchievementID, false, "", time.Time{}); err != nil {
+		log.Printf("⚠️ Gagal menghapus tanda terhapus dokumen Mongo %s: %v", ref.MongoAchievementID, err)
+	}
+	return nil
+}
+
+// Purge menghapus permanen: referensi Postgres lebih dulu (sumber kebenaran keberadaan prestasi),
+// lalu dokumen Mongo, file lampiran dan revisinya.
+func (w *AchievementWriter) Purge(ctx context.Context, ref *postgres.AchievementReference) error {
+	content, err := w.Repo.FindContentByMongoID(ctx, ref.MongoAchievementID)
+	if err != nil {
+		content = nil
+	}
+
+	if err := w.Repo.DeleteRef(ctx, ref.ID); err != nil {
+		return fmt.Errorf("postgres delete: %w", err)
+	}
+
+	var attachments []mongodb.Attachment
+	if content != nil {
+		attachments = content.Attachments
+	}
+	w.compensateMongo(ref.MongoAchievementID, attachments)
+	if err := w.Revisions.DeleteByAchievement(context.Background(), ref.MongoAchievementID); err != nil {
+		log.Printf("⚠️ Gagal hapus revisi %s: %v", ref.MongoAchievementID, err)
+	}
+	return nil
+}
+
+// compensateMongo memakai context baru supaya tetap jalan walau request sudah dibatalkan.
+func (w *AchievementWriter) compensateMongo(mongoID string, attachments []mongodb.Attachment) {
+	if err := w.Repo.DeleteMongo(context.Background(), mongoID); err != nil {
+		log.Printf("⚠️ Kompensasi gagal, dokumen Mongo %s tertinggal (akan dibereskan rekonsiliasi): %v", mongoID, err)
+		return
+	}
+	w.Files.Remove(attachments)
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	mongodb "pelaporan_prestasi/app/models/mongo"
+	"pelaporan_prestasi/app/scanner"
+	"pelaporan_prestasi/app/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AttachmentFiles menghubungkan lampiran prestasi dengan FileStore: memvalidasi dan menyimpan upload,
+// menghapus file, dan mengganti fileUrl di response dengan URL bertanda tangan yang kadaluarsa.
+type AttachmentFiles struct {
+	Store   storage.FileStore
+	Scanner scanner.Scanner
+	URLTTL  time.Duration
+	Limits  UploadLimits
+}
+
+// UploadLimits membatasi ukuran satu file dan total lampiran per prestasi (byte).
+type UploadLimits struct {
+	MaxFileSize  int64
+	MaxTotalSize int64
+}
+
+func NewAttachmentFiles(store storage.FileStore, scan scanner.Scanner, urlTTL time.Duration, limits UploadLimits) *AttachmentFiles {
+	return &AttachmentFiles{Store: store, Scanner: scan, URLTTL: urlTTL, Limits: limits}
+}
+
+// attachmentKey mengembalikan key storage lampiran. Lampiran lama (sebelum FileStore) hanya
+// punya fileUrl berupa path lokal "uploads/<nama>".
+func attachmentKey(att mongodb.Attachment) string {
+	if att.StorageKey != "" {
+		return att.StorageKey
+	}
+	return strings.TrimPrefix(strings.ReplaceAll(att.FileURL, "\\", "/"), "uploads/")
+}
+
+// attachmentID mengembalikan id lampiran; lampiran lama memakai nama file storage tanpa ekstensi.
+func attachmentID(att mongodb.Attachment) string {
+	if att.ID != "" {
+		return att.ID
+	}
+	base := path.Base(attachmentKey(att))
+	return strings.TrimSuffix(base, path.Ext(base))
+}
+
+func findAttachment(content *mongodb.Achievement, attID string) (mongodb.Attachment, bool) {
+	for _, att := range content.Attachments {
+		if attachmentID(att) == attID {
+			return att, true
+		}
+	}
+	return mongodb.Attachment{}, false
+}
+
+// attachmentContentType menebak Content-Type dari ekstensi file lampiran.
+func attachmentContentType(att mongodb.Attachment) string {
+	ext := att.FileType
+	if ext == "" {
+		ext = path.Ext(attachmentKey(att))
+	}
+	if ct := mime.TypeByExtension(strings.ToLower(ext)); ct != "" {
+		return ct
+	}
+	return "application/octet-stream"
+}
+
+// contentDisposition membuat header Content-Disposition dengan nama file yang di-escape dengan benar.
+func contentDisposition(disposition, fileName string) string {
+	if v := mime.FormatMediaType(disposition, map[string]string{"filename": fileName}); v != "" {
+		return v
+	}
+	return disposition
+}
+
+func attachmentsSize(attachments []mongodb.Attachment) int64 {
+	var total int64
+	for _, att := range attachments {
+		total += att.Size
+	}
+	return total
+}
+
+// Accept memvalidasi upload (ukuran, MIME hasil sniffing, polyglot), menaruhnya di karantina,
+// memindainya, lalu memindahkannya ke key final. usedBytes adalah total lampiran yang sudah ada.
+// File yang terdeteksi malware dibiarkan di karantina untuk diperiksa admin.
+func (f *AttachmentFiles) Accept(ctx context.Context, file *multipart.FileHeader, studentID string, usedBytes int64) (mongodb.Attachment, error) {
+	if file.Size > f.Limits.MaxFileSize {
+		return mongodb.Attachment{}, &UploadError{Status: http.StatusRequestEntityTooLarge, Message: fmt.Sprintf("Ukuran file maksimal %d MB", f.Limits.MaxFileSize>>20)}
+	}
+	if usedBytes+file.Size > f.Limits.MaxTotalSize {
+		return mongodb.Attachment{}, &UploadError{Status: http.StatusRequestEntityTooLarge, Message: fmt.Sprintf("Total lampiran per prestasi maksimal %d MB", f.Limits.MaxTotalSize>>20)}
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return mongodb.Attachment{}, err
+	}
+	defer src.Close()
+	data, err := io.ReadAll(io.LimitReader(src, f.Limits.MaxFileSize+1))
+	if err != nil {
+		return mongodb.Attachment{}, err
+	}
+	if int64(len(data)) > f.Limits.MaxFileSize {
+		return mongodb.Attachment{}, &UploadError{Status: http.StatusRequestEntityTooLarge, Message: fmt.Sprintf("Ukuran file maksimal %d MB", f.Limits.MaxFileSize>>20)}
+	}
+
+	mimeType, err := sniffUpload(file.Filename, data)
+	if err != nil {
+		return mongodb.Attachment{}, err
+	}
+
+	ext := strings.ToLower(path.Ext(file.Filename))
+	key := attachmentStorageKey(studentID, ext)
+	quarantineKey := "quarantine/" + key
+	if err := f.Store.Put(ctx, quarantineKey, bytes.NewReader(data), int64(len(data)), mimeType); err != nil {
+		return mongodb.Attachment{}, err
+	}
+
+	result, err := f.Scanner.Scan(ctx, bytes.NewReader(data))
+	if err != nil {
+		f.Store.Delete(context.Background(), quarantineKey)
+		return mongodb.Attachment{}, &UploadError{Status: http.StatusServiceUnavailable, Message: "Pemindai file tidak tersedia, coba lagi nanti"}
+	}
+	if !result.Clean {
+		log.Printf("🦠 Upload %s (%s) dikarantina: %s", file.Filename, quarantineKey, result.Signature)
+		return mongodb.Attachment{}, uploadRejected("File terdeteksi mengandung malware dan dikarantina")
+	}
+
+	if err := f.Store.Put(ctx, key, bytes.NewReader(data), int64(len(data)), mimeType); err != nil {
+		f.Store.Delete(context.Background(), quarantineKey)
+		return mongodb.Attachment{}, err
+	}
+	if err := f.Store.Delete(ctx, quarantineKey); err != nil {
+		log.Printf("⚠️ Gagal hapus file karantina %s: %v", quarantineKey, err)
+	}
+
+	return mongodb.Attachment{
+		ID:         uuid.New().String(),
+		FileName:   file.Filename,
+		StorageKey: key,
+		FileType:   ext,
+		Size:       int64(len(data)),
+		SHA256:     fmt.Sprintf("%x", sha256.Sum256(data)),
+		UploadedAt: time.Now(),
+	}, nil
+}
+
+// LimitBody membatasi ukuran body multipart supaya file raksasa tidak sempat ditulis ke disk sementara.
+func (f *AttachmentFiles) LimitBody(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, f.Limits.MaxFileSize+1<<20)
+}
+
+// bodyTooLarge mengubah error baca body yang melewati LimitBody menjadi UploadError 413.
+func (f *AttachmentFiles) bodyTooLarge(err error) (*UploadError, bool) {
+	var merr *http.MaxBytesError
+	if !errors.As(err, &merr) {
+		return nil, false
+	}
+	return &UploadError{Status: http.StatusRequestEntityTooLarge, Message: fmt.Sprintf("Ukuran file maksimal %d MB", f.Limits.MaxFileSize>>20)}, true
+}
+
+// writeUploadError menulis UploadError apa adanya, error lain sebagai 500.
+func writeUploadError(c *gin.Context, err error) {
+	var uerr *UploadError
+	if errors.As(err, &uerr) {
+		c.JSON(uerr.Status, gin.H{"error": uerr.Message})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal simpan file"})
+}
+
+// Remove menghapus file lampiran; kegagalan hanya dicatat karena dipakai di jalur kompensasi.
+func (f *AttachmentFiles) Remove(attachments []mongodb.Attachment) {
+	for _, att := range attachments {
+		key := attachmentKey(att)
+		if key == "" {
+			continue
+		}
+		if err := f.Store.Delete(context.Background(), key); err != nil {
+			log.Printf("⚠️ Gagal hapus file %s: %v", key, err)
+		}
+		if att.Preview != nil && att.Preview.ThumbnailKey != "" {
+			if err := f.Store.Delete(context.Background(), att.Preview.ThumbnailKey); err != nil {
+				log.Printf("⚠️ Gagal hapus thumbnail %s: %v", att.Preview.ThumbnailKey, err)
+			}
+		}
+	}
+}
+
+// Sign mengisi fileUrl (dan thumbnailUrl preview) setiap lampiran dengan URL unduhan bertanda tangan.
+func (f *AttachmentFiles) Sign(ctx context.Context, content *mongodb.Achievement) {
+	for i := range content.Attachments {
+		att := &content.Attachments[i]
+		att.ID = attachmentID(*att)
+		if att.Preview != nil && att.Preview.ThumbnailKey != "" {
+			preview := *att.Preview
+			preview.ThumbnailURL, _ = f.Store.SignedURL(ctx, preview.ThumbnailKey, "", f.URLTTL)
+			att.Preview = &preview
+		}
+		url, err := f.Store.SignedURL(ctx, attachmentKey(*att), att.FileName, f.URLTTL)
+		if err != nil {
+			log.Printf("⚠️ Gagal membuat URL file %s: %v", attachmentKey(*att), err)
+			att.FileURL = ""
+			continue
+		}
+		att.FileURL = url
+	}
+}
+
+func (f *AttachmentFiles) SignAll(ctx context.Context, contents map[string]mongodb.Achievement) {
+	for id, content := range contents {
+		f.Sign(ctx, &content)
+		contents[id] = content
+	}
+}
+
+// Download godoc
+// @Summary Download File (signed URL)
+// @Description Endpoint tujuan URL bertanda tangan dari driver storage lokal. Tidak butuh token, tapi expires & sig wajib valid.
+// @Tags Files
+// @Param key path string true "Storage key"
+// @Param expires query int true "Unix timestamp kadaluarsa"
+// @Param sig query string true "Tanda tangan HMAC"
+// @Param name query string false "Nama file unduhan"
+// @Success 200 {file} file
+// @Router /files/{key} [get]
+func (f *AttachmentFiles) Download(c *gin.Context) {
+	local, ok := f.Store.(*storage.LocalStore)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+		return
+	}
+
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	if err := local.Verify(key, c.Query("expires"), c.Query("name"), c.Query("sig")); err != nil {
+		if errors.Is(err, storage.ErrSignatureExpired) {
+			c.JSON(http.StatusGone, gin.H{"error": "URL sudah kadaluarsa"})
+			return
+		}
+		c.JSON(http.StatusForbidden, gin.H{"error": "Tanda tangan URL tidak valid"})
+		return
+	}
+
+	rc, err := local.Open(c.Request.Context(), key)
+	if err != nil {
+		if errors.Is(err, storage.ErrFileNotFound) || errors.Is(err, storage.ErrInvalidKey) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "File tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer rc.Close()
+
+	name := c.Query("name")
+	if name == "" {
+		name = key[strings.LastIndex(key, "/")+1:]
+	}
+	c.Header("Content-Disposition", contentDisposition("attachment", name))
+	c.DataFromReader(http.StatusOK, -1, "application/octet-stream", rc, nil)
+}
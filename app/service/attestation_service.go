@@ -0,0 +1,419 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"pelaporan_prestasi/app/attest"
+	mongodb "pelaporan_prestasi/app/models/mongo"
+	"pelaporan_prestasi/app/models/postgres"
+	"pelaporan_prestasi/app/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AttestationService menerbitkan atestasi bertanda tangan Ed25519 saat prestasi VERIFIED supaya pihak
+// ketiga (panitia beasiswa, pemberi kerja) bisa memeriksa keaslian prestasi tanpa akun.
+type AttestationService struct {
+	Repo    *repository.AttestationRepository
+	AchRepo *repository.AchievementRepository
+	Signer  *attest.Signer
+	// BaseURL dipakai untuk membentuk verify_url yang dikodekan ke QR
+	BaseURL string
+}
+
+func NewAttestationService(repo *repository.AttestationRepository, achRepo *repository.AchievementRepository, signer *attest.Signer, baseURL string) *AttestationService {
+	return &AttestationService{Repo: repo, AchRepo: achRepo, Signer: signer, BaseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// contentDigest adalah SHA-256 (hex) isi prestasi yang diverifikasi: judul, deskripsi, tipe, details,
+// tags, event, dan hash setiap lampiran. Poin tidak ikut karena dihitung ulang oleh rulebook.
+func contentDigest(content mongodb.Achievement) string {
+	type attachmentDigest struct {
+		ID       string `json:"id"`
+		FileName string `json:"fileName"`
+		SHA256   string `json:"sha256"`
+	}
+	attachments := make([]attachmentDigest, 0, len(content.Attachments))
+	for _, att := range content.Attachments {
+		attachments = append(attachments, attachmentDigest{ID: attachmentID(att), FileName: att.FileName, SHA256: att.SHA256})
+	}
+	canonical, _ := json.Marshal(struct {
+		Title           string                     `json:"title"`
+		Description     string                     `json:"description"`
+		AchievementType string                     `json:"achievementType"`
+		Details         mongodb.AchievementDetails `json:"details"`
+		Tags            []string                   `json:"tags"`
+		EventID         string                     `json:"eventId"`
+		Attachments     []attachmentDigest         `json:"attachments"`
+	}{content.Title, content.Description, content.AchievementType, content.Details, content.Tags, content.EventID, attachments})
+	return fmt.Sprintf("%x", sha256.Sum256(canonical))
+}
+
+// Issue menandatangani atestasi baru untuk prestasi ref yang diverifikasi oleh verifierID.
+// Atestasi aktif sebelumnya dicabut dengan alasan "superseded", kecuali jika atestasi itu sudah
+// mencakup isi, anggota tim, dan kunci yang sama: atestasi itu dikembalikan apa adanya supaya QR
+// yang sudah dibagikan tetap valid.
+func (s *AttestationService) Issue(ctx context.Context, ref *postgres.AchievementReference, verifierID string) (*postgres.Attestation, error) {
+	content, err := s.AchRepo.FindContentByMongoID(ctx, ref.MongoAchievementID)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.AchRepo.FindMembers(ctx, ref.ID)
+	if err != nil {
+		return nil, err
+	}
+	team := make([]postgres.AttestationParty, 0, len(members))
+	for _, mb := range members {
+		team = append(team, postgres.AttestationParty{ID: mb.StudentID, Name: mb.Name, NIM: mb.NIM})
+	}
+	digest := contentDigest(*content)
+
+	current, err := s.Repo.FindLatestByAchievement(ctx, ref.ID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+	if current != nil && s.coversCurrent(current, ref.StudentID, digest, team) {
+		return current, nil
+	}
+
+	student, err := s.Repo.FindParty(ctx, ref.StudentID)
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := s.Repo.FindParty(ctx, verifierID)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := postgres.AttestationClaims{
+		Version:       2,
+		ID:            uuid.New().String(),
+		KeyID:         s.Signer.KeyID,
+		AchievementID: ref.ID,
+		Title:         content.Title,
+		ContentSHA256: digest,
+		Student:       student,
+		Team:          team,
+		Verifier:      postgres.AttestationParty{ID: verifier.ID, Name: verifier.Name},
+		VerifiedAt:    time.Now().UTC().Truncate(time.Second),
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &postgres.Attestation{
+		ID:            claims.ID,
+		AchievementID: &ref.ID,
+		KeyID:         claims.KeyID,
+		Payload:       string(payload),
+		Signature:     base64.RawURLEncoding.EncodeToString(s.Signer.Sign(payload)),
+		ContentSHA256: claims.ContentSHA256,
+		StudentID:     ref.StudentID,
+		VerifierID:    verifierID,
+		IssuedAt:      claims.VerifiedAt,
+	}
+	if err := s.Repo.Create(ctx, a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// coversCurrent: atestasi a masih aktif, ditandatangani kunci sekarang, dan mencakup isi serta
+// anggota tim yang sama.
+func (s *AttestationService) coversCurrent(a *postgres.Attestation, studentID, digest string, team []postgres.AttestationParty) bool {
+	if a.RevokedAt != nil || a.KeyID != s.Signer.KeyID || a.ContentSHA256 != digest || a.StudentID != studentID {
+		return false
+	}
+	var claims postgres.AttestationClaims
+	if err := json.Unmarshal([]byte(a.Payload), &claims); err != nil || len(claims.Team) != len(team) {
+		return false
+	}
+	signed := make(map[string]bool, len(claims.Team))
+	for _, p := range claims.Team {
+		signed[p.ID] = true
+	}
+	for _, p := range team {
+		if !signed[p.ID] {
+			return false
+		}
+	}
+	return true
+}
+
+// RegisterKey mendaftarkan kunci aktif supaya atestasi yang ditandatanganinya tetap bisa diverifikasi
+// setelah kunci dirotasi.
+func (s *AttestationService) RegisterKey(ctx context.Context) error {
+	return s.Repo.RegisterKey(ctx, s.Signer.KeyID, base64.StdEncoding.EncodeToString(s.Signer.PublicKey()))
+}
+
+// verifySignature memeriksa tanda tangan dengan kunci keyID: kunci aktif atau kunci lama dari attestation_keys.
+func (s *AttestationService) verifySignature(ctx context.Context, keyID string, payload, sig []byte) (known, valid bool, err error) {
+	if keyID == s.Signer.KeyID {
+		return true, s.Signer.Verify(payload, sig), nil
+	}
+	key, err := s.Repo.FindKey(ctx, keyID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, err
+	}
+	pub, err := base64.StdEncoding.DecodeString(key.PublicKey)
+	if err != nil || attest.KeyIDOf(pub) != keyID {
+		return false, false, nil
+	}
+	return true, attest.Verify(pub, payload, sig), nil
+}
+
+// AttestationResponse adalah atestasi beserta payload QR dan URL verifikasi publiknya.
+type AttestationResponse struct {
+	postgres.Attestation
+	Claims    postgres.AttestationClaims `json:"claims"`
+	QRPayload string                     `json:"qr_payload"`
+	VerifyURL string                     `json:"verify_url"`
+}
+
+func (s *AttestationService) toResponse(a *postgres.Attestation) AttestationResponse {
+	resp := AttestationResponse{Attestation: *a}
+	json.Unmarshal([]byte(a.Payload), &resp.Claims)
+	if sig, err := base64.RawURLEncoding.DecodeString(a.Signature); err == nil {
+		resp.QRPayload = attest.EncodeToken([]byte(a.Payload), sig)
+	}
+	resp.VerifyURL = s.BaseURL + "/api/v1/attestations/verify?payload=" + url.QueryEscape(resp.QRPayload)
+	return resp
+}
+
+// writeLatest menulis atestasi terbaru prestasi ref (aktif atau sudah dicabut).
+func (s *AttestationService) writeLatest(c *gin.Context, ref *postgres.AchievementReference) {
+	a, err := s.Repo.FindLatestByAchievement(c.Request.Context(), ref.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Prestasi belum memiliki atestasi"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": s.toResponse(a)})
+}
+
+// ReissueAttestation godoc
+// @Summary Reissue Attestation (Admin)
+// @Description Terbitkan atestasi baru untuk prestasi VERIFIED (mis. setelah penerbitan otomatis gagal atau kunci dirotasi). Atestasi aktif sebelumnya dicabut sebagai superseded; jika atestasi aktif sudah mencakup isi, anggota tim, dan kunci yang sama, atestasi itu dikembalikan dengan 200.
+// @Tags Attestations
+// @Security BearerAuth
+// @Param id path string true "ID prestasi"
+// @Success 200 {object} AttestationResponse
+// @Success 201 {object} AttestationResponse
+// @Router /achievements/{id}/attestation [post]
+func (s *AttestationService) ReissueAttestation(c *gin.Context) {
+	ref, err := s.AchRepo.FindRefByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+		return
+	}
+	if ref.Status != StatusVerified {
+		c.JSON(http.StatusConflict, gin.H{"error": "Atestasi hanya untuk prestasi VERIFIED"})
+		return
+	}
+	// Verifikator tetap dosen yang memverifikasi; admin hanya menjadi verifikator jika belum pernah ada atestasi
+	verifierID := c.GetString("user_id")
+	prev, err := s.Repo.FindLatestByAchievement(c.Request.Context(), ref.ID)
+	if err == nil {
+		verifierID = prev.VerifierID
+	}
+
+	a, err := s.Issue(c.Request.Context(), ref, verifierID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if prev != nil && prev.ID == a.ID {
+		c.JSON(http.StatusOK, gin.H{"status": "unchanged", "data": s.toResponse(a)})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"status": "success", "data": s.toResponse(a)})
+}
+
+type RevokeAttestationRequest struct {
+	Reason string `json:"reason" binding:"required" example:"Sertifikat terbukti palsu"`
+}
+
+// RevokeAttestation godoc
+// @Summary Revoke Attestation (Admin)
+// @Tags Attestations
+// @Security BearerAuth
+// @Param id path string true "ID atestasi"
+// @Param body body RevokeAttestationRequest true "Alasan"
+// @Success 200 {object} map[string]string
+// @Router /attestations/{id}/revoke [post]
+func (s *AttestationService) RevokeAttestation(c *gin.Context) {
+	var req RevokeAttestationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if _, err := uuid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Atestasi tidak ditemukan atau sudah dicabut"})
+		return
+	}
+	if err := s.Repo.Revoke(c.Request.Context(), c.Param("id"), c.GetString("user_id"), strings.TrimSpace(req.Reason)); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Atestasi tidak ditemukan atau sudah dicabut"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Attestation revoked"})
+}
+
+// GetPublicKey godoc
+// @Summary Attestation Public Key
+// @Description Kunci publik Ed25519 (base64) untuk memverifikasi tanda tangan atestasi secara offline. keys memuat juga kunci lama yang sudah dirotasi, dicocokkan lewat kid di atestasi.
+// @Tags Attestations
+// @Success 200 {object} map[string]interface{}
+// @Router /attestations/public-key [get]
+func (s *AttestationService) GetPublicKey(c *gin.Context) {
+	keys, err := s.Repo.FindKeys(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"algorithm":  "Ed25519",
+		"key_id":     s.Signer.KeyID,
+		"public_key": base64.StdEncoding.EncodeToString(s.Signer.PublicKey()),
+		"keys":       keys,
+	})
+}
+
+// AttestationVerification adalah hasil pemeriksaan publik satu atestasi. Valid berarti tanda tangan
+// cocok, atestasi tercatat dan belum dicabut, dan isi prestasi saat ini masih sama dengan yang
+// ditandatangani.
+type AttestationVerification struct {
+	Valid            bool                        `json:"valid"`
+	SignatureValid   bool                        `json:"signature_valid"`
+	Registered       bool                        `json:"registered"`
+	Revoked          bool                        `json:"revoked"`
+	RevokedAt        *time.Time                  `json:"revoked_at,omitempty"`
+	RevocationReason *string                     `json:"revocation_reason,omitempty"`
+	ContentMatches   *bool                       `json:"content_matches,omitempty"`
+	Claims           *postgres.AttestationClaims `json:"claims,omitempty"`
+	Error            string                      `json:"error,omitempty"`
+}
+
+// VerifyAttestation godoc
+// @Summary Verify Attestation (Public)
+// @Description Periksa keaslian atestasi dari ID atestasi atau payload QR. Tidak butuh token.
+// @Tags Attestations
+// @Param id query string false "ID atestasi"
+// @Param payload query string false "Payload QR (base64url payload . base64url signature)"
+// @Success 200 {object} AttestationVerification
+// @Router /attestations/verify [get]
+func (s *AttestationService) VerifyAttestation(c *gin.Context) {
+	id, token := strings.TrimSpace(c.Query("id")), c.Query("payload")
+	if id == "" && token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Isi salah satu: id atau payload"})
+		return
+	}
+
+	var result AttestationVerification
+	var payload, sig []byte
+	if token != "" {
+		var err error
+		if payload, sig, err = attest.DecodeToken(token); err != nil {
+			result.Error = "Payload QR tidak terbaca"
+			c.JSON(http.StatusOK, result)
+			return
+		}
+	}
+
+	var claims postgres.AttestationClaims
+	if payload != nil {
+		if err := json.Unmarshal(payload, &claims); err != nil {
+			result.Error = "Payload atestasi tidak valid"
+			c.JSON(http.StatusOK, result)
+			return
+		}
+		id = claims.ID
+	}
+
+	// Endpoint publik: id yang bukan UUID tidak mungkin tercatat, tidak perlu ke DB
+	var stored *postgres.Attestation
+	if _, err := uuid.Parse(id); err == nil {
+		stored, err = s.Repo.FindByID(c.Request.Context(), id)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if stored != nil {
+		result.Registered = true
+		if payload == nil {
+			payload = []byte(stored.Payload)
+			sig, _ = base64.RawURLEncoding.DecodeString(stored.Signature)
+			json.Unmarshal(payload, &claims)
+		} else if string(payload) != stored.Payload {
+			result.Registered = false
+		}
+		result.Revoked = stored.RevokedAt != nil
+		result.RevokedAt, result.RevocationReason = stored.RevokedAt, stored.RevocationReason
+	}
+	if payload == nil {
+		result.Error = "Atestasi tidak ditemukan"
+		c.JSON(http.StatusOK, result)
+		return
+	}
+	result.Claims = &claims
+
+	knownKey, validSig, err := s.verifySignature(c.Request.Context(), claims.KeyID, payload, sig)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	switch {
+	case !knownKey:
+		result.Error = "Atestasi ditandatangani kunci yang tidak dikenal (" + claims.KeyID + ")"
+	case !validSig:
+		result.Error = attest.ErrBadSignature.Error()
+	default:
+		result.SignatureValid = true
+	}
+	if !result.Registered && result.Error == "" {
+		result.Error = "Atestasi tidak tercatat di sistem"
+	}
+
+	if result.SignatureValid && result.Registered {
+		matches := s.contentMatches(c.Request.Context(), claims)
+		result.ContentMatches = &matches
+	}
+	result.Valid = result.SignatureValid && result.Registered && !result.Revoked &&
+		result.ContentMatches != nil && *result.ContentMatches
+	c.JSON(http.StatusOK, result)
+}
+
+// contentMatches memeriksa prestasi masih VERIFIED dan isinya masih sama dengan yang ditandatangani.
+// Prestasi yang sudah dihapus dianggap tidak cocok.
+func (s *AttestationService) contentMatches(ctx context.Context, claims postgres.AttestationClaims) bool {
+	ref, err := s.AchRepo.FindRefByID(ctx, claims.AchievementID)
+	if err != nil || ref.Status != StatusVerified {
+		return false
+	}
+	content, err := s.AchRepo.FindContentByMongoID(ctx, ref.MongoAchievementID)
+	if err != nil {
+		return false
+	}
+	return contentDigest(*content) == claims.ContentSHA256
+}
@@ -1,30 +1,52 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"os"
 	"strings"
 	"time"
 
+	"pelaporan_prestasi/app/models/postgres"
 	"pelaporan_prestasi/app/repository"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+
+	accessTokenTTL  = time.Hour * 2
+	refreshTokenTTL = time.Hour * 72
+)
+
 type AuthService struct {
-	UserRepo *repository.UserRepository
+	UserRepo  *repository.UserRepository
+	TokenRepo *repository.RefreshTokenRepository
 	JWTSecret string
 }
 
-func NewAuthService(userRepo *repository.UserRepository, secret string) *AuthService {
+func NewAuthService(userRepo *repository.UserRepository, tokenRepo *repository.RefreshTokenRepository, secret string) *AuthService {
 	return &AuthService{
 		UserRepo:  userRepo,
-		JWTSecret: secret, 
+		TokenRepo: tokenRepo,
+		JWTSecret: secret,
 	}
 }
 
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
 type LoginRequest struct {
 	Username string `json:"username" binding:"required" example:"mahasiswa123"`
 	Password string `json:"password" binding:"required" example:"password123"`
@@ -60,9 +82,12 @@ func (s *AuthService) Login(c *gin.Context) {
 		return
 	}
 
-	// Generate Token
-	accessToken, _ := s.generateToken(user.ID, user.RoleID, time.Hour*2)      
-	refreshToken, _ := s.generateToken(user.ID, user.RoleID, time.Hour*72)   
+	// Generate Token (session baru = family refresh token baru)
+	accessToken, refreshToken, err := s.issueTokens(c.Request.Context(), user.ID, user.RoleID, uuid.New().String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat sesi"})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"status": "success",
@@ -82,48 +107,137 @@ func (s *AuthService) Login(c *gin.Context) {
 
 // RefreshToken godoc
 // @Summary      Refresh Access Token
-// @Description  Dapatkan token baru menggunakan refresh token
+// @Description  Tukar refresh token dengan pasangan access token + refresh token baru (rotasi).
+// @Description  Refresh token lama langsung tidak berlaku; memakai ulang token lama akan mencabut seluruh sesi.
 // @Tags         Authentication
 // @Accept       json
 // @Produce      json
-// @Param        request body object{refreshToken=string} true "Refresh Token"
+// @Param        request body RefreshRequest true "Refresh Token"
 // @Success      200  {object} map[string]interface{}
 // @Failure      401  {object} map[string]string
 // @Router       /auth/refresh [post]
 func (s *AuthService) Refresh(c *gin.Context) {
-	var input struct {
-		RefreshToken string `json:"refreshToken" binding:"required"`
-	}
+	var input RefreshRequest
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Refresh token required"})
 		return
 	}
 	claims, err := s.validateToken(input.RefreshToken)
+	if err != nil || claims["type"] != TokenTypeRefresh {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		return
+	}
+
+	jti, _ := claims["jti"].(string)
+	userID, _ := claims["user_id"].(string)
+	sid, _ := claims["sid"].(string)
+	if jti == "" || userID == "" || sid == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		return
+	}
+
+	// Role diambil ulang dari DB supaya perubahan role langsung berlaku di token baru
+	user, err := s.UserRepo.FindByID(c.Request.Context(), userID)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
 		return
 	}
 
-	userID := claims["user_id"].(string)
-	roleID := claims["role_id"].(string)
-	newAccessToken, _ := s.generateToken(userID, roleID, time.Hour*2)
+	next := postgres.RefreshToken{
+		ID:        uuid.New().String(),
+		ExpiresAt: time.Now().Add(refreshTokenTTL),
+	}
+	if err := s.TokenRepo.Rotate(c.Request.Context(), jti, &next); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRefreshTokenReused):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token sudah pernah dipakai, sesi dicabut. Silakan login ulang."})
+		case errors.Is(err, repository.ErrRefreshTokenNotFound), errors.Is(err, repository.ErrRefreshTokenRevoked):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal refresh token"})
+		}
+		return
+	}
+
+	newAccessToken, err := s.generateToken(user.ID, user.RoleID, TokenTypeAccess, uuid.New().String(), next.FamilyID, accessTokenTTL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal refresh token"})
+		return
+	}
+	newRefreshToken, err := s.generateToken(user.ID, user.RoleID, TokenTypeRefresh, next.ID, next.FamilyID, refreshTokenTTL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal refresh token"})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"status": "success",
 		"data": gin.H{
-			"token": newAccessToken,
+			"token":        newAccessToken,
+			"refreshToken": newRefreshToken,
 		},
 	})
 }
 
 // Logout godoc
 // @Summary      Logout User
-// @Description  Keluar dari sistem
+// @Description  Keluar dari sistem dan cabut sesi saat ini (semua refresh token dalam satu family).
+// @Description  Sesi diambil dari refreshToken di body, atau dari access token di header Authorization.
+// @Description  Access token di header ikut dicabut sehingga langsung ditolak walau belum kadaluarsa.
 // @Tags         Authentication
 // @Security     BearerAuth
+// @Accept       json
+// @Param        request body LogoutRequest false "Refresh Token"
 // @Success      200  {object} map[string]string
+// @Failure      401  {object} map[string]string
 // @Router       /auth/logout [post]
 func (s *AuthService) Logout(c *gin.Context) {
+	var input LogoutRequest
+	_ = c.ShouldBindJSON(&input)
+	ctx := c.Request.Context()
+
+	accessToken := strings.Replace(c.GetHeader("Authorization"), "Bearer ", "", 1)
+	if input.RefreshToken == "" && accessToken == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token required"})
+		return
+	}
+
+	var sid string
+	if accessToken != "" {
+		claims, err := s.validateToken(accessToken)
+		if err == nil && claims["type"] == TokenTypeAccess {
+			// Access token ikut dicabut supaya tidak bisa dipakai lagi sampai exp
+			jti, _ := claims["jti"].(string)
+			exp, _ := claims.GetExpirationTime()
+			if _, perr := uuid.Parse(jti); perr == nil && exp != nil {
+				if err := s.TokenRepo.RevokeAccessToken(ctx, jti, exp.Time); err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal logout"})
+					return
+				}
+			}
+			sid, _ = claims["sid"].(string)
+		} else if input.RefreshToken == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			return
+		}
+	}
+
+	if input.RefreshToken != "" {
+		claims, err := s.validateToken(input.RefreshToken)
+		if err != nil || claims["type"] != TokenTypeRefresh {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			return
+		}
+		sid, _ = claims["sid"].(string)
+	}
+
+	if sid != "" {
+		if err := s.TokenRepo.RevokeFamily(ctx, sid); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal logout"})
+			return
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Logged out successfully"})
 }
 
@@ -145,12 +259,23 @@ func (s *AuthService) Profile(c *gin.Context) {
 	tokenString := strings.Replace(authHeader, "Bearer ", "", 1)
 	
 	claims, err := s.validateToken(tokenString)
+	if err != nil || claims["type"] != TokenTypeAccess {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		return
+	}
+
+	jti, _ := claims["jti"].(string)
+	revoked, err := s.IsAccessTokenRevoked(c.Request.Context(), jti)
 	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa token"})
+		return
+	}
+	if revoked {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 		return
 	}
 
-	userID := claims["user_id"].(string)
+	userID, _ := claims["user_id"].(string)
 
 	user, err := s.UserRepo.FindByID(c.Request.Context(), userID)
 	if err != nil {
@@ -164,10 +289,44 @@ func (s *AuthService) Profile(c *gin.Context) {
 	})
 }
 
-func (s *AuthService) generateToken(userID, roleID string, duration time.Duration) (string, error) {
+// IsAccessTokenRevoked dipakai AuthMiddleware; jti yang bukan UUID dianggap sudah dicabut.
+func (s *AuthService) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	if _, err := uuid.Parse(jti); err != nil {
+		return true, nil
+	}
+	return s.TokenRepo.IsAccessTokenRevoked(ctx, jti)
+}
+
+// issueTokens membuat access token + refresh token untuk sesi sid dan menyimpan jti refresh token.
+func (s *AuthService) issueTokens(ctx context.Context, userID, roleID, sid string) (string, string, error) {
+	rt := postgres.RefreshToken{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		FamilyID:  sid,
+		ExpiresAt: time.Now().Add(refreshTokenTTL),
+	}
+	if err := s.TokenRepo.Create(ctx, &rt); err != nil {
+		return "", "", err
+	}
+
+	accessToken, err := s.generateToken(userID, roleID, TokenTypeAccess, uuid.New().String(), sid, accessTokenTTL)
+	if err != nil {
+		return "", "", err
+	}
+	refreshToken, err := s.generateToken(userID, roleID, TokenTypeRefresh, rt.ID, sid, refreshTokenTTL)
+	if err != nil {
+		return "", "", err
+	}
+	return accessToken, refreshToken, nil
+}
+
+func (s *AuthService) generateToken(userID, roleID, tokenType, jti, sid string, duration time.Duration) (string, error) {
 	claims := jwt.MapClaims{
 		"user_id": userID,
 		"role_id": roleID,
+		"type":    tokenType,
+		"jti":     jti,
+		"sid":     sid,
 		"exp":     time.Now().Add(duration).Unix(),
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -177,7 +336,7 @@ func (s *AuthService) generateToken(userID, roleID string, duration time.Duratio
 func (s *AuthService) validateToken(tokenString string) (jwt.MapClaims, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		return []byte(os.Getenv("JWT_SECRET")), nil
-	})
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,649 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"pelaporan_prestasi/app/badge"
+	mongodb "pelaporan_prestasi/app/models/mongo"
+	"pelaporan_prestasi/app/models/postgres"
+	"pelaporan_prestasi/app/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	ob2Context = "https://w3id.org/openbadges/v2"
+	vcContext  = "https://www.w3.org/ns/credentials/v2"
+	ob3Context = "https://purl.imsglobal.org/spec/ob/v3p0/context-3.0.3.json"
+)
+
+// BadgeIssuer adalah profil penerbit badge (BADGE_ISSUER_*).
+type BadgeIssuer struct {
+	Name        string
+	URL         string
+	Email       string
+	Description string
+	Image       string
+}
+
+// BadgeService mengekspor prestasi VERIFIED sebagai Open Badges 2.0 (assertion hosted) dan 3.0
+// (OpenBadgeCredential, hanya sebagai VC-JWT bertanda tangan kunci atestasi), dalam bentuk JSON/JWT
+// maupun gambar PNG/SVG yang sudah di-bake, serta meng-host issuer, kunci, badge class dan assertion-nya.
+type BadgeService struct {
+	Repo         *repository.BadgeRepository
+	AchRepo      *repository.AchievementRepository
+	Attestations *AttestationService
+	Issuer       BadgeIssuer
+	// BaseURL adalah URL publik API, mis. https://prestasi.example.ac.id
+	BaseURL string
+
+	imageMu sync.Mutex
+	images  map[string]cachedBadgeImage
+}
+
+// cachedBadgeImage adalah PNG badge class hasil render untuk satu versi konten prestasi.
+type cachedBadgeImage struct {
+	version int
+	png     []byte
+}
+
+// maxCachedBadgeImages membatasi cache render; cache dikosongkan saat penuh.
+const maxCachedBadgeImages = 1000
+
+func NewBadgeService(repo *repository.BadgeRepository, achRepo *repository.AchievementRepository, attestations *AttestationService, issuer BadgeIssuer, baseURL string) *BadgeService {
+	return &BadgeService{Repo: repo, AchRepo: achRepo, Attestations: attestations, Issuer: issuer, BaseURL: strings.TrimRight(baseURL, "/") + "/api/v1/badges", images: map[string]cachedBadgeImage{}}
+}
+
+var badgeColors = map[string]color.RGBA{
+	"competition":   {212, 160, 23, 255},
+	"publication":   {13, 110, 253, 255},
+	"organization":  {25, 135, 84, 255},
+	"certification": {111, 66, 193, 255},
+}
+
+// ob3AchievementTypes memetakan tipe prestasi ke enumerasi achievementType Open Badges 3.0.
+var ob3AchievementTypes = map[string]string{
+	"competition":   "Award",
+	"publication":   "Achievement",
+	"organization":  "Membership",
+	"certification": "Certification",
+}
+
+func (s *BadgeService) issuerURL() string                 { return s.BaseURL + "/issuer" }
+func (s *BadgeService) keyURL(keyID string) string        { return s.issuerURL() + "/keys/" + keyID }
+func (s *BadgeService) classURL(refID string) string      { return s.BaseURL + "/classes/" + refID }
+func (s *BadgeService) assertionURL(id string) string     { return s.BaseURL + "/assertions/" + id }
+func (s *BadgeService) classImageURL(refID string) string { return s.classURL(refID) + "/image" }
+
+func typeLabelEN(achievementType string) string {
+	if l, ok := skpiTypeLabels[achievementType]; ok {
+		return l[1]
+	}
+	return achievementType
+}
+
+func (s *BadgeService) badgeImage(content mongodb.Achievement) badge.Image {
+	col, ok := badgeColors[content.AchievementType]
+	if !ok {
+		col = color.RGBA{108, 117, 125, 255}
+	}
+	return badge.Image{Title: content.Title, Label: typeLabelEN(content.AchievementType), Issuer: s.Issuer.Name, Color: col}
+}
+
+// recipientHash meng-hash email penerima sesuai Open Badges: "sha256$" + hex(sha256(email + salt)).
+func recipientHash(email, salt string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email)) + salt))
+	return "sha256$" + hex.EncodeToString(sum[:])
+}
+
+// badgeState memuat prestasi milik assertion dan menentukan apakah badge sudah dicabut: prestasi
+// dihapus atau tidak lagi VERIFIED, penerima bukan lagi anggota tim, atau atestasinya dicabut.
+func (s *BadgeService) badgeState(ctx context.Context, a *postgres.BadgeAssertion) (*postgres.AchievementReference, *mongodb.Achievement, string, error) {
+	ref, err := s.AchRepo.FindRefByID(ctx, a.AchievementID)
+	if err != nil {
+		return nil, nil, "Prestasi sudah dihapus", nil
+	}
+	if ref.Status != StatusVerified {
+		return ref, nil, "Prestasi tidak lagi berstatus VERIFIED", nil
+	}
+	member, err := s.AchRepo.IsMemberOfRef(ctx, ref.ID, a.StudentID)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if !member {
+		return ref, nil, "Penerima bukan lagi anggota tim prestasi", nil
+	}
+	att, err := s.Attestations.Repo.FindLatestByAchievement(ctx, ref.ID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, nil, "", err
+	}
+	if att != nil && att.RevokedAt != nil {
+		reason := "Atestasi verifikasi dicabut"
+		if att.RevocationReason != nil {
+			reason += ": " + *att.RevocationReason
+		}
+		return ref, nil, reason, nil
+	}
+	content, err := s.AchRepo.FindContentByMongoID(ctx, ref.MongoAchievementID)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return ref, content, "", nil
+}
+
+func (s *BadgeService) issuerProfile() gin.H {
+	profile := gin.H{"@context": ob2Context, "type": "Issuer", "id": s.issuerURL(), "name": s.Issuer.Name, "url": s.Issuer.URL}
+	if s.Issuer.Email != "" {
+		profile["email"] = s.Issuer.Email
+	}
+	if s.Issuer.Description != "" {
+		profile["description"] = s.Issuer.Description
+	}
+	if s.Issuer.Image != "" {
+		profile["image"] = s.Issuer.Image
+	}
+	return profile
+}
+
+// ob3Profile adalah profil penerbit Open Badges 3.0 yang mencantumkan kunci penanda tangan VC-JWT.
+// kid di header JWT menunjuk salah satu URL kunci di sini.
+func (s *BadgeService) ob3Profile(keys []postgres.AttestationKey) gin.H {
+	profile := gin.H{"@context": []string{vcContext, ob3Context}, "id": s.issuerURL(), "type": []string{"Profile"}, "name": s.Issuer.Name, "url": s.Issuer.URL}
+	if s.Issuer.Email != "" {
+		profile["email"] = s.Issuer.Email
+	}
+	if s.Issuer.Description != "" {
+		profile["description"] = s.Issuer.Description
+	}
+	methods := make([]gin.H, 0, len(keys))
+	for _, k := range keys {
+		if jwk, ok := s.publicJWK(k); ok {
+			methods = append(methods, gin.H{"id": s.keyURL(k.KeyID), "type": "JsonWebKey", "controller": s.issuerURL(), "publicKeyJwk": jwk})
+		}
+	}
+	profile["verificationMethod"] = methods
+	return profile
+}
+
+// publicJWK mengubah kunci atestasi menjadi JWK Ed25519.
+func (s *BadgeService) publicJWK(k postgres.AttestationKey) (gin.H, bool) {
+	pub, err := base64.StdEncoding.DecodeString(k.PublicKey)
+	if err != nil {
+		return nil, false
+	}
+	return gin.H{"kty": "OKP", "crv": "Ed25519", "kid": s.keyURL(k.KeyID), "x": base64.RawURLEncoding.EncodeToString(pub)}, true
+}
+
+func (s *BadgeService) criteria(content mongodb.Achievement) string {
+	return fmt.Sprintf("Verified %s achievement, reviewed and approved by an academic advisor at %s.", strings.ToLower(typeLabelEN(content.AchievementType)), s.Issuer.Name)
+}
+
+func (s *BadgeService) badgeClass(ref *postgres.AchievementReference, content mongodb.Achievement) gin.H {
+	class := gin.H{
+		"@context":    ob2Context,
+		"type":        "BadgeClass",
+		"id":          s.classURL(ref.ID),
+		"name":        content.Title,
+		"description": content.Description,
+		"image":       s.classImageURL(ref.ID),
+		"criteria":    gin.H{"narrative": s.criteria(content)},
+		"issuer":      s.issuerURL(),
+	}
+	if len(content.Tags) > 0 {
+		class["tags"] = content.Tags
+	}
+	return class
+}
+
+// evidence menautkan atestasi bertanda tangan (jika ada) sebagai bukti verifikasi.
+func (s *BadgeService) evidence(ctx context.Context, refID string) []gin.H {
+	att, err := s.Attestations.Repo.FindLatestByAchievement(ctx, refID)
+	if err != nil {
+		return nil
+	}
+	return []gin.H{{"id": s.Attestations.toResponse(att).VerifyURL, "narrative": "Ed25519-signed verification attestation"}}
+}
+
+func (s *BadgeService) ob2Assertion(ctx context.Context, a *postgres.BadgeAssertion, ref *postgres.AchievementReference, email string) gin.H {
+	assertion := gin.H{
+		"@context": ob2Context,
+		"type":     "Assertion",
+		"id":       s.assertionURL(a.ID),
+		"recipient": gin.H{
+			"type":     "email",
+			"hashed":   true,
+			"salt":     a.Salt,
+			"identity": recipientHash(email, a.Salt),
+		},
+		"badge":        s.classURL(ref.ID),
+		"issuedOn":     a.IssuedOn.UTC().Format(time.RFC3339),
+		"verification": gin.H{"type": "hosted"},
+		"image":        s.classImageURL(ref.ID),
+	}
+	if ev := s.evidence(ctx, ref.ID); ev != nil {
+		assertion["evidence"] = ev
+	}
+	return assertion
+}
+
+func (s *BadgeService) ob3Credential(ctx context.Context, a *postgres.BadgeAssertion, ref *postgres.AchievementReference, content mongodb.Achievement, email string) gin.H {
+	achievementType, ok := ob3AchievementTypes[content.AchievementType]
+	if !ok {
+		achievementType = "Achievement"
+	}
+	issuer := gin.H{"id": s.issuerURL(), "type": []string{"Profile"}, "name": s.Issuer.Name, "url": s.Issuer.URL}
+	if s.Issuer.Email != "" {
+		issuer["email"] = s.Issuer.Email
+	}
+	credential := gin.H{
+		"@context":  []string{vcContext, ob3Context},
+		"id":        s.assertionURL(a.ID) + "?version=3",
+		"type":      []string{"VerifiableCredential", "OpenBadgeCredential"},
+		"issuer":    issuer,
+		"validFrom": a.IssuedOn.UTC().Format(time.RFC3339),
+		"name":      content.Title,
+		"credentialSubject": gin.H{
+			"type": []string{"AchievementSubject"},
+			"identifier": []gin.H{{
+				"type":         "IdentityObject",
+				"identityHash": recipientHash(email, a.Salt),
+				"identityType": "emailAddress",
+				"hashed":       true,
+				"salt":         a.Salt,
+			}},
+			"achievement": gin.H{
+				"id":              s.classURL(ref.ID),
+				"type":            []string{"Achievement"},
+				"achievementType": achievementType,
+				"name":            content.Title,
+				"description":     content.Description,
+				"criteria":        gin.H{"narrative": s.criteria(content)},
+				"image":           gin.H{"id": s.classImageURL(ref.ID), "type": "Image"},
+			},
+		},
+	}
+	if ev := s.evidence(ctx, ref.ID); ev != nil {
+		credential["evidence"] = ev
+	}
+	return credential
+}
+
+// signJWT menandatangani credential sebagai VC-JWT (JWS compact, EdDSA) dengan kunci atestasi.
+// kid menunjuk kunci di profil penerbit (/badges/issuer/keys/:kid), bukan kunci yang disisipkan di
+// header, supaya verifier hanya mempercayai kunci yang dipublikasikan penerbit.
+func (s *BadgeService) signJWT(credential gin.H) (string, error) {
+	signer := s.Attestations.Signer
+	header, err := json.Marshal(gin.H{
+		"alg": "EdDSA",
+		"typ": "vc+jwt",
+		"kid": s.keyURL(signer.KeyID),
+	})
+	if err != nil {
+		return "", err
+	}
+	claims := gin.H{}
+	for k, v := range credential {
+		claims[k] = v
+	}
+	claims["iss"] = s.issuerURL()
+	claims["jti"] = credential["id"]
+	if from, err := time.Parse(time.RFC3339, fmt.Sprint(credential["validFrom"])); err == nil {
+		claims["nbf"] = from.Unix()
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return input + "." + base64.RawURLEncoding.EncodeToString(signer.Sign([]byte(input))), nil
+}
+
+func newBadgeSalt() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+type BadgeExportQuery struct {
+	Format  string `form:"format" binding:"omitempty,oneof=json jwt png svg"`
+	Version int    `form:"version" binding:"omitempty,oneof=2 3"`
+}
+
+// ExportBadge godoc
+// @Summary Export Open Badge
+// @Description Ekspor prestasi VERIFIED milik mahasiswa login (termasuk prestasi tim yang ia ikuti) sebagai Open Badge. version=2 (default) menghasilkan assertion hosted; version=3 menghasilkan OpenBadgeCredential yang hanya diberikan sebagai VC-JWT bertanda tangan. format=json (JSON-LD, hanya v2, default v2), jwt (hanya v3, default v3), png atau svg (gambar yang sudah di-bake).
+// @Tags Badges
+// @Security BearerAuth
+// @Param id path string true "ID prestasi"
+// @Param format query string false "json | jwt | png | svg"
+// @Param version query int false "2 | 3"
+// @Success 200 {object} map[string]interface{}
+// @Router /achievements/{id}/badge [get]
+func (s *BadgeService) ExportBadge(c *gin.Context) {
+	var q BadgeExportQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if q.Version == 0 {
+		q.Version = 2
+	}
+	if q.Format == "" {
+		q.Format = "json"
+		if q.Version == 3 {
+			q.Format = "jwt"
+		}
+	}
+	if q.Format == "jwt" && q.Version != 3 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format jwt hanya untuk version=3"})
+		return
+	}
+	// OpenBadgeCredential tanpa proof tidak bisa diverifikasi, jadi v3 hanya diberikan sebagai VC-JWT
+	if q.Format == "json" && q.Version == 3 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "version=3 hanya tersedia sebagai jwt, png atau svg"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	userID := c.GetString("user_id")
+	ref, err := s.AchRepo.FindRefByID(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+		return
+	}
+	member, err := s.AchRepo.IsMemberOfRef(ctx, ref.ID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !member {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Badge hanya bisa diekspor oleh mahasiswa penerima prestasi"})
+		return
+	}
+	if ref.Status != StatusVerified {
+		c.JSON(http.StatusConflict, gin.H{"error": "Hanya prestasi VERIFIED yang bisa diekspor sebagai badge"})
+		return
+	}
+
+	assertion, err := s.Repo.FindOrCreate(ctx, ref.ID, userID, newBadgeSalt())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ref, content, revoked, err := s.badgeState(ctx, assertion)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if revoked != "" {
+		c.JSON(http.StatusGone, gin.H{"error": revoked})
+		return
+	}
+	email, err := s.Repo.FindRecipientEmail(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Data yang di-bake: assertion JSON (v2) atau VC-JWT (v3)
+	var data string
+	keyword := badge.KeywordOB2
+	if q.Version == 3 {
+		credential := s.ob3Credential(ctx, assertion, ref, *content, email)
+		if data, err = s.signJWT(credential); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		keyword = badge.KeywordOB3
+	} else {
+		doc := s.ob2Assertion(ctx, assertion, ref, email)
+		if q.Format == "json" {
+			c.JSON(http.StatusOK, doc)
+			return
+		}
+		raw, _ := json.Marshal(doc)
+		data = string(raw)
+	}
+
+	name := "badge-" + assertion.ID
+	switch q.Format {
+	case "jwt":
+		c.Data(http.StatusOK, "application/vc+jwt", []byte(data))
+	case "svg":
+		verify := ""
+		if q.Version == 2 {
+			verify = s.assertionURL(assertion.ID)
+		}
+		baked, err := badge.BakeSVG(badge.RenderSVG(s.badgeImage(*content)), keyword, data, verify)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Header("Content-Disposition", contentDisposition("attachment", name+".svg"))
+		c.Data(http.StatusOK, "image/svg+xml", baked)
+	case "png":
+		img, err := badge.RenderPNG(s.badgeImage(*content))
+		if err == nil {
+			img, err = badge.BakePNG(img, keyword, data)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Header("Content-Disposition", contentDisposition("attachment", name+".png"))
+		c.Data(http.StatusOK, "image/png", img)
+	}
+}
+
+// GetIssuer godoc
+// @Summary Open Badges Issuer Profile
+// @Description Profil penerbit (hosted, publik). version=3 mengembalikan Profile Open Badges 3.0 beserta kunci penanda tangan VC-JWT (verificationMethod), termasuk kunci lama yang sudah dirotasi.
+// @Tags Badges
+// @Param version query int false "2 | 3"
+// @Success 200 {object} map[string]interface{}
+// @Router /badges/issuer [get]
+func (s *BadgeService) GetIssuer(c *gin.Context) {
+	if c.Query("version") != "3" {
+		c.JSON(http.StatusOK, s.issuerProfile())
+		return
+	}
+	keys, err := s.Attestations.Repo.FindKeys(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, s.ob3Profile(keys))
+}
+
+// GetIssuerKey godoc
+// @Summary Open Badges Issuer Key
+// @Description Kunci publik (JWK Ed25519) yang dirujuk kid di header VC-JWT badge.
+// @Tags Badges
+// @Param kid path string true "Key ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /badges/issuer/keys/{kid} [get]
+func (s *BadgeService) GetIssuerKey(c *gin.Context) {
+	key, err := s.Attestations.Repo.FindKey(c.Request.Context(), c.Param("kid"))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Kunci tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	jwk, ok := s.publicJWK(*key)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Kunci tersimpan tidak valid"})
+		return
+	}
+	c.JSON(http.StatusOK, jwk)
+}
+
+// loadPublicClass memuat prestasi untuk badge class publik; hanya prestasi VERIFIED yang sudah punya
+// assertion yang dipublikasikan.
+func (s *BadgeService) loadPublicClass(c *gin.Context) (*postgres.AchievementReference, *mongodb.Achievement, bool) {
+	ctx := c.Request.Context()
+	// Endpoint publik: id yang bukan UUID langsung 404 tanpa menyentuh DB
+	if _, err := uuid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Badge tidak ditemukan"})
+		return nil, nil, false
+	}
+	exists, err := s.Repo.HasAssertions(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, nil, false
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Badge tidak ditemukan"})
+		return nil, nil, false
+	}
+	ref, err := s.AchRepo.FindRefByID(ctx, c.Param("id"))
+	if err != nil || ref.Status != StatusVerified {
+		c.JSON(http.StatusGone, gin.H{"error": "Badge sudah tidak berlaku"})
+		return nil, nil, false
+	}
+	content, err := s.AchRepo.FindContentByMongoID(ctx, ref.MongoAchievementID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, nil, false
+	}
+	return ref, content, true
+}
+
+// GetBadgeClass godoc
+// @Summary Open Badges BadgeClass
+// @Description BadgeClass prestasi (hosted, publik).
+// @Tags Badges
+// @Param id path string true "ID prestasi"
+// @Success 200 {object} map[string]interface{}
+// @Router /badges/classes/{id} [get]
+func (s *BadgeService) GetBadgeClass(c *gin.Context) {
+	ref, content, ok := s.loadPublicClass(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, s.badgeClass(ref, *content))
+}
+
+// GetBadgeImage godoc
+// @Summary Open Badges BadgeClass Image
+// @Description Gambar PNG badge class (tanpa bake). Hasil render di-cache per versi konten prestasi.
+// @Tags Badges
+// @Produce image/png
+// @Param id path string true "ID prestasi"
+// @Success 200 {file} file
+// @Router /badges/classes/{id}/image [get]
+func (s *BadgeService) GetBadgeImage(c *gin.Context) {
+	ref, content, ok := s.loadPublicClass(c)
+	if !ok {
+		return
+	}
+	version := content.CurrentVersion()
+	etag := fmt.Sprintf(`"%s-%d"`, ref.ID, version)
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Header("ETag", etag)
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	img, err := s.classImage(ref.ID, *content)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, "image/png", img)
+}
+
+// classImage merender PNG badge class, memakai cache selama versi konten belum berubah.
+func (s *BadgeService) classImage(refID string, content mongodb.Achievement) ([]byte, error) {
+	version := content.CurrentVersion()
+	s.imageMu.Lock()
+	cached, ok := s.images[refID]
+	s.imageMu.Unlock()
+	if ok && cached.version == version {
+		return cached.png, nil
+	}
+
+	img, err := badge.RenderPNG(s.badgeImage(content))
+	if err != nil {
+		return nil, err
+	}
+	s.imageMu.Lock()
+	if len(s.images) >= maxCachedBadgeImages {
+		s.images = map[string]cachedBadgeImage{}
+	}
+	s.images[refID] = cachedBadgeImage{version: version, png: img}
+	s.imageMu.Unlock()
+	return img, nil
+}
+
+// GetAssertion godoc
+// @Summary Open Badges Hosted Assertion
+// @Description Assertion hosted untuk verifikasi. Badge yang dicabut menghasilkan 410 dengan revoked=true sesuai spesifikasi Open Badges. version=3 mengembalikan OpenBadgeCredential sebagai VC-JWT bertanda tangan.
+// @Tags Badges
+// @Param id path string true "ID assertion"
+// @Param version query int false "2 | 3"
+// @Success 200 {object} map[string]interface{}
+// @Failure 410 {object} map[string]interface{}
+// @Router /badges/assertions/{id} [get]
+func (s *BadgeService) GetAssertion(c *gin.Context) {
+	ctx := c.Request.Context()
+	if _, err := uuid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Assertion tidak ditemukan"})
+		return
+	}
+	assertion, err := s.Repo.FindByID(ctx, c.Param("id"))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Assertion tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ref, content, revoked, err := s.badgeState(ctx, assertion)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if revoked != "" {
+		c.JSON(http.StatusGone, gin.H{
+			"@context":         ob2Context,
+			"type":             "Assertion",
+			"id":               s.assertionURL(assertion.ID),
+			"revoked":          true,
+			"revocationReason": revoked,
+		})
+		return
+	}
+	email, err := s.Repo.FindRecipientEmail(ctx, assertion.StudentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if c.Query("version") == "3" {
+		token, err := s.signJWT(s.ob3Credential(ctx, assertion, ref, *content, email))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Data(http.StatusOK, "application/vc+jwt", []byte(token))
+		return
+	}
+	c.JSON(http.StatusOK, s.ob2Assertion(ctx, assertion, ref, email))
+}
@@ -2,15 +2,17 @@ package service
 
 import (
 	"pelaporan_prestasi/app/repository"
+	"pelaporan_prestasi/middleware"
 	"github.com/gin-gonic/gin"
 )
 
 type DosenService struct {
-	Repo *repository.DosenRepository
+	Repo  *repository.DosenRepository
+	Perms *middleware.PermissionCache
 }
 
-func NewDosenService(repo *repository.DosenRepository) *DosenService {
-	return &DosenService{Repo: repo}
+func NewDosenService(repo *repository.DosenRepository, perms *middleware.PermissionCache) *DosenService {
+	return &DosenService{Repo: repo, Perms: perms}
 }
 
 // GetAll godoc
@@ -20,7 +22,7 @@ func NewDosenService(repo *repository.DosenRepository) *DosenService {
 // @Router /dosen [get]
 func (s *DosenService) GetAll(c *gin.Context) {
     loginUserID := c.GetString("user_id")
-    loginRoleID := c.GetString("role_id")
+    readAll := s.Perms.Can(c, "dosen", "read_all")
 
     data, err := s.Repo.GetAll(c.Request.Context())
     if err != nil {
@@ -30,9 +32,7 @@ func (s *DosenService) GetAll(c *gin.Context) {
 
     var filteredData []repository.DosenData
     for _, d := range data {
-        if loginRoleID == RoleAdmin {
-            filteredData = append(filteredData, d)
-        } else if loginRoleID == RoleDosen && d.UserID == loginUserID {
+        if readAll || d.UserID == loginUserID {
             filteredData = append(filteredData, d)
         }
     }
@@ -52,14 +52,13 @@ func (s *DosenService) GetAll(c *gin.Context) {
 func (s *DosenService) GetAdvisees(c *gin.Context) {
 	dosenID := c.Param("id") 
 	loginUserID := c.GetString("user_id")
-	loginRoleID := c.GetString("role_id")
 	dosen, err := s.Repo.GetByID(c.Request.Context(), dosenID)
 	if err != nil {
 		c.JSON(404, gin.H{"error": "Dosen tidak ditemukan"})
 		return
 	}
 
-	if loginRoleID == RoleDosen && dosen.UserID != loginUserID {
+	if dosen.UserID != loginUserID && !s.Perms.Can(c, "dosen", "read_all") {
 		c.JSON(403, gin.H{"error": "Forbidden: Anda tidak diperbolehkan melihat bimbingan dosen lain"})
 		return
 	}
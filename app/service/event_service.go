@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"pelaporan_prestasi/app/models/postgres"
+	"pelaporan_prestasi/app/repository"
+	"pelaporan_prestasi/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EventService mengelola katalog kompetisi/event resmi dan laporan prestasi per event.
+type EventService struct {
+	Repo    *repository.EventRepository
+	AchRepo *repository.AchievementRepository
+	Perms   *middleware.PermissionCache
+}
+
+func NewEventService(repo *repository.EventRepository, achRepo *repository.AchievementRepository, perms *middleware.PermissionCache) *EventService {
+	return &EventService{Repo: repo, AchRepo: achRepo, Perms: perms}
+}
+
+type EventRequest struct {
+	Name                 string `json:"name" binding:"required" example:"Gemastik XVII"`
+	Organizer            string `json:"organizer" binding:"required" example:"Puspresnas"`
+	Level                string `json:"level" binding:"required" example:"national"`
+	Year                 int    `json:"year" binding:"required,min=1900,max=2100" example:"2024"`
+	NationallyRecognized bool   `json:"nationally_recognized" example:"true"`
+}
+
+func (req EventRequest) toEvent(id string) (postgres.Event, error) {
+	valid := false
+	for _, l := range CompetitionLevels {
+		valid = valid || l == req.Level
+	}
+	if !valid {
+		return postgres.Event{}, errors.New("level tidak dikenal: " + req.Level)
+	}
+	return postgres.Event{
+		ID:                   id,
+		Name:                 strings.TrimSpace(req.Name),
+		Organizer:            strings.TrimSpace(req.Organizer),
+		Level:                req.Level,
+		Year:                 req.Year,
+		NationallyRecognized: req.NationallyRecognized,
+	}, nil
+}
+
+// errUnknownEvent: event_id prestasi tidak ada di katalog.
+var errUnknownEvent = errors.New("event_id tidak ada di katalog event")
+
+// applyEvent memvalidasi event_id prestasi dan memakai data katalog sebagai default details.
+// Untuk kompetisi, eventName/organizer/level yang kosong diisi dari event, dan level yang diisi
+// harus sama dengan tingkat event.
+func applyEvent(ctx context.Context, events *repository.EventRepository, eventID, achievementType string, details map[string]interface{}) error {
+	if eventID == "" {
+		return nil
+	}
+	ev, err := events.FindByID(ctx, eventID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return errUnknownEvent
+	}
+	if err != nil {
+		return err
+	}
+	if achievementType != "competition" {
+		return nil
+	}
+
+	defaults := map[string]string{"eventName": ev.Name, "organizer": ev.Organizer, "level": ev.Level}
+	for key, val := range defaults {
+		if raw, ok := details[key]; !ok || raw == nil || raw == "" {
+			details[key] = val
+		}
+	}
+	if level, _ := details["level"].(string); level != ev.Level {
+		return &DetailValidationError{Fields: map[string]string{"level": "harus sama dengan tingkat event (" + ev.Level + ")"}}
+	}
+	return nil
+}
+
+// writeApplyEventError: kesalahan input dari applyEvent dibalas 400, kegagalan repository 500.
+func writeApplyEventError(c *gin.Context, err error) {
+	var verr *DetailValidationError
+	if errors.Is(err, errUnknownEvent) || errors.As(err, &verr) {
+		writeDetailError(c, err)
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+func writeEventError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event tidak ditemukan"})
+	case errors.Is(err, repository.ErrEventExists):
+		c.JSON(http.StatusConflict, gin.H{"error": "Event dengan nama dan tahun yang sama sudah ada"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+type EventQuery struct {
+	Query      string `form:"q"`
+	Level      string `form:"level"`
+	Year       int    `form:"year"`
+	Recognized *bool  `form:"recognized"`
+}
+
+// GetEvents godoc
+// @Summary      List Events
+// @Description  Katalog event untuk dipilih saat membuat prestasi.
+// @Tags         Events
+// @Security     BearerAuth
+// @Param        q query string false "Cari nama / penyelenggara"
+// @Param        level query string false "campus | regional | national | international"
+// @Param        year query int false "Tahun"
+// @Param        recognized query bool false "Hanya event rekognisi nasional"
+// @Success      200  {object} map[string]interface{}
+// @Router       /events [get]
+func (s *EventService) GetEvents(c *gin.Context) {
+	var q EventQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	events, err := s.Repo.Find(c.Request.Context(), repository.EventFilter{
+		Query:      strings.TrimSpace(q.Query),
+		Level:      q.Level,
+		Year:       q.Year,
+		Recognized: q.Recognized,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": events})
+}
+
+// GetEvent godoc
+// @Summary      Get Event
+// @Tags         Events
+// @Security     BearerAuth
+// @Param        id   path string true "Event ID"
+// @Success      200  {object} postgres.Event
+// @Router       /events/{id} [get]
+func (s *EventService) GetEvent(c *gin.Context) {
+	ev, err := s.Repo.FindByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeEventError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": ev})
+}
+
+// CreateEvent godoc
+// @Summary      Create Event
+// @Tags         Events
+// @Security     BearerAuth
+// @Param        request body EventRequest true "Event"
+// @Success      201  {object} map[string]interface{}
+// @Router       /events [post]
+func (s *EventService) CreateEvent(c *gin.Context) {
+	var req EventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ev, err := req.toEvent("")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.Repo.Create(c.Request.Context(), &ev); err != nil {
+		writeEventError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"status": "success", "data": ev})
+}
+
+// UpdateEvent godoc
+// @Summary      Update Event
+// @Description  Prestasi yang sudah tertaut tidak ikut diubah; level baru berlaku saat poin dihitung ulang.
+// @Tags         Events
+// @Security     BearerAuth
+// @Param        id   path string true "Event ID"
+// @Param        request body EventRequest true "Event"
+// @Success      200  {object} map[string]interface{}
+// @Router       /events/{id} [put]
+func (s *EventService) UpdateEvent(c *gin.Context) {
+	var req EventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ev, err := req.toEvent(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.Repo.Update(c.Request.Context(), &ev); err != nil {
+		writeEventError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": ev})
+}
+
+// DeleteEvent godoc
+// @Summary      Delete Event
+// @Description  Event yang masih ditautkan prestasi (termasuk di tong sampah) tidak bisa dihapus (409).
+// @Tags         Events
+// @Security     BearerAuth
+// @Param        id   path string true "Event ID"
+// @Success      200  {object} map[string]string
+// @Router       /events/{id} [delete]
+func (s *EventService) DeleteEvent(c *gin.Context) {
+	used, err := s.AchRepo.CountByEvent(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if used > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Event masih ditautkan %d prestasi", used)})
+		return
+	}
+
+	if err := s.Repo.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		writeEventError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Event deleted"})
+}
+
+// EventReport adalah rekap prestasi yang menautkan satu event.
+type EventReport struct {
+	Event    postgres.Event `json:"event"`
+	Total    int            `json:"total"`
+	ByStatus map[string]int `json:"by_status"`
+	// Students adalah jumlah mahasiswa berbeda (anggota tim) pada prestasi untuk event ini
+	Students       int `json:"students"`
+	VerifiedPoints int `json:"verified_points"`
+}
+
+// GetEventReport godoc
+// @Summary      Achievements per Event
+// @Description  Rekap prestasi per event katalog sesuai scope akses (achievement:read_all semua, selain itu milik sendiri dan mahasiswa bimbingan). Event tanpa prestasi tidak ditampilkan.
+// @Tags         Reports
+// @Security     BearerAuth
+// @Param        level query string false "Filter level event"
+// @Param        year query int false "Filter tahun event"
+// @Param        recognized query bool false "Hanya event rekognisi nasional"
+// @Success      200  {object} map[string]interface{}
+// @Router       /reports/events [get]
+func (s *EventService) GetEventReport(c *gin.Context) {
+	var q EventQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var filter repository.RefFilter
+	if !s.Perms.Can(c, "achievement", "read_all") {
+		filter.VisibleTo = c.GetString("user_id")
+	}
+
+	events, err := s.Repo.Find(c.Request.Context(), repository.EventFilter{Level: q.Level, Year: q.Year, Recognized: q.Recognized})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	eventIDs := make([]string, len(events))
+	for i, ev := range events {
+		eventIDs[i] = ev.ID
+	}
+	stats, err := s.AchRepo.FindEventStats(c.Request.Context(), filter, eventIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	result := []EventReport{}
+	for _, ev := range events {
+		st, ok := stats[ev.ID]
+		if !ok {
+			continue
+		}
+		result = append(result, EventReport{Event: ev, Total: st.Total, ByStatus: st.ByStatus, Students: st.Students, VerifiedPoints: st.VerifiedPoints})
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Total > result[j].Total })
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"pelaporan_prestasi/app/models/dto"
 	"pelaporan_prestasi/app/repository"
+	"pelaporan_prestasi/middleware"
 
 	"github.com/gin-gonic/gin"
 )
@@ -14,21 +15,26 @@ type UpdateAdvisorRequest struct {
 type MahasiswaService struct {
 	Repo    *repository.MahasiswaRepository
 	AchRepo *repository.AchievementRepository
+	Files   *AttachmentFiles
+	Perms   *middleware.PermissionCache
 }
 
-func NewMahasiswaService(repo *repository.MahasiswaRepository, achRepo *repository.AchievementRepository) *MahasiswaService {
-	return &MahasiswaService{Repo: repo, AchRepo: achRepo}
+func NewMahasiswaService(repo *repository.MahasiswaRepository, achRepo *repository.AchievementRepository, files *AttachmentFiles, perms *middleware.PermissionCache) *MahasiswaService {
+	return &MahasiswaService{Repo: repo, AchRepo: achRepo, Files: files, Perms: perms}
+}
+
+// canRead: mahasiswa:read_all melihat semua mahasiswa; selain itu hanya diri sendiri atau mahasiswa bimbingan.
+func (s *MahasiswaService) canRead(c *gin.Context, m *repository.MahasiswaData) bool {
+	loginUserID := c.GetString("user_id")
+	return m.UserID == loginUserID || m.AdvisorUserID == loginUserID || s.Perms.Can(c, "mahasiswa", "read_all")
 }
 
 // GetAll godoc
-// @Summary Get All Mahasiswa (Filtered by Role)
+// @Summary Get All Mahasiswa (Filtered by Permission)
 // @Tags Mahasiswa
 // @Security BearerAuth
 // @Router /mahasiswa [get]
 func (s *MahasiswaService) GetAll(c *gin.Context) {
-	loginUserID := c.GetString("user_id")
-	loginRoleID := c.GetString("role_id")
-
 	data, err := s.Repo.GetAll(c.Request.Context())
 	if err != nil {
 		c.JSON(500, gin.H{"error": "Gagal mengambil data: " + err.Error()})
@@ -37,11 +43,7 @@ func (s *MahasiswaService) GetAll(c *gin.Context) {
 
 	var filteredData []repository.MahasiswaData
 	for _, m := range data {
-		if loginRoleID == RoleAdmin {
-			filteredData = append(filteredData, m)
-		} else if loginRoleID == RoleDosen && m.AdvisorUserID == loginUserID {
-			filteredData = append(filteredData, m)
-		} else if loginRoleID == RoleMahasiswa && m.UserID == loginUserID {
+		if s.canRead(c, &m) {
 			filteredData = append(filteredData, m)
 		}
 	}
@@ -60,8 +62,6 @@ func (s *MahasiswaService) GetAll(c *gin.Context) {
 // @Router /mahasiswa/{id} [get]
 func (s *MahasiswaService) GetDetail(c *gin.Context) {
 	id := c.Param("id")
-	loginUserID := c.GetString("user_id")
-	loginRoleID := c.GetString("role_id")
 
 	data, err := s.Repo.GetByID(c.Request.Context(), id)
 	if err != nil {
@@ -69,10 +69,7 @@ func (s *MahasiswaService) GetDetail(c *gin.Context) {
 		return
 	}
 
-	isOwner := data.UserID == loginUserID
-	isAdvisor := data.AdvisorUserID == loginUserID
-
-	if loginRoleID != RoleAdmin && !isOwner && !isAdvisor {
+	if !s.canRead(c, data) {
 		c.JSON(403, gin.H{"error": "Forbidden: Anda tidak memiliki akses ke data ini"})
 		return
 	}
@@ -81,7 +78,7 @@ func (s *MahasiswaService) GetDetail(c *gin.Context) {
 
 // GetAchievements godoc
 // @Summary Get Mahasiswa Achievements
-// @Description Mengambil daftar prestasi mahasiswa
+// @Description Mengambil daftar prestasi mahasiswa. Referensi yang kontennya hilang dilaporkan di "missing".
 // @Tags Mahasiswa
 // @Accept json
 // @Produce json
@@ -91,8 +88,6 @@ func (s *MahasiswaService) GetDetail(c *gin.Context) {
 // @Router /mahasiswa/{id}/achievements [get]
 func (s *MahasiswaService) GetAchievements(c *gin.Context) {
 	id := c.Param("id")
-	loginUserID := c.GetString("user_id")
-	loginRoleID := c.GetString("role_id")
 
 	mhs, err := s.Repo.GetByID(c.Request.Context(), id)
 	if err != nil {
@@ -100,7 +95,7 @@ func (s *MahasiswaService) GetAchievements(c *gin.Context) {
 		return
 	}
 
-	if loginRoleID != RoleAdmin && mhs.UserID != loginUserID && mhs.AdvisorUserID != loginUserID {
+	if !s.canRead(c, mhs) {
 		c.JSON(403, gin.H{"error": "Forbidden: Hanya bisa melihat prestasi sendiri atau bimbingan"})
 		return
 	}
@@ -111,19 +106,20 @@ func (s *MahasiswaService) GetAchievements(c *gin.Context) {
 		return
 	}
 
-	var results []dto.AchievementResponse
-	for _, ref := range refs {
-		content, _ := s.AchRepo.FindContentByMongoID(c.Request.Context(), ref.MongoAchievementID)
-		if content != nil {
-			results = append(results, dto.ToAchievementResponse(ref, *content))
-		}
+	contents, err := s.AchRepo.FindContentByMongoIDs(c.Request.Context(), mongoIDsOf(refs))
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
 	}
-	c.JSON(200, gin.H{"data": results})
+	s.Files.SignAll(c.Request.Context(), contents)
+
+	results, missing := dto.ToAchievementResponses(refs, contents)
+	c.JSON(200, gin.H{"data": results, "missing": missing})
 }
 
 // UpdateAdvisor godoc
 // @Summary Update Dosen Wali
-// @Description Mengubah dosen wali mahasiswa (Admin Only)
+// @Description Mengubah dosen wali mahasiswa (butuh permission mahasiswa:assign_advisor)
 // @Tags Mahasiswa
 // @Accept json
 // @Produce json
@@ -133,11 +129,6 @@ func (s *MahasiswaService) GetAchievements(c *gin.Context) {
 // @Success 200 {object} map[string]interface{}
 // @Router /mahasiswa/{id}/advisor [put]
 func (s *MahasiswaService) UpdateAdvisor(c *gin.Context) {
-	if c.GetString("role_id") != RoleAdmin {
-		c.JSON(403, gin.H{"error": "Forbidden: Hanya Admin"})
-		return
-	}
-
 	id := c.Param("id")
 	var req UpdateAdvisorRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
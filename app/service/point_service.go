@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	mongodb "pelaporan_prestasi/app/models/mongo"
+	"pelaporan_prestasi/app/models/postgres"
+	"pelaporan_prestasi/app/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PointService menghitung poin prestasi dari rulebook di tabel point_rules.
+// Poin tidak lagi diambil dari input mahasiswa; poin dihitung saat prestasi VERIFIED
+// dan dihitung ulang setiap kali rulebook berubah.
+type PointService struct {
+	Repo    *repository.PointRuleRepository
+	AchRepo *repository.AchievementRepository
+	Events  *repository.EventRepository
+
+	// recalc memberi tahu worker Start bahwa rulebook berubah. Buffer 1: perubahan beruntun
+	// digabung menjadi satu hitung ulang berikutnya yang membaca rulebook terbaru.
+	recalc chan struct{}
+}
+
+func NewPointService(repo *repository.PointRuleRepository, achRepo *repository.AchievementRepository, events *repository.EventRepository) *PointService {
+	return &PointService{Repo: repo, AchRepo: achRepo, Events: events, recalc: make(chan struct{}, 1)}
+}
+
+type PointRuleRequest struct {
+	AchievementType string  `json:"achievement_type" binding:"required" example:"competition"`
+	Level           *string `json:"level" example:"national"`
+	Rank            *string `json:"rank" example:"juara_1"`
+	Points          int     `json:"points" binding:"min=0" example:"50"`
+	Description     string  `json:"description" example:"Juara 1 kompetisi nasional"`
+}
+
+// rankFieldByType menentukan field details yang dipakai sebagai kolom rank di rulebook.
+var rankFieldByType = map[string]string{
+	"competition":  "rank",
+	"organization": "position",
+	"publication":  "indexing",
+}
+
+func detailString(details mongodb.AchievementDetails, key string) string {
+	if key == "" {
+		return ""
+	}
+	str, _ := details[key].(string)
+	return str
+}
+
+// Calculate memilih rule paling spesifik yang cocok (level & rank persis > salah satu > wildcard).
+// Tipe tanpa field level memakai level event katalog yang ditautkan, jika ada.
+func (s *PointService) Calculate(ctx context.Context, content mongodb.Achievement) (mongodb.PointsBreakdown, error) {
+	breakdown := mongodb.PointsBreakdown{
+		AchievementType: content.AchievementType,
+		Level:           detailString(content.Details, "level"),
+		Rank:            detailString(content.Details, rankFieldByType[content.AchievementType]),
+	}
+	if breakdown.Level == "" && content.EventID != "" {
+		ev, err := s.Events.FindByID(ctx, content.EventID)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return breakdown, err
+		}
+		if ev != nil {
+			breakdown.Level = ev.Level
+		}
+	}
+
+	rules, err := s.Repo.FindByType(ctx, content.AchievementType)
+	if err != nil {
+		return breakdown, err
+	}
+
+	var best *postgres.PointRule
+	bestScore := -1
+	for i := range rules {
+		rule := &rules[i]
+		score := 0
+		if rule.Level != nil {
+			if *rule.Level != breakdown.Level {
+				continue
+			}
+			score += 2
+		}
+		if rule.Rank != nil {
+			if *rule.Rank != breakdown.Rank {
+				continue
+			}
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = rule, score
+		}
+	}
+
+	if best == nil {
+		breakdown.Note = "Tidak ada rule yang cocok"
+		return breakdown, nil
+	}
+	breakdown.RuleID = best.ID
+	breakdown.Points = best.Points
+	breakdown.Note = best.Description
+	return breakdown, nil
+}
+
+// ApplyToContent menghitung dan menyimpan poin satu dokumen; true jika poin/rule berubah.
+func (s *PointService) ApplyToContent(ctx context.Context, content mongodb.Achievement) (bool, error) {
+	breakdown, err := s.Calculate(ctx, content)
+	if err != nil {
+		return false, err
+	}
+	prev := content.PointsBreakdown
+	if prev != nil && prev.RuleID == breakdown.RuleID && content.Points == breakdown.Points {
+		return false, nil
+	}
+	breakdown.CalculatedAt = time.Now()
+	if err := s.AchRepo.SetPoints(ctx, content.ID.Hex(), breakdown.Points, breakdown); err != nil {
+		return false, err
+	}
+	syncListSummary(ctx, s.AchRepo, content.ID.Hex())
+	return true, nil
+}
+
+// ApplyToRef dipanggil setelah prestasi diverifikasi.
+func (s *PointService) ApplyToRef(ctx context.Context, ref *postgres.AchievementReference) error {
+	content, err := s.AchRepo.FindContentByMongoID(ctx, ref.MongoAchievementID)
+	if err != nil {
+		return err
+	}
+	_, err = s.ApplyToContent(ctx, *content)
+	return err
+}
+
+type RecalculateResult struct {
+	Checked int `json:"checked"`
+	Updated int `json:"updated"`
+	Failed  int `json:"failed"`
+}
+
+// RecalculateVerified menghitung ulang poin semua prestasi VERIFIED.
+func (s *PointService) RecalculateVerified(ctx context.Context) (RecalculateResult, error) {
+	var result RecalculateResult
+	refs, err := s.AchRepo.FindRefs(ctx, repository.RefFilter{Status: StatusVerified})
+	if err != nil {
+		return result, err
+	}
+	contents, err := s.AchRepo.FindContentByMongoIDs(ctx, mongoIDsOf(refs))
+	if err != nil {
+		return result, err
+	}
+
+	for _, content := range contents {
+		result.Checked++
+		changed, err := s.ApplyToContent(ctx, content)
+		if err != nil {
+			result.Failed++
+			continue
+		}
+		if changed {
+			result.Updated++
+		}
+	}
+	return result, nil
+}
+
+// recalculateInBackground dipakai setelah rulebook berubah supaya request admin tidak menunggu.
+// Hitung ulang dijalankan berurutan oleh Start, tidak pernah paralel.
+func (s *PointService) recalculateInBackground() {
+	select {
+	case s.recalc <- struct{}{}:
+	default:
+		// Sudah ada hitung ulang yang menunggu; rulebook terbaru ikut terbaca olehnya
+	}
+}
+
+// Start menjalankan hitung ulang poin yang diminta recalculateInBackground satu per satu sampai ctx selesai.
+func (s *PointService) Start(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.recalc:
+			result, err := s.RecalculateVerified(ctx)
+			if err != nil {
+				log.Printf("⚠️ Hitung ulang poin gagal: %v", err)
+				continue
+			}
+			log.Printf("🔢 Hitung ulang poin: %d dicek, %d berubah, %d gagal", result.Checked, result.Updated, result.Failed)
+		}
+	}
+}
+
+func (req PointRuleRequest) toRule(id string) (postgres.PointRule, error) {
+	if _, ok := FindDetailSchema(req.AchievementType); !ok {
+		return postgres.PointRule{}, errors.New("achievement_type tidak dikenal: " + req.AchievementType)
+	}
+	if req.Level != nil && *req.Level == "" {
+		req.Level = nil
+	}
+	if req.Rank != nil && *req.Rank == "" {
+		req.Rank = nil
+	}
+	if req.Level != nil {
+		valid := false
+		for _, l := range CompetitionLevels {
+			valid = valid || l == *req.Level
+		}
+		if !valid {
+			return postgres.PointRule{}, errors.New("level tidak dikenal: " + *req.Level)
+		}
+	}
+	return postgres.PointRule{
+		ID:              id,
+		AchievementType: req.AchievementType,
+		Level:           req.Level,
+		Rank:            req.Rank,
+		Points:          req.Points,
+		Description:     req.Description,
+	}, nil
+}
+
+// GetRules godoc
+// @Summary      List Point Rules
+// @Tags         Point Rules (Admin)
+// @Security     BearerAuth
+// @Success      200  {object} map[string]interface{}
+// @Router       /point-rules [get]
+func (s *PointService) GetRules(c *gin.Context) {
+	rules, err := s.Repo.FindAll(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rules})
+}
+
+// CreateRule godoc
+// @Summary      Create Point Rule
+// @Description  level/rank kosong = berlaku untuk semua nilai. Poin prestasi VERIFIED dihitung ulang di background.
+// @Tags         Point Rules (Admin)
+// @Security     BearerAuth
+// @Param        request body PointRuleRequest true "Rule"
+// @Success      201  {object} map[string]interface{}
+// @Router       /point-rules [post]
+func (s *PointService) CreateRule(c *gin.Context) {
+	var req PointRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	rule, err := req.toRule("")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.Repo.Create(c.Request.Context(), &rule); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	s.recalculateInBackground()
+	c.JSON(http.StatusCreated, gin.H{"status": "success", "data": rule})
+}
+
+// UpdateRule godoc
+// @Summary      Update Point Rule
+// @Tags         Point Rules (Admin)
+// @Security     BearerAuth
+// @Param        id   path string true "Rule ID"
+// @Param        request body PointRuleRequest true "Rule"
+// @Success      200  {object} map[string]interface{}
+// @Router       /point-rules/{id} [put]
+func (s *PointService) UpdateRule(c *gin.Context) {
+	var req PointRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	rule, err := req.toRule(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.Repo.Update(c.Request.Context(), &rule); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Rule tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	s.recalculateInBackground()
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": rule})
+}
+
+// DeleteRule godoc
+// @Summary      Delete Point Rule
+// @Tags         Point Rules (Admin)
+// @Security     BearerAuth
+// @Param        id   path string true "Rule ID"
+// @Success      200  {object} map[string]string
+// @Router       /point-rules/{id} [delete]
+func (s *PointService) DeleteRule(c *gin.Context) {
+	if err := s.Repo.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Rule tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	s.recalculateInBackground()
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Rule deleted"})
+}
+
+// Recalculate godoc
+// @Summary      Recalculate Points
+// @Description  Hitung ulang poin semua prestasi VERIFIED sekarang juga.
+// @Tags         Point Rules (Admin)
+// @Security     BearerAuth
+// @Success      200  {object} RecalculateResult
+// @Router       /point-rules/recalculate [post]
+func (s *PointService) Recalculate(c *gin.Context) {
+	result, err := s.RecalculateVerified(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+
+	mongodb "pelaporan_prestasi/app/models/mongo"
+)
+
+// PublicDetail adalah satu baris details yang ditampilkan di portofolio publik.
+type PublicDetail struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+// portfolioDetails mengubah details menjadi baris berlabel sesuai urutan schema tipe prestasinya.
+// Hanya field schema yang ikut, dan field private hanya jika showPrivate.
+func portfolioDetails(achievementType string, details mongodb.AchievementDetails, showPrivate bool) []PublicDetail {
+	schema, _ := FindDetailSchema(achievementType)
+	out := []PublicDetail{}
+	for _, f := range schema.Fields {
+		if f.Private && !showPrivate {
+			continue
+		}
+		value := detailString(details, f.Name)
+		if f.Type == FieldStringList {
+			if list, ok := details[f.Name].([]interface{}); ok {
+				parts := []string{}
+				for _, v := range list {
+					if s, ok := v.(string); ok {
+						parts = append(parts, s)
+					}
+				}
+				value = strings.Join(parts, ", ")
+			}
+		}
+		if f.Type == FieldEnum {
+			if l, ok := skpiValueLabels[value]; ok {
+				value = l[0]
+			}
+		}
+		if value != "" {
+			out = append(out, PublicDetail{Label: f.Label, Value: value})
+		}
+	}
+	return out
+}
+
+var portfolioTemplate = template.Must(template.New("portfolio").Funcs(template.FuncMap{
+	"isImage": func(fileType string) bool { return strings.HasPrefix(fileType, "image/") },
+}).Parse(`<!DOCTYPE html>
+<html lang="id">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex, nofollow">
+<title>Portofolio Prestasi - {{.Owner.Name}}</title>
+<style>
+body{font-family:Helvetica,Arial,sans-serif;margin:0;background:#f5f6f8;color:#212529}
+main{max-width:760px;margin:0 auto;padding:32px 16px}
+header{margin-bottom:24px}
+h1{margin:0 0 4px;font-size:28px}
+.muted{color:#6c757d;font-size:14px}
+.card{background:#fff;border-radius:8px;padding:20px;margin-bottom:16px;box-shadow:0 1px 2px rgba(0,0,0,.08)}
+.card h2{margin:4px 0 8px;font-size:19px}
+.type{display:inline-block;font-size:12px;text-transform:uppercase;letter-spacing:.05em;color:#0d6efd}
+dl{display:grid;grid-template-columns:max-content 1fr;gap:4px 16px;margin:12px 0 0;font-size:14px}
+dt{color:#6c757d}dd{margin:0}
+.tags span{display:inline-block;background:#e9ecef;border-radius:12px;padding:2px 10px;margin:8px 6px 0 0;font-size:12px}
+.files{margin-top:12px;font-size:14px}
+.files img{max-width:120px;max-height:120px;display:block;margin-bottom:4px;border-radius:4px}
+.files a{display:inline-block;margin-right:16px;vertical-align:top}
+footer{margin-top:32px;text-align:center}
+</style>
+</head>
+<body>
+<main>
+<header>
+<h1>{{.Owner.Name}}</h1>
+{{if .Owner.ProgramStudy}}<div class="muted">{{.Owner.ProgramStudy}}{{if .Owner.AcademicYear}} &middot; Angkatan {{.Owner.AcademicYear}}{{end}}</div>{{end}}
+{{if .Headline}}<p>{{.Headline}}</p>{{end}}
+<div class="muted">{{len .Achievements}} prestasi terverifikasi &middot; {{.TotalPoints}} poin</div>
+</header>
+{{range .Achievements}}
+<section class="card">
+<span class="type">{{.TypeLabel}}</span>
+<h2>{{.Title}}</h2>
+<div class="muted">{{.Date}} &middot; {{.Points}} poin</div>
+{{if .Description}}<p>{{.Description}}</p>{{end}}
+{{with .Details}}<dl>{{range .}}<dt>{{.Label}}</dt><dd>{{.Value}}</dd>{{end}}</dl>{{end}}
+{{if .Tags}}<div class="tags">{{range .Tags}}<span>{{.}}</span>{{end}}</div>{{end}}
+{{if .Attachments}}<div class="files">{{range .Attachments}}<a href="{{.URL}}" rel="noopener noreferrer" target="_blank">{{if .ThumbnailURL}}<img src="{{.ThumbnailURL}}" alt="">{{else if isImage .FileType}}<img src="{{.URL}}" alt="">{{end}}{{.FileName}}</a>{{end}}</div>{{end}}
+</section>
+{{else}}
+<p class="muted">Belum ada prestasi yang ditampilkan.</p>
+{{end}}
+<footer class="muted">Semua prestasi di halaman ini telah diverifikasi oleh dosen wali.<br>Diperbarui {{.UpdatedAt.Format "02 Jan 2006"}}</footer>
+</main>
+</body>
+</html>
+`))
+
+func renderPortfolioPage(p *PublicPortfolio) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := portfolioTemplate.Execute(&buf, p); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func renderPortfolioError(code int) []byte {
+	msg := "Terjadi kesalahan, coba lagi nanti."
+	if code == http.StatusNotFound {
+		msg = "Portofolio tidak ditemukan atau sudah tidak dibagikan."
+	}
+	return []byte(fmt.Sprintf(`<!DOCTYPE html><html lang="id"><head><meta charset="utf-8"><meta name="robots" content="noindex"><title>%d</title></head><body style="font-family:Helvetica,Arial,sans-serif;text-align:center;padding:64px;color:#6c757d"><p>%s</p></body></html>`, code, msg))
+}
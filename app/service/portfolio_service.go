@@ -0,0 +1,439 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	mongodb "pelaporan_prestasi/app/models/mongo"
+	"pelaporan_prestasi/app/models/postgres"
+	"pelaporan_prestasi/app/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PortfolioService mengelola portofolio publik mahasiswa: halaman opt-in di bawah slug acak yang
+// hanya memuat prestasi VERIFIED yang dipilih mahasiswa. Lampiran hanya tampil untuk prestasi yang
+// show_attachments-nya dinyalakan, lewat URL bertanda tangan yang kadaluarsa.
+type PortfolioService struct {
+	Repo    *repository.PortfolioRepository
+	AchRepo *repository.AchievementRepository
+	Files   *AttachmentFiles
+	// BaseURL adalah URL publik aplikasi untuk membentuk tautan /p/:slug
+	BaseURL string
+}
+
+func NewPortfolioService(repo *repository.PortfolioRepository, achRepo *repository.AchievementRepository, files *AttachmentFiles, baseURL string) *PortfolioService {
+	return &PortfolioService{Repo: repo, AchRepo: achRepo, Files: files, BaseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// PortfolioChoice adalah satu prestasi VERIFIED beserta pilihan tampilnya di portofolio.
+type PortfolioChoice struct {
+	AchievementID   string `json:"achievement_id"`
+	Title           string `json:"title"`
+	AchievementType string `json:"achievement_type"`
+	Date            string `json:"date"`
+	Points          int    `json:"points"`
+	AttachmentCount int    `json:"attachment_count"`
+	Visible         bool   `json:"visible"`
+	ShowAttachments bool   `json:"show_attachments"`
+	// ShowPrivateDetails: field details pribadi (mis. nomor sertifikat) ikut dibagikan
+	ShowPrivateDetails bool `json:"show_private_details"`
+}
+
+type PortfolioSettings struct {
+	Portfolio *postgres.Portfolio `json:"portfolio"`
+	PublicURL string              `json:"public_url,omitempty"`
+	Items     []PortfolioChoice   `json:"items"`
+}
+
+type UpdatePortfolioRequest struct {
+	Published *bool   `json:"published" binding:"required"`
+	Headline  *string `json:"headline" binding:"omitempty,max=255" example:"Mahasiswa Teknik Informatika, minat keamanan siber"`
+}
+
+type UpdatePortfolioItemRequest struct {
+	Visible         *bool `json:"visible"`
+	ShowAttachments *bool `json:"show_attachments"`
+	// ShowPrivateDetails ikut menampilkan field pribadi seperti nomor sertifikat
+	ShowPrivateDetails *bool `json:"show_private_details"`
+}
+
+type PublicAttachment struct {
+	FileName     string `json:"file_name"`
+	FileType     string `json:"file_type"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+}
+
+type PublicAchievement struct {
+	ID              string             `json:"id"`
+	Title           string             `json:"title"`
+	AchievementType string             `json:"achievement_type"`
+	TypeLabel       string             `json:"type_label"`
+	Description     string             `json:"description"`
+	Date            string             `json:"date"`
+	Details         []PublicDetail     `json:"details"`
+	Tags            []string           `json:"tags"`
+	Points          int                `json:"points"`
+	Attachments     []PublicAttachment `json:"attachments,omitempty"`
+}
+
+type PublicPortfolio struct {
+	Owner        repository.PortfolioOwner `json:"owner"`
+	Headline     string                    `json:"headline,omitempty"`
+	TotalPoints  int                       `json:"total_points"`
+	Achievements []PublicAchievement       `json:"achievements"`
+	UpdatedAt    time.Time                 `json:"updated_at"`
+}
+
+// newPortfolioSlug membuat slug acak 144-bit yang tidak bisa ditebak.
+func newPortfolioSlug() string {
+	b := make([]byte, 18)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func (s *PortfolioService) publicURL(p *postgres.Portfolio) string {
+	if p == nil || p.Slug == nil {
+		return ""
+	}
+	return s.BaseURL + "/p/" + *p.Slug
+}
+
+type verifiedAchievement struct {
+	Ref     postgres.AchievementReference
+	Content mongodb.Achievement
+	Date    string
+}
+
+// verifiedAchievements memuat prestasi VERIFIED mahasiswa (termasuk prestasi tim yang ia ikuti),
+// terbaru dulu berdasarkan tanggal kegiatan.
+func (s *PortfolioService) verifiedAchievements(ctx context.Context, studentID string) ([]verifiedAchievement, error) {
+	refs, err := s.AchRepo.FindRefsByStudentID(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	verified := []postgres.AchievementReference{}
+	for _, ref := range refs {
+		if ref.Status == StatusVerified {
+			verified = append(verified, ref)
+		}
+	}
+	contents, err := s.AchRepo.FindContentByMongoIDs(ctx, mongoIDsOf(verified))
+	if err != nil {
+		return nil, err
+	}
+
+	list := []verifiedAchievement{}
+	for _, ref := range verified {
+		content, ok := contents[ref.MongoAchievementID]
+		if !ok {
+			continue
+		}
+		list = append(list, verifiedAchievement{Ref: ref, Content: content, Date: achievementDate(content)})
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Date > list[j].Date })
+	return list, nil
+}
+
+// ensurePortfolio memuat portofolio mahasiswa, membuatnya (belum dipublikasikan) jika belum ada.
+func (s *PortfolioService) ensurePortfolio(ctx context.Context, studentID string) (*postgres.Portfolio, error) {
+	p, err := s.Repo.FindByStudent(ctx, studentID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return s.Repo.Save(ctx, studentID, false, nil, newPortfolioSlug())
+	}
+	return p, err
+}
+
+func (s *PortfolioService) settings(ctx context.Context, studentID string) (*PortfolioSettings, error) {
+	p, err := s.ensurePortfolio(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.Repo.FindItems(ctx, p.ID)
+	if err != nil {
+		return nil, err
+	}
+	list, err := s.verifiedAchievements(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &PortfolioSettings{Portfolio: p, PublicURL: s.publicURL(p), Items: []PortfolioChoice{}}
+	for _, a := range list {
+		item := items[a.Ref.ID]
+		resp.Items = append(resp.Items, PortfolioChoice{
+			AchievementID:      a.Ref.ID,
+			Title:              a.Content.Title,
+			AchievementType:    a.Content.AchievementType,
+			Date:               a.Date,
+			Points:             a.Content.Points,
+			AttachmentCount:    len(a.Content.Attachments),
+			Visible:            item.Visible,
+			ShowAttachments:    item.ShowAttachments,
+			ShowPrivateDetails: item.ShowPrivateDetails,
+		})
+	}
+	return resp, nil
+}
+
+// GetMyPortfolio godoc
+// @Summary Get My Portfolio (Mahasiswa)
+// @Description Pengaturan portofolio publik beserta semua prestasi VERIFIED dan pilihan tampilnya.
+// @Tags Portfolio
+// @Security BearerAuth
+// @Success 200 {object} PortfolioSettings
+// @Router /portfolio [get]
+func (s *PortfolioService) GetMyPortfolio(c *gin.Context) {
+	resp, err := s.settings(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// UpdateMyPortfolio godoc
+// @Summary Publish / Unpublish Portfolio (Mahasiswa)
+// @Description Menyalakan atau mematikan portofolio publik dan mengubah headline. Slug tetap sama; gunakan POST /portfolio/slug untuk mencabut tautan lama.
+// @Tags Portfolio
+// @Security BearerAuth
+// @Param body body UpdatePortfolioRequest true "Pengaturan"
+// @Success 200 {object} PortfolioSettings
+// @Router /portfolio [put]
+func (s *PortfolioService) UpdateMyPortfolio(c *gin.Context) {
+	var req UpdatePortfolioRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Headline != nil {
+		if h := strings.TrimSpace(*req.Headline); h != "" {
+			req.Headline = &h
+		} else {
+			req.Headline = nil
+		}
+	}
+
+	ctx := c.Request.Context()
+	userID := c.GetString("user_id")
+	if _, err := s.Repo.Save(ctx, userID, *req.Published, req.Headline, newPortfolioSlug()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	resp, err := s.settings(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": resp})
+}
+
+// RotatePortfolioSlug godoc
+// @Summary Revoke Portfolio Link (Mahasiswa)
+// @Description Mengganti slug portofolio dengan slug acak baru; tautan lama langsung tidak berlaku.
+// @Tags Portfolio
+// @Security BearerAuth
+// @Success 200 {object} PortfolioSettings
+// @Router /portfolio/slug [post]
+func (s *PortfolioService) RotatePortfolioSlug(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := c.GetString("user_id")
+	if _, err := s.ensurePortfolio(ctx, userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if _, err := s.Repo.RotateSlug(ctx, userID, newPortfolioSlug()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	resp, err := s.settings(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": resp})
+}
+
+// UpdatePortfolioItem godoc
+// @Summary Set Portfolio Item Visibility (Mahasiswa)
+// @Description Mengatur apakah prestasi VERIFIED tampil di portofolio publik, apakah lampirannya ikut dibagikan, dan apakah field details pribadi (mis. nomor sertifikat) ikut ditampilkan. Field yang tidak dikirim tidak berubah.
+// @Tags Portfolio
+// @Security BearerAuth
+// @Param id path string true "ID prestasi"
+// @Param body body UpdatePortfolioItemRequest true "Pilihan tampil"
+// @Success 200 {object} postgres.PortfolioItem
+// @Router /portfolio/items/{id} [put]
+func (s *PortfolioService) UpdatePortfolioItem(c *gin.Context) {
+	var req UpdatePortfolioItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	userID := c.GetString("user_id")
+	ref, err := s.AchRepo.FindRefByID(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+		return
+	}
+	member, err := s.AchRepo.IsMemberOfRef(ctx, ref.ID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !member {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+		return
+	}
+	if ref.Status != StatusVerified {
+		c.JSON(http.StatusConflict, gin.H{"error": "Hanya prestasi VERIFIED yang bisa ditampilkan di portofolio"})
+		return
+	}
+
+	p, err := s.ensurePortfolio(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	items, err := s.Repo.FindItems(ctx, p.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	item := items[ref.ID]
+	item.AchievementID = ref.ID
+	if req.Visible != nil {
+		item.Visible = *req.Visible
+	}
+	if req.ShowAttachments != nil {
+		item.ShowAttachments = *req.ShowAttachments
+	}
+	if req.ShowPrivateDetails != nil {
+		item.ShowPrivateDetails = *req.ShowPrivateDetails
+	}
+	if err := s.Repo.SaveItem(ctx, p.ID, &item); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": item})
+}
+
+// loadPublic menyusun portofolio publik dari slug. Hanya prestasi yang dipilih, masih VERIFIED, dan
+// mahasiswanya masih anggota yang ditampilkan.
+func (s *PortfolioService) loadPublic(ctx context.Context, slug string) (*PublicPortfolio, error) {
+	p, owner, err := s.Repo.FindPublished(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.Repo.FindItems(ctx, p.ID)
+	if err != nil {
+		return nil, err
+	}
+	list, err := s.verifiedAchievements(ctx, p.StudentID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &PublicPortfolio{Owner: *owner, UpdatedAt: p.UpdatedAt, Achievements: []PublicAchievement{}}
+	if p.Headline != nil {
+		resp.Headline = *p.Headline
+	}
+	for _, a := range list {
+		item, ok := items[a.Ref.ID]
+		if !ok || !item.Visible {
+			continue
+		}
+		content := a.Content
+		pub := PublicAchievement{
+			ID:              a.Ref.ID,
+			Title:           content.Title,
+			AchievementType: content.AchievementType,
+			TypeLabel:       typeLabelID(content.AchievementType),
+			Description:     content.Description,
+			Date:            a.Date,
+			Details:         portfolioDetails(content.AchievementType, content.Details, item.ShowPrivateDetails),
+			Tags:            content.Tags,
+			Points:          content.Points,
+		}
+		if item.ShowAttachments {
+			s.Files.Sign(ctx, &content)
+			for _, att := range content.Attachments {
+				if att.FileURL == "" {
+					continue
+				}
+				shared := PublicAttachment{FileName: att.FileName, FileType: att.FileType, URL: att.FileURL}
+				if att.Preview != nil {
+					shared.ThumbnailURL = att.Preview.ThumbnailURL
+				}
+				pub.Attachments = append(pub.Attachments, shared)
+			}
+		}
+		resp.Achievements = append(resp.Achievements, pub)
+		resp.TotalPoints += content.Points
+	}
+	return resp, nil
+}
+
+func typeLabelID(achievementType string) string {
+	if l, ok := skpiTypeLabels[achievementType]; ok {
+		return l[0]
+	}
+	return achievementType
+}
+
+// publicHeaders: halaman portofolio tidak diindeks, tidak di-cache (URL lampiran kadaluarsa), dan
+// slug tidak bocor lewat header Referer.
+func publicHeaders(c *gin.Context) {
+	c.Header("X-Robots-Tag", "noindex, nofollow")
+	c.Header("Cache-Control", "no-store")
+	c.Header("Referrer-Policy", "no-referrer")
+}
+
+// GetPublicPortfolio godoc
+// @Summary Public Portfolio (JSON)
+// @Description Portofolio publik mahasiswa berdasarkan slug, tanpa token. Hanya prestasi VERIFIED yang dipilih mahasiswa; lampiran hanya jika dibagikan.
+// @Tags Portfolio
+// @Param slug path string true "Slug portofolio"
+// @Success 200 {object} PublicPortfolio
+// @Router /public/portfolios/{slug} [get]
+func (s *PortfolioService) GetPublicPortfolio(c *gin.Context) {
+	publicHeaders(c)
+	resp, err := s.loadPublic(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Portofolio tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// RenderPublicPortfolio menampilkan portofolio publik sebagai halaman HTML di /p/:slug.
+func (s *PortfolioService) RenderPublicPortfolio(c *gin.Context) {
+	publicHeaders(c)
+	resp, err := s.loadPublic(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		code := http.StatusInternalServerError
+		if errors.Is(err, repository.ErrNotFound) {
+			code = http.StatusNotFound
+		}
+		c.Data(code, "text/html; charset=utf-8", renderPortfolioError(code))
+		return
+	}
+	page, err := renderPortfolioPage(resp)
+	if err != nil {
+		c.Data(http.StatusInternalServerError, "text/html; charset=utf-8", renderPortfolioError(http.StatusInternalServerError))
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", page)
+}
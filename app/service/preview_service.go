@@ -0,0 +1,300 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	mongodb "pelaporan_prestasi/app/models/mongo"
+	"pelaporan_prestasi/app/repository"
+
+	"golang.org/x/image/draw"
+)
+
+const (
+	thumbnailMaxSide = 320
+	// maxPreviewPixels mencegah decompression bomb menghabiskan memori worker.
+	maxPreviewPixels = 40_000_000
+)
+
+type previewJob struct {
+	MongoID    string
+	Attachment mongodb.Attachment
+}
+
+// PreviewService membuat thumbnail gambar dan preview halaman pertama PDF di background,
+// lalu mencatat hasilnya di attachments.$.preview supaya UI verifikasi bisa menampilkannya inline.
+// Render PDF memakai pdftoppm (poppler); tanpa itu hanya jumlah halaman yang dicatat.
+//
+// Status pekerjaan disimpan di preview lampiran itu sendiri (pending sampai selesai), sehingga antrean
+// di memori hanya perantara: sweeper mengantrekan ulang lampiran pending yang hilang karena restart
+// atau antrean penuh, dan lampiran lama yang belum punya preview sama sekali.
+type PreviewService struct {
+	Repo        *repository.AchievementRepository
+	Files       *AttachmentFiles
+	PDFRenderer string
+
+	queue chan previewJob
+	mu    sync.Mutex
+	// queued berisi lampiran yang sedang di antrean atau diproses, supaya sweeper tidak menggandakannya
+	queued map[string]bool
+}
+
+// previewSweepBatch adalah jumlah dokumen yang diperiksa sweeper per putaran.
+const previewSweepBatch = 100
+
+func NewPreviewService(repo *repository.AchievementRepository, files *AttachmentFiles, pdfRenderer string) *PreviewService {
+	return &PreviewService{Repo: repo, Files: files, PDFRenderer: pdfRenderer, queue: make(chan previewJob, 256), queued: map[string]bool{}}
+}
+
+// Start menjalankan worker dan sweeper sampai ctx selesai. Sweeper langsung berjalan sekali saat start.
+func (s *PreviewService) Start(ctx context.Context, workers int, sweepInterval time.Duration) {
+	for i := 0; i < workers; i++ {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case job := <-s.queue:
+					s.process(ctx, job)
+					s.release(job)
+				}
+			}
+		}()
+	}
+
+	go func() {
+		ticker := time.NewTicker(sweepInterval)
+		defer ticker.Stop()
+
+		s.sweep(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.sweep(ctx)
+			}
+		}
+	}()
+}
+
+func previewJobKey(mongoID string, att mongodb.Attachment) string {
+	return mongoID + "/" + attachmentID(att)
+}
+
+// Enqueue menjadwalkan pembuatan preview. Antrean penuh tidak memblokir request; lampiran tetap
+// berstatus pending dan diambil lagi oleh sweeper.
+func (s *PreviewService) Enqueue(mongoID string, attachments ...mongodb.Attachment) {
+	for _, att := range attachments {
+		key := previewJobKey(mongoID, att)
+		s.mu.Lock()
+		if s.queued[key] {
+			s.mu.Unlock()
+			continue
+		}
+		s.queued[key] = true
+		s.mu.Unlock()
+
+		select {
+		case s.queue <- previewJob{MongoID: mongoID, Attachment: att}:
+		default:
+			s.release(previewJob{MongoID: mongoID, Attachment: att})
+			log.Printf("⚠️ Antrean preview penuh, lampiran %s menunggu sweeper", attachmentID(att))
+		}
+	}
+}
+
+func (s *PreviewService) release(job previewJob) {
+	s.mu.Lock()
+	delete(s.queued, previewJobKey(job.MongoID, job.Attachment))
+	s.mu.Unlock()
+}
+
+// sweep mengantrekan lampiran yang preview-nya masih pending atau belum pernah dibuat.
+func (s *PreviewService) sweep(ctx context.Context) {
+	docs, err := s.Repo.FindPendingPreviews(ctx, previewSweepBatch)
+	if err != nil {
+		log.Printf("⚠️ Gagal mencari preview tertunda: %v", err)
+		return
+	}
+	for _, doc := range docs {
+		for _, att := range doc.Attachments {
+			if att.Preview == nil || att.Preview.Status == mongodb.PreviewPending {
+				s.Enqueue(doc.ID.Hex(), att)
+			}
+		}
+	}
+}
+
+// pendingPreview adalah nilai awal preview lampiran baru.
+func pendingPreview() *mongodb.AttachmentPreview {
+	return &mongodb.AttachmentPreview{Status: mongodb.PreviewPending}
+}
+
+func (s *PreviewService) process(ctx context.Context, job previewJob) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	preview, err := s.generate(ctx, job.Attachment)
+	if err != nil {
+		log.Printf("⚠️ Gagal membuat preview %s: %v", attachmentID(job.Attachment), err)
+		preview.Status = mongodb.PreviewFailed
+		preview.Error = err.Error()
+	}
+	preview.GeneratedAt = time.Now()
+
+	if err := s.Repo.SetAttachmentPreview(ctx, job.MongoID, job.Attachment, preview); err != nil {
+		log.Printf("⚠️ Gagal simpan preview %s: %v", attachmentID(job.Attachment), err)
+		if preview.ThumbnailKey != "" {
+			s.Files.Store.Delete(context.Background(), preview.ThumbnailKey)
+		}
+	}
+}
+
+func (s *PreviewService) generate(ctx context.Context, att mongodb.Attachment) (mongodb.AttachmentPreview, error) {
+	rc, err := s.Files.Store.Open(ctx, attachmentKey(att))
+	if err != nil {
+		return mongodb.AttachmentPreview{}, err
+	}
+	data, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		return mongodb.AttachmentPreview{}, err
+	}
+
+	var thumb image.Image
+	preview := mongodb.AttachmentPreview{Status: mongodb.PreviewReady}
+	switch attachmentContentType(att) {
+	case "image/jpeg", "image/png":
+		thumb, err = decodeImage(bytes.NewReader(data))
+		if err != nil {
+			return preview, err
+		}
+	case "application/pdf":
+		preview.PageCount = s.pdfPageCount(ctx, data)
+		thumb, err = s.renderPDFFirstPage(ctx, data)
+		if errors.Is(err, exec.ErrNotFound) {
+			// Tanpa renderer, jumlah halaman tetap berguna untuk UI
+			preview.Status = mongodb.PreviewUnsupported
+			return preview, nil
+		}
+		if err != nil {
+			return preview, err
+		}
+	default:
+		preview.Status = mongodb.PreviewUnsupported
+		return preview, nil
+	}
+
+	thumb = resizeToFit(thumb, thumbnailMaxSide)
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: 80}); err != nil {
+		return preview, err
+	}
+
+	key := "previews/" + attachmentKey(att) + ".jpg"
+	if err := s.Files.Store.Put(ctx, key, &buf, int64(buf.Len()), "image/jpeg"); err != nil {
+		return preview, err
+	}
+	preview.ThumbnailKey = key
+	preview.Width = thumb.Bounds().Dx()
+	preview.Height = thumb.Bounds().Dy()
+	return preview, nil
+}
+
+func decodeImage(r io.ReadSeeker) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width*cfg.Height > maxPreviewPixels {
+		return nil, fmt.Errorf("gambar terlalu besar untuk dibuat thumbnail (%dx%d)", cfg.Width, cfg.Height)
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(r)
+	return img, err
+}
+
+// resizeToFit memperkecil img supaya sisi terpanjangnya maxSide; gambar kecil dibiarkan.
+func resizeToFit(img image.Image, maxSide int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= maxSide && h <= maxSide {
+		return img
+	}
+	if w >= h {
+		w, h = maxSide, max(1, h*maxSide/w)
+	} else {
+		w, h = max(1, w*maxSide/h), maxSide
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst
+}
+
+var (
+	pdfPagesRe = regexp.MustCompile(`(?m)^Pages:\s+(\d+)`)
+	pdfPageRe  = regexp.MustCompile(`/Type\s*/Page[^s]`)
+)
+
+// pdfPageCount memakai pdfinfo bila tersedia, jika tidak menghitung objek /Type /Page
+// (bisa meleset untuk PDF dengan object stream terkompresi).
+func (s *PreviewService) pdfPageCount(ctx context.Context, data []byte) int {
+	cmd := exec.CommandContext(ctx, "pdfinfo", "-")
+	cmd.Stdin = bytes.NewReader(data)
+	if out, err := cmd.Output(); err == nil {
+		if m := pdfPagesRe.FindSubmatch(out); m != nil {
+			if n, err := strconv.Atoi(string(m[1])); err == nil {
+				return n
+			}
+		}
+	}
+	return len(pdfPageRe.FindAll(data, -1))
+}
+
+func (s *PreviewService) renderPDFFirstPage(ctx context.Context, data []byte) (image.Image, error) {
+	bin, err := exec.LookPath(s.PDFRenderer)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "preview-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "in.pdf")
+	if err := os.WriteFile(in, data, 0600); err != nil {
+		return nil, err
+	}
+	out := filepath.Join(dir, "page")
+	cmd := exec.CommandContext(ctx, bin, "-f", "1", "-l", "1", "-singlefile", "-png",
+		"-scale-to", strconv.Itoa(thumbnailMaxSide*2), in, out)
+	if msg, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("%s: %v: %s", s.PDFRenderer, err, bytes.TrimSpace(msg))
+	}
+
+	f, err := os.Open(out + ".png")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return decodeImage(f)
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"pelaporan_prestasi/app/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ReconcileService mencari dan memperbaiki data yatim antara Mongo dan Postgres:
+//   - dokumen Mongo tanpa referensi Postgres (lebih tua dari Grace) dihapus beserta filenya;
+//   - referensi Postgres yang dokumen Mongo-nya hilang ditandai orphaned_at untuk ditinjau admin
+//     (referensi di tong sampah dilewati karena dibereskan purge);
+//   - ringkasan konten di referensi yang belum disalin atau tertinggal dari updatedAt Mongo disalin ulang.
+type ReconcileService struct {
+	Repo  *repository.AchievementRepository
+	Files *AttachmentFiles
+	Grace time.Duration
+
+	mu sync.Mutex
+}
+
+func NewReconcileService(repo *repository.AchievementRepository, files *AttachmentFiles, grace time.Duration) *ReconcileService {
+	return &ReconcileService{Repo: repo, Files: files, Grace: grace}
+}
+
+type ReconcileReport struct {
+	StartedAt    time.Time `json:"started_at"`
+	FinishedAt   time.Time `json:"finished_at"`
+	OrphanMongo  []string  `json:"orphan_mongo"`
+	OrphanRefs   []string  `json:"orphan_refs"`
+	RepairedDocs int       `json:"repaired_docs"`
+	// FlaggedRefs adalah jumlah referensi yatim yang baru ditandai orphaned_at
+	FlaggedRefs int `json:"flagged_refs"`
+	// SyncedSummaries adalah jumlah ringkasan daftar prestasi yang disalin ulang dari Mongo
+	SyncedSummaries int      `json:"synced_summaries"`
+	Errors          []string `json:"errors"`
+}
+
+func (s *ReconcileService) RunOnce(ctx context.Context) (*ReconcileReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	report := &ReconcileReport{StartedAt: time.Now(), OrphanMongo: []string{}, OrphanRefs: []string{}, Errors: []string{}}
+
+	docs, err := s.Repo.FindAllMongoStamps(ctx)
+	if err != nil {
+		return nil, err
+	}
+	refs, err := s.Repo.FindAllMongoRefs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := report.StartedAt.Add(-s.Grace)
+	for mongoID, stamp := range docs {
+		if _, ok := refs[mongoID]; ok || !stamp.CreatedAt.Before(cutoff) {
+			continue
+		}
+		report.OrphanMongo = append(report.OrphanMongo, mongoID)
+
+		content, _ := s.Repo.FindContentByMongoID(ctx, mongoID)
+		if err := s.Repo.DeleteMongo(ctx, mongoID); err != nil {
+			report.Errors = append(report.Errors, "mongo "+mongoID+": "+err.Error())
+			continue
+		}
+		if content != nil {
+			s.Files.Remove(content.Attachments)
+		}
+		report.RepairedDocs++
+	}
+
+	for mongoID, ref := range refs {
+		if _, ok := docs[mongoID]; ok {
+			// Dokumen muncul lagi (mis. dipulihkan dari backup): tanda yatim dicabut
+			if ref.Orphaned {
+				if err := s.Repo.SetOrphaned(ctx, ref.ID, false); err != nil {
+					report.Errors = append(report.Errors, "postgres "+ref.ID+": "+err.Error())
+				}
+			}
+			continue
+		}
+		if ref.Trashed {
+			continue
+		}
+		// Cek ulang: dokumen bisa saja baru dibuat setelah daftar Mongo dibaca
+		exists, err := s.Repo.MongoExists(ctx, mongoID)
+		if err != nil {
+			report.Errors = append(report.Errors, "mongo "+mongoID+": "+err.Error())
+			continue
+		}
+		if !exists {
+			report.OrphanRefs = append(report.OrphanRefs, ref.ID)
+		}
+	}
+	// Pengaman: koleksi Mongo kosong tapi ada referensi biasanya berarti salah konfigurasi MONGO_DB
+	if len(docs) == 0 && len(report.OrphanRefs) > 0 {
+		report.Errors = append(report.Errors, "koleksi Mongo kosong, perbaikan referensi dilewati")
+	} else {
+		for _, refID := range report.OrphanRefs {
+			if err := s.Repo.SetOrphaned(ctx, refID, true); err != nil {
+				report.Errors = append(report.Errors, "postgres "+refID+": "+err.Error())
+				continue
+			}
+			report.FlaggedRefs++
+		}
+	}
+
+	s.syncSummaries(ctx, docs, report)
+	report.FinishedAt = time.Now()
+	return report, nil
+}
+
+// syncSummaries menyalin ulang ringkasan konten referensi yang belum pernah disalin atau yang
+// content_updated_at-nya berbeda dari updatedAt dokumen Mongo.
+func (s *ReconcileService) syncSummaries(ctx context.Context, docs map[string]repository.MongoStamp, report *ReconcileReport) {
+	stamps, err := s.Repo.FindSummaryStamps(ctx)
+	if err != nil {
+		report.Errors = append(report.Errors, "ringkasan: "+err.Error())
+		return
+	}
+	for mongoID, synced := range stamps {
+		doc, ok := docs[mongoID]
+		if !ok || (synced != nil && synced.Equal(doc.UpdatedAt.UTC().Truncate(time.Millisecond))) {
+			continue
+		}
+		content, err := s.Repo.FindContentByMongoID(ctx, mongoID)
+		if err == nil {
+			err = s.Repo.SaveSummary(ctx, mongoID, repository.SummaryOf(*content))
+		}
+		if err != nil {
+			report.Errors = append(report.Errors, "ringkasan "+mongoID+": "+err.Error())
+			continue
+		}
+		report.SyncedSummaries++
+	}
+}
+
+// Start menjalankan rekonsiliasi sekali saat start (mengisi ringkasan daftar prestasi yang belum
+// disalin) lalu berkala sampai ctx dibatalkan.
+func (s *ReconcileService) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	s.runAndLog(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.runAndLog(ctx)
+		}
+	}
+}
+
+func (s *ReconcileService) runAndLog(ctx context.Context) {
+	report, err := s.RunOnce(ctx)
+	if err != nil {
+		log.Printf("⚠️ Rekonsiliasi gagal: %v", err)
+		return
+	}
+	if len(report.OrphanMongo) > 0 || len(report.OrphanRefs) > 0 || report.SyncedSummaries > 0 || len(report.Errors) > 0 {
+		log.Printf("🔧 Rekonsiliasi: %d dokumen Mongo yatim (%d dihapus), %d referensi yatim (%d baru ditandai), %d ringkasan disalin, %d error",
+			len(report.OrphanMongo), report.RepairedDocs, len(report.OrphanRefs), report.FlaggedRefs, report.SyncedSummaries, len(report.Errors))
+	}
+}
+
+// Reconcile godoc
+// @Summary      Run Mongo/Postgres Reconciliation
+// @Description  Jalankan rekonsiliasi data yatim sekarang juga (butuh permission system:maintain).
+// @Tags         Maintenance (Admin)
+// @Security     BearerAuth
+// @Success      200  {object} ReconcileReport
+// @Router       /admin/reconcile [post]
+func (s *ReconcileService) Reconcile(c *gin.Context) {
+	report, err := s.RunOnce(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
+
+// GetOrphanRefs godoc
+// @Summary      List Orphaned References
+// @Description  Referensi Postgres yang dokumen Mongo-nya hilang, ditandai rekonsiliasi untuk ditinjau.
+// @Tags         Maintenance (Admin)
+// @Security     BearerAuth
+// @Success      200  {object} map[string]interface{}
+// @Router       /admin/orphan-refs [get]
+func (s *ReconcileService) GetOrphanRefs(c *gin.Context) {
+	list, err := s.Repo.FindOrphanedRefs(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list})
+}
+
+// DeleteOrphanRef godoc
+// @Summary      Delete Orphaned Reference
+// @Description  Hapus permanen referensi bertanda yatim beserta history-nya setelah ditinjau. Ditolak (409) jika dokumen Mongo-nya ternyata ada lagi.
+// @Tags         Maintenance (Admin)
+// @Security     BearerAuth
+// @Param        id   path string true "ID referensi"
+// @Success      200  {object} map[string]string
+// @Router       /admin/orphan-refs/{id} [delete]
+func (s *ReconcileService) DeleteOrphanRef(c *gin.Context) {
+	ctx := c.Request.Context()
+	if _, err := uuid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Referensi yatim tidak ditemukan"})
+		return
+	}
+	ref, err := s.Repo.FindOrphanedRef(ctx, c.Param("id"))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Referensi yatim tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	exists, err := s.Repo.MongoExists(ctx, ref.MongoAchievementID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if exists {
+		c.JSON(http.StatusConflict, gin.H{"error": "Dokumen Mongo referensi ini ada; jalankan rekonsiliasi untuk mencabut tandanya"})
+		return
+	}
+	if err := s.Repo.DeleteRef(ctx, ref.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Orphaned reference deleted"})
+}
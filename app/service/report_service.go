@@ -1,8 +1,12 @@
 package service
 
 import (
+    "context"
+    "errors"
+
     "pelaporan_prestasi/app/models/dto" 
     "pelaporan_prestasi/app/repository"
+    "pelaporan_prestasi/middleware"
     "github.com/gin-gonic/gin"
 )
 
@@ -10,34 +14,48 @@ type ReportService struct {
     Repo    *repository.ReportRepository
     MhsRepo *repository.MahasiswaRepository
     AchRepo *repository.AchievementRepository 
+    Files   *AttachmentFiles
+    Perms   *middleware.PermissionCache
 }
 
-func NewReportService(repo *repository.ReportRepository, mhsRepo *repository.MahasiswaRepository, achRepo *repository.AchievementRepository) *ReportService {
-    return &ReportService{Repo: repo, MhsRepo: mhsRepo, AchRepo: achRepo}
+func NewReportService(repo *repository.ReportRepository, mhsRepo *repository.MahasiswaRepository, achRepo *repository.AchievementRepository, files *AttachmentFiles, perms *middleware.PermissionCache) *ReportService {
+    return &ReportService{Repo: repo, MhsRepo: mhsRepo, AchRepo: achRepo, Files: files, Perms: perms}
 }
 
 // GetGlobalStats godoc
 // @Summary Get Global Statistics
+// @Description report:read_all: statistik global. Dosen wali: statistik mahasiswa bimbingan (per status, antrian PENDING dan umur terlamanya, rincian per mahasiswa beserta poin, dan per tipe prestasi). Lainnya: jumlah prestasi per status miliknya.
 // @Tags Reports
 // @Security BearerAuth
 // @Router /api/v1/reports/statistics [get]
 func (s *ReportService) GetGlobalStats(c *gin.Context) {
     loginUserID := c.GetString("user_id")
-    loginRoleID := c.GetString("role_id")
 
-    if loginRoleID == RoleAdmin {
+    if s.Perms.Can(c, "report", "read_all") {
         stats, err := s.Repo.GetGlobalStats(c.Request.Context())
         if err != nil { c.JSON(500, gin.H{"error": err.Error()}); return }
         c.JSON(200, gin.H{"scope": "Global", "data": stats})
+        return
+    }
 
-    } else if loginRoleID == RoleMahasiswa {
-        stats, err := s.Repo.GetStudentStats(c.Request.Context(), loginUserID)
-        if err != nil { c.JSON(500, gin.H{"error": err.Error()}); return }
-        c.JSON(200, gin.H{"scope": "Personal", "data": stats})
-
-    } else if loginRoleID == RoleDosen {
-        c.JSON(200, gin.H{"scope": "Dosen Wali", "message": "Statistik bimbingan Anda"})
+    // User yang terdaftar sebagai dosen melihat bimbingannya, selain itu statistik pribadi
+    stats, err := s.advisorStats(c.Request.Context(), loginUserID)
+    if err == nil {
+        c.JSON(200, gin.H{"scope": "Dosen Wali", "data": stats})
+        return
     }
+    if !errors.Is(err, repository.ErrNotFound) { c.JSON(500, gin.H{"error": err.Error()}); return }
+
+    personal, err := s.Repo.GetStudentStats(c.Request.Context(), loginUserID)
+    if err != nil { c.JSON(500, gin.H{"error": err.Error()}); return }
+    c.JSON(200, gin.H{"scope": "Personal", "data": personal})
+}
+
+// advisorStats mengambil statistik dosen wali sepenuhnya dari Postgres; ErrNotFound jika user bukan dosen.
+func (s *ReportService) advisorStats(ctx context.Context, advisorUserID string) (*repository.AdvisorStatistics, error) {
+    dosenID, err := s.Repo.FindDosenID(ctx, advisorUserID)
+    if err != nil { return nil, err }
+    return s.Repo.GetAdvisorStats(ctx, dosenID, advisorUserID)
 }
 
 // GetStudentReport godoc
@@ -49,7 +67,6 @@ func (s *ReportService) GetGlobalStats(c *gin.Context) {
 func (s *ReportService) GetStudentReport(c *gin.Context) {
     studentID := c.Param("id")
     loginUserID := c.GetString("user_id")
-    loginRoleID := c.GetString("role_id")
 
     mhs, err := s.MhsRepo.GetByID(c.Request.Context(), studentID)
     if err != nil {
@@ -57,23 +74,22 @@ func (s *ReportService) GetStudentReport(c *gin.Context) {
         return
     }
 
-    if loginRoleID == RoleMahasiswa && mhs.UserID != loginUserID {
-        c.JSON(403, gin.H{"error": "Forbidden: Anda hanya bisa melihat laporan Anda sendiri"})
+    if mhs.UserID != loginUserID && mhs.AdvisorUserID != loginUserID && !s.Perms.Can(c, "report", "read_all") {
+        c.JSON(403, gin.H{"error": "Forbidden: Anda hanya bisa melihat laporan sendiri atau mahasiswa bimbingan"})
         return
     }
 
-    refs, _ := s.AchRepo.FindRefsByStudentID(c.Request.Context(), mhs.UserID)
-    var achievements []dto.AchievementResponse
-    
-    for _, ref := range refs {
-        content, _ := s.AchRepo.FindContentByMongoID(c.Request.Context(), ref.MongoAchievementID)
-        if content != nil {
-            achievements = append(achievements, dto.ToAchievementResponse(ref, *content))
-        }
-    }
+    refs, err := s.AchRepo.FindRefsByStudentID(c.Request.Context(), mhs.UserID)
+    if err != nil { c.JSON(500, gin.H{"error": err.Error()}); return }
+
+    contents, err := s.AchRepo.FindContentByMongoIDs(c.Request.Context(), mongoIDsOf(refs))
+    if err != nil { c.JSON(500, gin.H{"error": err.Error()}); return }
+    s.Files.SignAll(c.Request.Context(), contents)
 
+    achievements, missing := dto.ToAchievementResponses(refs, contents)
     c.JSON(200, gin.H{
         "student_info": mhs,
-        "achievements": achievements, 
+        "achievements": achievements,
+        "missing":      missing,
     })
 }
\ No newline at end of file
@@ -0,0 +1,324 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+
+	"pelaporan_prestasi/app/models/postgres"
+	"pelaporan_prestasi/app/repository"
+	"pelaporan_prestasi/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RoleService struct {
+	Repo  *repository.RoleRepository
+	Perms *middleware.PermissionCache
+}
+
+func NewRoleService(repo *repository.RoleRepository, perms *middleware.PermissionCache) *RoleService {
+	return &RoleService{Repo: repo, Perms: perms}
+}
+
+type RoleRequest struct {
+	Name        string `json:"name" binding:"required" example:"kaprodi"`
+	Description string `json:"description" example:"Ketua Program Studi"`
+}
+
+type PermissionRequest struct {
+	Name        string `json:"name" example:"report:export"`
+	Resource    string `json:"resource" binding:"required" example:"report"`
+	Action      string `json:"action" binding:"required" example:"export"`
+	Description string `json:"description" example:"Export laporan prestasi"`
+}
+
+type AssignPermissionRequest struct {
+	PermissionID string `json:"permission_id" binding:"required"`
+}
+
+func isBuiltinRole(id string) bool {
+	return id == RoleAdmin || id == RoleMahasiswa || id == RoleDosen
+}
+
+// GetRoles godoc
+// @Summary      List Roles
+// @Tags         Roles (Admin)
+// @Security     BearerAuth
+// @Success      200  {object} map[string]interface{}
+// @Router       /roles [get]
+func (s *RoleService) GetRoles(c *gin.Context) {
+	roles, err := s.Repo.FindAllRoles(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": roles})
+}
+
+// CreateRole godoc
+// @Summary      Create Role
+// @Description  Tambah role baru (mis. kaprodi, wakil dekan), lalu atur permission-nya.
+// @Tags         Roles (Admin)
+// @Security     BearerAuth
+// @Param        request body RoleRequest true "Data Role"
+// @Success      201  {object} map[string]interface{}
+// @Router       /roles [post]
+func (s *RoleService) CreateRole(c *gin.Context) {
+	var input RoleRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	role := postgres.Role{Name: input.Name, Description: input.Description}
+	if err := s.Repo.CreateRole(c.Request.Context(), &role); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"status": "success", "data": role})
+}
+
+// UpdateRole godoc
+// @Summary      Update Role
+// @Tags         Roles (Admin)
+// @Security     BearerAuth
+// @Param        id   path string true "Role ID"
+// @Param        request body RoleRequest true "Data Role"
+// @Success      200  {object} map[string]string
+// @Router       /roles/{id} [put]
+func (s *RoleService) UpdateRole(c *gin.Context) {
+	var input RoleRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	role := postgres.Role{ID: c.Param("id"), Name: input.Name, Description: input.Description}
+	if err := s.Repo.UpdateRole(c.Request.Context(), &role); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Role tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Role updated"})
+}
+
+// DeleteRole godoc
+// @Summary      Delete Role
+// @Description  Role bawaan dan role yang masih dipakai user tidak bisa dihapus.
+// @Tags         Roles (Admin)
+// @Security     BearerAuth
+// @Param        id   path string true "Role ID"
+// @Success      200  {object} map[string]string
+// @Failure      409  {object} map[string]string
+// @Router       /roles/{id} [delete]
+func (s *RoleService) DeleteRole(c *gin.Context) {
+	id := c.Param("id")
+	if isBuiltinRole(id) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Role bawaan tidak bisa dihapus"})
+		return
+	}
+
+	count, err := s.Repo.CountUsersByRole(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Role masih dipakai oleh user"})
+		return
+	}
+
+	if err := s.Repo.DeleteRole(c.Request.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Role tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	s.Perms.Invalidate(id)
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Role deleted"})
+}
+
+// GetRolePermissions godoc
+// @Summary      List Permission of Role
+// @Tags         Roles (Admin)
+// @Security     BearerAuth
+// @Param        id   path string true "Role ID"
+// @Success      200  {object} map[string]interface{}
+// @Router       /roles/{id}/permissions [get]
+func (s *RoleService) GetRolePermissions(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := s.Repo.FindRoleByID(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Role tidak ditemukan"})
+		return
+	}
+
+	perms, err := s.Repo.FindPermissionsByRoleID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": perms})
+}
+
+// AssignPermission godoc
+// @Summary      Grant Permission to Role
+// @Tags         Roles (Admin)
+// @Security     BearerAuth
+// @Param        id   path string true "Role ID"
+// @Param        request body AssignPermissionRequest true "Permission"
+// @Success      200  {object} map[string]string
+// @Router       /roles/{id}/permissions [post]
+func (s *RoleService) AssignPermission(c *gin.Context) {
+	id := c.Param("id")
+	var input AssignPermissionRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := s.Repo.FindRoleByID(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Role tidak ditemukan"})
+		return
+	}
+
+	if err := s.Repo.AssignPermission(c.Request.Context(), id, input.PermissionID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Permission tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	s.Perms.Invalidate(id)
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Permission ditambahkan"})
+}
+
+// RevokePermission godoc
+// @Summary      Revoke Permission from Role
+// @Description  role:manage tidak bisa dicabut dari role sendiri (409) supaya admin tidak terkunci.
+// @Tags         Roles (Admin)
+// @Security     BearerAuth
+// @Param        id           path string true "Role ID"
+// @Param        permissionId path string true "Permission ID"
+// @Success      200  {object} map[string]string
+// @Router       /roles/{id}/permissions/{permissionId} [delete]
+func (s *RoleService) RevokePermission(c *gin.Context) {
+	id := c.Param("id")
+	if id == c.GetString("role_id") {
+		locked, err := s.isRoleManage(c, c.Param("permissionId"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if locked {
+			c.JSON(http.StatusConflict, gin.H{"error": "Tidak bisa mencabut role:manage dari role sendiri"})
+			return
+		}
+	}
+
+	if err := s.Repo.RevokePermission(c.Request.Context(), id, c.Param("permissionId")); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Permission tidak dimiliki role ini"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	s.Perms.Invalidate(id)
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Permission dicabut"})
+}
+
+// isRoleManage mengecek apakah permissionID adalah role:manage.
+func (s *RoleService) isRoleManage(c *gin.Context, permissionID string) (bool, error) {
+	perms, err := s.Repo.FindAllPermissions(c.Request.Context())
+	if err != nil {
+		return false, err
+	}
+	for _, p := range perms {
+		if p.ID == permissionID {
+			return p.Resource == "role" && p.Action == "manage", nil
+		}
+	}
+	return false, nil
+}
+
+// GetPermissions godoc
+// @Summary      List Permissions
+// @Tags         Roles (Admin)
+// @Security     BearerAuth
+// @Success      200  {object} map[string]interface{}
+// @Router       /permissions [get]
+func (s *RoleService) GetPermissions(c *gin.Context) {
+	perms, err := s.Repo.FindAllPermissions(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": perms})
+}
+
+// CreatePermission godoc
+// @Summary      Create Permission
+// @Tags         Roles (Admin)
+// @Security     BearerAuth
+// @Param        request body PermissionRequest true "Data Permission"
+// @Success      201  {object} map[string]interface{}
+// @Router       /permissions [post]
+func (s *RoleService) CreatePermission(c *gin.Context) {
+	var input PermissionRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if input.Name == "" {
+		input.Name = input.Resource + ":" + input.Action
+	}
+
+	perm := postgres.Permission{
+		Name:        input.Name,
+		Resource:    input.Resource,
+		Action:      input.Action,
+		Description: input.Description,
+	}
+	if err := s.Repo.CreatePermission(c.Request.Context(), &perm); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"status": "success", "data": perm})
+}
+
+// DeletePermission godoc
+// @Summary      Delete Permission
+// @Description  role:manage tidak bisa dihapus (409).
+// @Tags         Roles (Admin)
+// @Security     BearerAuth
+// @Param        id   path string true "Permission ID"
+// @Success      200  {object} map[string]string
+// @Router       /permissions/{id} [delete]
+func (s *RoleService) DeletePermission(c *gin.Context) {
+	locked, err := s.isRoleManage(c, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if locked {
+		c.JSON(http.StatusConflict, gin.H{"error": "Permission role:manage tidak bisa dihapus"})
+		return
+	}
+
+	if err := s.Repo.DeletePermission(c.Request.Context(), c.Param("id")); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Permission tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	s.Perms.InvalidateAll()
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Permission deleted"})
+}
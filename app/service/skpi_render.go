@@ -0,0 +1,310 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	mongodb "pelaporan_prestasi/app/models/mongo"
+	"pelaporan_prestasi/app/pdf"
+	"pelaporan_prestasi/app/repository"
+)
+
+// Label dua bahasa (Indonesia / English) untuk isi SKPI.
+var skpiTypeLabels = map[string][2]string{
+	"competition":   {"Kompetisi", "Competition"},
+	"publication":   {"Publikasi Ilmiah", "Scientific Publication"},
+	"organization":  {"Organisasi", "Organizational Experience"},
+	"certification": {"Sertifikasi", "Certification"},
+}
+
+var skpiValueLabels = map[string][2]string{
+	"campus":        {"Kampus", "Campus"},
+	"regional":      {"Regional", "Regional"},
+	"national":      {"Nasional", "National"},
+	"international": {"Internasional", "International"},
+	"juara_1":       {"Juara 1", "1st Place"},
+	"juara_2":       {"Juara 2", "2nd Place"},
+	"juara_3":       {"Juara 3", "3rd Place"},
+	"harapan":       {"Juara Harapan", "Honorable Mention"},
+	"finalis":       {"Finalis", "Finalist"},
+	"peserta":       {"Peserta", "Participant"},
+	"ketua":         {"Ketua", "Chairperson"},
+	"wakil_ketua":   {"Wakil Ketua", "Vice Chairperson"},
+	"sekretaris":    {"Sekretaris", "Secretary"},
+	"bendahara":     {"Bendahara", "Treasurer"},
+	"koordinator":   {"Koordinator", "Coordinator"},
+	"anggota":       {"Anggota", "Member"},
+}
+
+var bulanIndonesia = [...]string{"Januari", "Februari", "Maret", "April", "Mei", "Juni", "Juli", "Agustus", "September", "Oktober", "November", "Desember"}
+
+func bilingual(labels map[string][2]string, key string) string {
+	l, ok := labels[key]
+	if !ok {
+		return key
+	}
+	if l[0] == l[1] {
+		return l[0]
+	}
+	return l[0] + " / " + l[1]
+}
+
+// skpiDate menulis tanggal dalam dua bahasa, mis. "17 Oktober 2026 / October 17, 2026".
+func skpiDate(t time.Time) string {
+	return fmt.Sprintf("%d %s %d / %s", t.Day(), bulanIndonesia[t.Month()-1], t.Year(), t.Format("January 2, 2006"))
+}
+
+// SkpiConfig adalah identitas institusi dan penandatangan default dokumen SKPI.
+type SkpiConfig struct {
+	InstitutionName    string
+	InstitutionNameEN  string
+	InstitutionAddress string
+	City               string
+	NumberPrefix       string
+	SignatoryName      string
+	SignatoryTitle     string
+	SignatoryTitleEN   string
+	SignatoryNIP       string
+}
+
+// Signatory adalah pejabat yang menandatangani satu dokumen SKPI.
+type Signatory struct {
+	Name    string
+	Title   string
+	TitleEN string
+	NIP     string
+}
+
+// skpiItem adalah satu prestasi VERIFIED yang dicantumkan di SKPI.
+type skpiItem struct {
+	Content mongodb.Achievement
+	Date    string
+}
+
+// skpiDocument berisi semua data yang dicetak pada satu dokumen SKPI.
+type skpiDocument struct {
+	Number      string
+	IssuedAt    time.Time
+	Student     repository.MahasiswaData
+	Items       []skpiItem
+	TotalPoints int
+	Signatory   Signatory
+}
+
+// skpiDetailLines meringkas details sesuai tipe prestasi menjadi baris "Label / Label: nilai".
+func skpiDetailLines(content mongodb.Achievement) []string {
+	d := content.Details
+	pair := func(id, en, val string) string {
+		if val == "" {
+			return ""
+		}
+		return id + " / " + en + ": " + val
+	}
+	lines := []string{}
+	switch content.AchievementType {
+	case "competition":
+		lines = append(lines,
+			pair("Kegiatan", "Event", detailString(d, "eventName")),
+			pair("Penyelenggara", "Organizer", detailString(d, "organizer")),
+			pair("Tingkat", "Level", bilingual(skpiValueLabels, detailString(d, "level"))),
+			pair("Peringkat", "Rank", bilingual(skpiValueLabels, detailString(d, "rank"))))
+	case "publication":
+		authors := []string{}
+		if list, ok := d["authors"].([]interface{}); ok {
+			for _, a := range list {
+				if s, ok := a.(string); ok {
+					authors = append(authors, s)
+				}
+			}
+		}
+		lines = append(lines,
+			pair("Jurnal", "Journal", detailString(d, "journal")),
+			pair("Indeksasi", "Indexing", strings.ToUpper(strings.ReplaceAll(detailString(d, "indexing"), "_", " "))),
+			pair("Penulis", "Authors", strings.Join(authors, ", ")),
+			pair("DOI", "DOI", detailString(d, "doi")))
+	case "organization":
+		period := detailString(d, "periodStart")
+		if end := detailString(d, "periodEnd"); end != "" {
+			period += " - " + end
+		}
+		lines = append(lines,
+			pair("Organisasi", "Organization", detailString(d, "organizationName")),
+			pair("Jabatan", "Position", bilingual(skpiValueLabels, detailString(d, "position"))),
+			pair("Periode", "Period", period))
+	case "certification":
+		lines = append(lines,
+			pair("Penerbit", "Issuer", detailString(d, "issuer")),
+			pair("Nomor", "Number", detailString(d, "number")),
+			pair("Berlaku Sampai", "Valid Until", detailString(d, "expiryDate")))
+	}
+
+	out := []string{}
+	for _, l := range lines {
+		if l != "" {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+const (
+	skpiMarginX   = 56.0
+	skpiTop       = 56.0
+	skpiBottom    = 780.0
+	skpiTextWidth = pdf.PageWidth - 2*skpiMarginX
+)
+
+// skpiWriter menulis baris demi baris dan pindah halaman otomatis.
+type skpiWriter struct {
+	doc    *pdf.Document
+	number string
+	y      float64
+}
+
+func (w *skpiWriter) newPage() {
+	w.doc.AddPage()
+	page := w.doc.PageCount()
+	w.doc.Line(skpiMarginX, skpiBottom+18, pdf.PageWidth-skpiMarginX, skpiBottom+18, 0.5)
+	w.doc.Text(skpiMarginX, skpiBottom+32, pdf.Italic, 8, "No. "+w.number)
+	w.doc.TextRight(pdf.PageWidth-skpiMarginX, skpiBottom+32, pdf.Italic, 8, fmt.Sprintf("Halaman / Page %d", page))
+	w.y = skpiTop
+}
+
+// ensure pindah halaman jika sisa ruang kurang dari height.
+func (w *skpiWriter) ensure(height float64) {
+	if w.y+height > skpiBottom {
+		w.newPage()
+	}
+}
+
+func (w *skpiWriter) paragraph(x float64, font pdf.Font, size float64, text string) {
+	lineHeight := size * 1.35
+	for _, line := range pdf.Wrap(font, size, text, skpiTextWidth-(x-skpiMarginX)) {
+		w.ensure(lineHeight)
+		w.y += lineHeight
+		w.doc.Text(x, w.y, font, size, line)
+	}
+}
+
+func (w *skpiWriter) heading(id, en string) {
+	w.ensure(40)
+	w.y += 14
+	w.paragraph(skpiMarginX, pdf.Bold, 11, id)
+	w.paragraph(skpiMarginX, pdf.Italic, 9, en)
+	w.y += 4
+	w.doc.Line(skpiMarginX, w.y, pdf.PageWidth-skpiMarginX, w.y, 0.5)
+	w.y += 2
+}
+
+func (w *skpiWriter) field(label, value string) {
+	w.ensure(14)
+	w.y += 14
+	w.doc.Text(skpiMarginX, w.y, pdf.Regular, 9.5, label)
+	w.doc.Text(skpiMarginX+170, w.y, pdf.Regular, 9.5, ":")
+	lines := pdf.Wrap(pdf.Bold, 9.5, value, skpiTextWidth-180)
+	w.doc.Text(skpiMarginX+180, w.y, pdf.Bold, 9.5, lines[0])
+	for _, l := range lines[1:] {
+		w.ensure(13)
+		w.y += 13
+		w.doc.Text(skpiMarginX+180, w.y, pdf.Bold, 9.5, l)
+	}
+}
+
+// renderSkpi menyusun PDF SKPI: kop institusi, nomor dokumen, identitas mahasiswa, daftar prestasi
+// terverifikasi, dan kolom tanda tangan. Setiap bagian ditulis dalam Bahasa Indonesia dan Inggris.
+func renderSkpi(cfg SkpiConfig, data skpiDocument) []byte {
+	doc := pdf.New("SKPI "+data.Student.NIM+" - "+data.Number, "Surat Keterangan Pendamping Ijazah / Diploma Supplement")
+	w := &skpiWriter{doc: doc, number: data.Number}
+	w.newPage()
+
+	// Kop institusi
+	w.y += 16
+	doc.TextCenter(w.y, pdf.Bold, 14, strings.ToUpper(cfg.InstitutionName))
+	if cfg.InstitutionNameEN != "" {
+		w.y += 14
+		doc.TextCenter(w.y, pdf.Italic, 10, cfg.InstitutionNameEN)
+	}
+	if cfg.InstitutionAddress != "" {
+		w.y += 13
+		doc.TextCenter(w.y, pdf.Regular, 9, cfg.InstitutionAddress)
+	}
+	w.y += 8
+	doc.Line(skpiMarginX, w.y, pdf.PageWidth-skpiMarginX, w.y, 1.5)
+	doc.Line(skpiMarginX, w.y+2.5, pdf.PageWidth-skpiMarginX, w.y+2.5, 0.5)
+
+	w.y += 28
+	doc.TextCenter(w.y, pdf.Bold, 13, "SURAT KETERANGAN PENDAMPING IJAZAH")
+	w.y += 14
+	doc.TextCenter(w.y, pdf.Italic, 10, "Diploma Supplement - Record of Achievements")
+	w.y += 14
+	doc.TextCenter(w.y, pdf.Regular, 9.5, "Nomor / Number: "+data.Number)
+	w.y += 6
+
+	w.heading("1. Identitas Pemegang SKPI", "Information Identifying the Holder of the Diploma Supplement")
+	w.field("Nama / Full Name", data.Student.Name)
+	w.field("NIM / Student ID Number", data.Student.NIM)
+	w.field("Program Studi / Study Program", data.Student.ProgramStudy)
+	w.field("Angkatan / Year of Entry", data.Student.AcademicYear)
+
+	w.heading("2. Prestasi dan Penghargaan", "Achievements and Awards")
+	if len(data.Items) == 0 {
+		w.paragraph(skpiMarginX, pdf.Italic, 9.5, "Belum ada prestasi terverifikasi. / No verified achievements.")
+	}
+	for i, item := range data.Items {
+		w.ensure(40)
+		w.y += 8
+		prefix := fmt.Sprintf("%d. ", i+1)
+		indent := skpiMarginX + pdf.TextWidth(pdf.Bold, 10, prefix)
+		doc.Text(skpiMarginX, w.y+13.5, pdf.Bold, 10, prefix)
+		w.paragraph(indent, pdf.Bold, 10, item.Content.Title)
+
+		meta := bilingual(skpiTypeLabels, item.Content.AchievementType)
+		if item.Date != "" {
+			meta += "  |  " + item.Date
+		}
+		meta += fmt.Sprintf("  |  %d poin / points", item.Content.Points)
+		w.paragraph(indent, pdf.Italic, 8.5, meta)
+		for _, line := range skpiDetailLines(item.Content) {
+			w.paragraph(indent, pdf.Regular, 9, line)
+		}
+	}
+
+	w.ensure(30)
+	w.y += 20
+	doc.Text(skpiMarginX, w.y, pdf.Bold, 10, fmt.Sprintf("Jumlah prestasi / Total achievements: %d", len(data.Items)))
+	doc.TextRight(pdf.PageWidth-skpiMarginX, w.y, pdf.Bold, 10, fmt.Sprintf("Total poin / Total points: %d", data.TotalPoints))
+
+	// Tanda tangan
+	w.ensure(140)
+	w.y += 36
+	x := pdf.PageWidth - skpiMarginX - 220
+	place := skpiDate(data.IssuedAt)
+	if cfg.City != "" {
+		place = cfg.City + ", " + place
+	}
+	doc.Text(x, w.y, pdf.Regular, 9.5, place)
+	w.y += 14
+	doc.Text(x, w.y, pdf.Regular, 9.5, data.Signatory.Title)
+	if data.Signatory.TitleEN != "" {
+		w.y += 12
+		doc.Text(x, w.y, pdf.Italic, 8.5, data.Signatory.TitleEN)
+	}
+	w.y += 64
+	doc.Text(x, w.y, pdf.Bold, 10, data.Signatory.Name)
+	doc.Line(x, w.y+3, x+signatureLineWidth(data.Signatory.Name), w.y+3, 0.5)
+	if data.Signatory.NIP != "" {
+		w.y += 13
+		doc.Text(x, w.y, pdf.Regular, 9, "NIP. "+data.Signatory.NIP)
+	}
+
+	return doc.Bytes(data.IssuedAt)
+}
+
+// signatureLineWidth: garis di bawah nama penanda tangan minimal 160pt.
+func signatureLineWidth(name string) float64 {
+	if w := pdf.TextWidth(pdf.Bold, 10, name); w > 160 {
+		return w
+	}
+	return 160
+}
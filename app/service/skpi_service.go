@@ -0,0 +1,281 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	mongodb "pelaporan_prestasi/app/models/mongo"
+	"pelaporan_prestasi/app/models/postgres"
+	"pelaporan_prestasi/app/repository"
+	"pelaporan_prestasi/app/storage"
+	"pelaporan_prestasi/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SkpiService menerbitkan SKPI (Surat Keterangan Pendamping Ijazah) berisi prestasi VERIFIED
+// mahasiswa. Setiap penerbitan menjadi versi baru yang PDF-nya disimpan di FileStore.
+type SkpiService struct {
+	Repo    *repository.SkpiRepository
+	MhsRepo *repository.MahasiswaRepository
+	AchRepo *repository.AchievementRepository
+	Store   storage.FileStore
+	Config  SkpiConfig
+	Perms   *middleware.PermissionCache
+}
+
+func NewSkpiService(repo *repository.SkpiRepository, mhsRepo *repository.MahasiswaRepository, achRepo *repository.AchievementRepository, store storage.FileStore, cfg SkpiConfig, perms *middleware.PermissionCache) *SkpiService {
+	return &SkpiService{Repo: repo, MhsRepo: mhsRepo, AchRepo: achRepo, Store: store, Config: cfg, Perms: perms}
+}
+
+// GenerateSkpiRequest mengganti penanda tangan default (SKPI_SIGNATORY_*) untuk satu dokumen.
+type GenerateSkpiRequest struct {
+	SignatoryName    string `json:"signatory_name" example:"Dr. Budi Santoso, M.Kom."`
+	SignatoryTitle   string `json:"signatory_title" example:"Wakil Rektor Bidang Kemahasiswaan"`
+	SignatoryTitleEN string `json:"signatory_title_en" example:"Vice Rector for Student Affairs"`
+	SignatoryNIP     string `json:"signatory_nip" example:"197001012000031001"`
+}
+
+func (req GenerateSkpiRequest) signatory(cfg SkpiConfig) Signatory {
+	pick := func(v, fallback string) string {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+		return fallback
+	}
+	return Signatory{
+		Name:    pick(req.SignatoryName, cfg.SignatoryName),
+		Title:   pick(req.SignatoryTitle, cfg.SignatoryTitle),
+		TitleEN: pick(req.SignatoryTitleEN, cfg.SignatoryTitleEN),
+		NIP:     pick(req.SignatoryNIP, cfg.SignatoryNIP),
+	}
+}
+
+// loadStudent memuat mahasiswa dari path :id dan memeriksa akses: mahasiswa:read_all semua, dosen
+// mahasiswa bimbingannya, mahasiswa dirinya sendiri.
+func (s *SkpiService) loadStudent(c *gin.Context) (*repository.MahasiswaData, bool) {
+	mhs, err := s.MhsRepo.GetByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Mahasiswa tidak ditemukan"})
+		return nil, false
+	}
+	userID := c.GetString("user_id")
+	if mhs.UserID != userID && mhs.AdvisorUserID != userID && !s.Perms.Can(c, "mahasiswa", "read_all") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+		return nil, false
+	}
+	return mhs, true
+}
+
+// achievementDate mengambil tanggal kegiatan (field tanggal pertama di details sesuai schema),
+// atau tanggal input jika tidak ada.
+func achievementDate(content mongodb.Achievement) string {
+	schema, _ := FindDetailSchema(content.AchievementType)
+	for _, f := range schema.Fields {
+		if f.Type == FieldDate {
+			if date := detailString(content.Details, f.Name); date != "" {
+				return date
+			}
+		}
+	}
+	return content.CreatedAt.Format("2006-01-02")
+}
+
+// verifiedItems mengumpulkan prestasi VERIFIED mahasiswa (termasuk prestasi tim yang ia ikuti),
+// urut tanggal kegiatan.
+func (s *SkpiService) verifiedItems(ctx context.Context, userID string) ([]skpiItem, int, error) {
+	refs, err := s.AchRepo.FindRefsByStudentID(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	verified := []postgres.AchievementReference{}
+	for _, ref := range refs {
+		if ref.Status == StatusVerified {
+			verified = append(verified, ref)
+		}
+	}
+	contents, err := s.AchRepo.FindContentByMongoIDs(ctx, mongoIDsOf(verified))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items := []skpiItem{}
+	total := 0
+	for _, ref := range verified {
+		content, ok := contents[ref.MongoAchievementID]
+		if !ok {
+			continue
+		}
+		items = append(items, skpiItem{Content: content, Date: achievementDate(content)})
+		total += content.Points
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Date < items[j].Date })
+	return items, total, nil
+}
+
+// documentNumber membentuk nomor dokumen, mis. "00042/SKPI/2026".
+func (s *SkpiService) documentNumber(seq int64, issuedAt time.Time) string {
+	return fmt.Sprintf("%05d/%s/%d", seq, s.Config.NumberPrefix, issuedAt.Year())
+}
+
+// GenerateSkpi godoc
+// @Summary Generate SKPI (Admin)
+// @Description Render prestasi VERIFIED mahasiswa menjadi PDF SKPI dua bahasa (Indonesia/Inggris) dengan kop institusi, nomor dokumen, dan kolom tanda tangan. Setiap generate disimpan sebagai versi baru.
+// @Tags Reports
+// @Security BearerAuth
+// @Param id path string true "ID Mahasiswa"
+// @Param body body GenerateSkpiRequest false "Penanda tangan (opsional, default dari konfigurasi)"
+// @Success 201 {object} postgres.SkpiDocument
+// @Router /reports/student/{id}/skpi [post]
+func (s *SkpiService) GenerateSkpi(c *gin.Context) {
+	var req GenerateSkpiRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	signatory := req.signatory(s.Config)
+	if signatory.Name == "" || signatory.Title == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nama dan jabatan penanda tangan wajib diisi (atau set SKPI_SIGNATORY_NAME/SKPI_SIGNATORY_TITLE)"})
+		return
+	}
+
+	mhs, ok := s.loadStudent(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	items, total, err := s.verifiedItems(ctx, mhs.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	seq, err := s.Repo.NextSequence(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	issuedAt := time.Now()
+	number := s.documentNumber(seq, issuedAt)
+	data := renderSkpi(s.Config, skpiDocument{
+		Number:      number,
+		IssuedAt:    issuedAt,
+		Student:     *mhs,
+		Items:       items,
+		TotalPoints: total,
+		Signatory:   signatory,
+	})
+
+	key := fmt.Sprintf("skpi/%s/%s.pdf", mhs.ID, uuid.New().String())
+	if err := s.Store.Put(ctx, key, bytes.NewReader(data), int64(len(data)), "application/pdf"); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal simpan dokumen: " + err.Error()})
+		return
+	}
+
+	doc := postgres.SkpiDocument{
+		MahasiswaID:      mhs.ID,
+		DocumentNumber:   number,
+		StorageKey:       key,
+		SHA256:           fmt.Sprintf("%x", sha256.Sum256(data)),
+		Size:             int64(len(data)),
+		AchievementCount: len(items),
+		TotalPoints:      total,
+		SignatoryName:    signatory.Name,
+		SignatoryTitle:   signatory.Title,
+	}
+	if by := c.GetString("user_id"); by != "" {
+		doc.GeneratedBy = &by
+	}
+	if signatory.NIP != "" {
+		doc.SignatoryNIP = &signatory.NIP
+	}
+	if err := s.Repo.Create(ctx, &doc); err != nil {
+		if errDel := s.Store.Delete(context.Background(), key); errDel != nil {
+			log.Printf("⚠️ Gagal hapus file SKPI %s: %v", key, errDel)
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"status": "success", "data": doc})
+}
+
+// GetSkpiVersions godoc
+// @Summary List SKPI Versions
+// @Description Semua versi SKPI yang pernah diterbitkan untuk mahasiswa, terbaru dulu.
+// @Tags Reports
+// @Security BearerAuth
+// @Param id path string true "ID Mahasiswa"
+// @Success 200 {object} map[string]interface{}
+// @Router /reports/student/{id}/skpi [get]
+func (s *SkpiService) GetSkpiVersions(c *gin.Context) {
+	mhs, ok := s.loadStudent(c)
+	if !ok {
+		return
+	}
+	docs, err := s.Repo.FindByMahasiswa(c.Request.Context(), mhs.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": docs})
+}
+
+// DownloadSkpi godoc
+// @Summary Download SKPI PDF
+// @Description Unduh ulang PDF SKPI versi tertentu persis seperti saat diterbitkan.
+// @Tags Reports
+// @Security BearerAuth
+// @Produce application/pdf
+// @Param id path string true "ID Mahasiswa"
+// @Param version path int true "Versi"
+// @Success 200 {file} file
+// @Router /reports/student/{id}/skpi/{version} [get]
+func (s *SkpiService) DownloadSkpi(c *gin.Context) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "version harus bilangan bulat positif"})
+		return
+	}
+	mhs, ok := s.loadStudent(c)
+	if !ok {
+		return
+	}
+
+	doc, err := s.Repo.FindVersion(c.Request.Context(), mhs.ID, version)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Versi SKPI tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	rc, err := s.Store.Open(c.Request.Context(), doc.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrFileNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "File SKPI tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer rc.Close()
+
+	name := fmt.Sprintf("SKPI_%s_v%d.pdf", mhs.NIM, doc.Version)
+	c.Header("Content-Disposition", contentDisposition("attachment", name))
+	c.Header("X-Document-Number", doc.DocumentNumber)
+	c.DataFromReader(http.StatusOK, doc.Size, "application/pdf", io.LimitReader(rc, doc.Size), nil)
+}
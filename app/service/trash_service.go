@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"pelaporan_prestasi/app/models/dto"
+	"pelaporan_prestasi/app/repository"
+	"pelaporan_prestasi/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TrashService mengelola tong sampah prestasi: daftar, pemulihan dalam masa Retention,
+// dan purge berkala yang menghapus permanen prestasi kadaluarsa beserta file-nya.
+type TrashService struct {
+	Repo      *repository.AchievementRepository
+	Writer    *AchievementWriter
+	Retention time.Duration
+	Perms     *middleware.PermissionCache
+
+	mu sync.Mutex
+}
+
+func NewTrashService(repo *repository.AchievementRepository, writer *AchievementWriter, retention time.Duration, perms *middleware.PermissionCache) *TrashService {
+	return &TrashService{Repo: repo, Writer: writer, Retention: retention, Perms: perms}
+}
+
+type PurgeReport struct {
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Purged     []string  `json:"purged"`
+	Errors     []string  `json:"errors"`
+}
+
+// retentionStart adalah batas deleted_at paling lama yang masih bisa dipulihkan.
+func (s *TrashService) retentionStart() time.Time {
+	return time.Now().Add(-s.Retention)
+}
+
+// PurgeExpired menghapus permanen semua prestasi yang sudah lewat masa retensi.
+func (s *TrashService) PurgeExpired(ctx context.Context) (*PurgeReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	report := &PurgeReport{StartedAt: time.Now(), Purged: []string{}, Errors: []string{}}
+	refs, err := s.Repo.FindExpiredTrash(ctx, s.retentionStart())
+	if err != nil {
+		return nil, err
+	}
+	for i := range refs {
+		if err := s.Writer.Purge(ctx, &refs[i]); err != nil {
+			report.Errors = append(report.Errors, refs[i].ID+": "+err.Error())
+			continue
+		}
+		report.Purged = append(report.Purged, refs[i].ID)
+	}
+
+	report.FinishedAt = time.Now()
+	return report, nil
+}
+
+// Start menjalankan purge berkala sampai ctx dibatalkan.
+func (s *TrashService) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := s.PurgeExpired(ctx)
+			if err != nil {
+				log.Printf("⚠️ Purge tong sampah gagal: %v", err)
+				continue
+			}
+			if len(report.Purged) > 0 || len(report.Errors) > 0 {
+				log.Printf("🗑️ Purge tong sampah: %d prestasi dihapus permanen, %d error", len(report.Purged), len(report.Errors))
+			}
+		}
+	}
+}
+
+// TrashItem adalah prestasi di tong sampah beserta kapan ia akan dipurge.
+type TrashItem struct {
+	dto.AchievementResponse
+	DeletedAt time.Time `json:"deleted_at"`
+	DeletedBy *string   `json:"deleted_by,omitempty"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+// GetTrash godoc
+// @Summary List Trashed Achievements
+// @Description Tong sampah milik sendiri; dengan achievement:manage_all semua (opsional filter student_id).
+// @Tags Achievements
+// @Security BearerAuth
+// @Param student_id query string false "Filter mahasiswa (achievement:manage_all)"
+// @Success 200 {object} map[string]interface{}
+// @Router /achievements/trash [get]
+func (s *TrashService) GetTrash(c *gin.Context) {
+	studentID := c.GetString("user_id")
+	if s.Perms.Can(c, "achievement", "manage_all") {
+		studentID = c.Query("student_id")
+	}
+
+	refs, err := s.Repo.FindTrashedRefs(c.Request.Context(), studentID, s.retentionStart())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	contents, err := s.Repo.FindContentByMongoIDs(c.Request.Context(), mongoIDsOf(refs))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	s.Writer.Files.SignAll(c.Request.Context(), contents)
+
+	items := []TrashItem{}
+	missing := []dto.MissingContent{}
+	for _, ref := range refs {
+		content, ok := contents[ref.MongoAchievementID]
+		if !ok {
+			missing = append(missing, dto.MissingContent{RefID: ref.ID, MongoAchievementID: ref.MongoAchievementID})
+			continue
+		}
+		items = append(items, TrashItem{
+			AchievementResponse: dto.ToAchievementResponse(ref, content),
+			DeletedAt:           *ref.DeletedAt,
+			DeletedBy:           ref.DeletedBy,
+			PurgeAt:             ref.DeletedAt.Add(s.Retention),
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"data": items, "missing": missing})
+}
+
+// Restore godoc
+// @Summary Restore Trashed Achievement
+// @Description Pulihkan prestasi dari tong sampah selama masa retensi belum lewat (410 jika sudah).
+// @Tags Achievements
+// @Security BearerAuth
+// @Param id path string true "ID"
+// @Success 200 {object} map[string]string
+// @Router /achievements/{id}/restore [post]
+func (s *TrashService) Restore(c *gin.Context) {
+	ref, err := s.Repo.FindTrashedRefByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Prestasi tidak ada di tong sampah"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if ref.StudentID != c.GetString("user_id") && !s.Perms.Can(c, "achievement", "manage_all") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+		return
+	}
+
+	if err := s.Writer.Restore(c.Request.Context(), ref, s.retentionStart()); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusGone, gin.H{"error": "Masa retensi sudah lewat, prestasi tidak bisa dipulihkan"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Restored"})
+}
+
+// Purge godoc
+// @Summary      Purge Expired Trash
+// @Description  Hapus permanen prestasi yang sudah lewat masa retensi sekarang juga (butuh permission system:maintain).
+// @Tags         Maintenance (Admin)
+// @Security     BearerAuth
+// @Success      200  {object} PurgeReport
+// @Router       /admin/purge-trash [post]
+func (s *TrashService) Purge(c *gin.Context) {
+	report, err := s.PurgeExpired(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// allowedUploadTypes memetakan ekstensi yang diterima ke MIME hasil sniffing isi file.
+var allowedUploadTypes = map[string]string{
+	".pdf":  "application/pdf",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+}
+
+// UploadError adalah penolakan upload yang aman ditampilkan ke pengguna.
+type UploadError struct {
+	Status  int
+	Message string
+}
+
+func (e *UploadError) Error() string { return e.Message }
+
+func uploadRejected(format string, args ...interface{}) *UploadError {
+	return &UploadError{Status: http.StatusUnprocessableEntity, Message: fmt.Sprintf(format, args...)}
+}
+
+// Penanda format lain yang tidak boleh ikut tertanam di file upload. Penanda biner dicocokkan
+// persis, penanda teks tanpa memedulikan huruf besar/kecil.
+var (
+	foreignBinarySignatures = [][]byte{[]byte("%PDF-"), []byte("PK\x03\x04")}
+	foreignTextSignatures   = [][]byte{[]byte("<?php"), []byte("<script"), []byte("<html")}
+)
+
+// sniffUpload memastikan isi file sesuai ekstensinya dan bukan polyglot, mengembalikan MIME-nya.
+func sniffUpload(fileName string, data []byte) (string, error) {
+	ext := strings.ToLower(filepath.Ext(fileName))
+	want, ok := allowedUploadTypes[ext]
+	if !ok {
+		return "", uploadRejected("Format file harus PDF atau Gambar (JPG/PNG)")
+	}
+	if len(data) == 0 {
+		return "", uploadRejected("File kosong")
+	}
+
+	got := http.DetectContentType(data)
+	if i := strings.IndexByte(got, ';'); i >= 0 {
+		got = got[:i]
+	}
+	if got != want {
+		return "", uploadRejected("Isi file (%s) tidak sesuai ekstensi %s", got, ext)
+	}
+
+	if err := checkPolyglot(want, data); err != nil {
+		return "", err
+	}
+	return want, nil
+}
+
+// checkPolyglot menolak file yang membawa data setelah penanda akhir formatnya atau
+// menyisipkan penanda format lain (mis. gambar yang sekaligus PDF/ZIP/HTML).
+func checkPolyglot(mimeType string, data []byte) error {
+	var body []byte
+	switch mimeType {
+	case "image/png":
+		end := bytes.LastIndex(data, []byte("IEND"))
+		if end < 0 || end+8 != len(data) {
+			return uploadRejected("File PNG memuat data tambahan setelah akhir gambar")
+		}
+		body = data
+	case "image/jpeg":
+		if !bytes.HasSuffix(bytes.TrimRight(data, "\x00"), []byte{0xFF, 0xD9}) {
+			return uploadRejected("File JPEG memuat data tambahan setelah akhir gambar")
+		}
+		body = data
+	case "application/pdf":
+		end := bytes.LastIndex(data, []byte("%%EOF"))
+		if end < 0 || len(bytes.TrimSpace(data[end+5:])) > 0 {
+			return uploadRejected("File PDF memuat data tambahan setelah %%%%EOF")
+		}
+		// Isi PDF boleh berisi stream biner; cukup periksa tidak ada header format lain di awal file
+		body = data[:min(len(data), 1024)][len("%PDF-"):]
+	}
+
+	for _, sig := range foreignBinarySignatures {
+		if bytes.Contains(body, sig) {
+			return uploadRejected("File terdeteksi memuat format lain (%q)", string(sig))
+		}
+	}
+	lower := bytes.ToLower(body)
+	for _, sig := range foreignTextSignatures {
+		if bytes.Contains(lower, sig) {
+			return uploadRejected("File terdeteksi memuat format lain (%q)", string(sig))
+		}
+	}
+	return nil
+}
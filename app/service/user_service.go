@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"pelaporan_prestasi/app/models/postgres"
 	"pelaporan_prestasi/app/repository"
+	"pelaporan_prestasi/middleware"
 
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
@@ -18,10 +19,11 @@ const (
 
 type UserService struct {
 	UserRepo *repository.UserRepository
+	Perms    *middleware.PermissionCache
 }
 
-func NewUserService(userRepo *repository.UserRepository) *UserService {
-	return &UserService{UserRepo: userRepo}
+func NewUserService(userRepo *repository.UserRepository, perms *middleware.PermissionCache) *UserService {
+	return &UserService{UserRepo: userRepo, Perms: perms}
 }
 
 // --- STRUCT REQUEST BODY ---
@@ -46,17 +48,13 @@ type UpdateRoleRequest struct {
 
 // GetAllUsers godoc
 // @Summary      Get All Users
-// @Description  Hanya Admin.
+// @Description  Butuh permission user:read.
 // @Tags         Users (Admin)
 // @Security     BearerAuth
 // @Success      200  {object} map[string]interface{}
 // @Failure      403  {object} map[string]string "Forbidden"
 // @Router       /users [get]
 func (s *UserService) GetAllUsers(c *gin.Context) {
-	if c.GetString("role_id") != RoleAdmin {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Akses Ditolak! Hanya Admin yang boleh melihat daftar user."})
-		return
-	}
 	users, err := s.UserRepo.FindAll(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -67,7 +65,7 @@ func (s *UserService) GetAllUsers(c *gin.Context) {
 
 // GetUserByID godoc
 // @Summary      Get User Detail
-// @Description  Admin bisa lihat siapa saja. User biasa hanya bisa lihat dirinya sendiri.
+// @Description  Pemilik permission user:read bisa lihat siapa saja. User biasa hanya bisa lihat dirinya sendiri.
 // @Tags         Users (Admin)
 // @Security     BearerAuth
 // @Param        id   path string true "User ID"
@@ -77,10 +75,9 @@ func (s *UserService) GetAllUsers(c *gin.Context) {
 // @Router       /users/{id} [get]
 func (s *UserService) GetUserByID(c *gin.Context) {
 	myUserID := c.GetString("user_id")
-	myRoleID := c.GetString("role_id")
 	targetUserID := c.Param("id")
 
-	if myRoleID != RoleAdmin {
+	if !s.Perms.Can(c, "user", "read") {
 		if myUserID != targetUserID {
 			c.JSON(http.StatusForbidden, gin.H{"error": "Anda tidak boleh melihat data user lain!"})
 			return
@@ -99,18 +96,13 @@ func (s *UserService) GetUserByID(c *gin.Context) {
 
 // CreateUser godoc
 // @Summary      Create User (Manual)
-// @Description  Hanya Admin.
+// @Description  Butuh permission user:create.
 // @Tags         Users (Admin)
 // @Security     BearerAuth
 // @Param        request body CreateUserRequest true "Data User"
 // @Success      201  {object} map[string]interface{}
 // @Router       /users [post]
 func (s *UserService) CreateUser(c *gin.Context) {
-	if c.GetString("role_id") != RoleAdmin {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Akses Ditolak!"})
-		return
-	}
-
 	var input CreateUserRequest
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -135,7 +127,7 @@ func (s *UserService) CreateUser(c *gin.Context) {
 
 // UpdateUser godoc
 // @Summary      Update User Data (Nama/Email)
-// @Description  Admin bebas edit. User biasa hanya edit diri sendiri.
+// @Description  Pemilik permission user:update bebas edit. User biasa hanya edit diri sendiri.
 // @Tags         Users (Admin)
 // @Security     BearerAuth
 // @Param        id   path string true "User ID"
@@ -147,7 +139,7 @@ func (s *UserService) UpdateUser(c *gin.Context) {
 	myUserID := c.GetString("user_id")
 	targetUserID := c.Param("id")
 
-	if myRoleID != RoleAdmin {
+	if !s.Perms.Can(c, "user", "update") {
 		if myUserID != targetUserID {
 			c.JSON(http.StatusForbidden, gin.H{"error": "Tidak boleh edit user lain!"})
 			return
@@ -160,7 +152,7 @@ func (s *UserService) UpdateUser(c *gin.Context) {
 		return
 	}
 
-	if myRoleID != RoleAdmin && input.RoleID != "" {
+	if input.RoleID != "" && !s.Perms.Can(c, "user", "assign_role") {
 		input.RoleID = myRoleID
 	}
 
@@ -180,7 +172,7 @@ func (s *UserService) UpdateUser(c *gin.Context) {
 
 // UpdateRole godoc
 // @Summary      Change User Role (Promote/Demote)
-// @Description  Khusus Admin untuk mengubah jabatan user.
+// @Description  Butuh permission user:assign_role.
 // @Tags         Users (Admin)
 // @Security     BearerAuth
 // @Param        id   path string true "User ID Target"
@@ -189,10 +181,6 @@ func (s *UserService) UpdateUser(c *gin.Context) {
 // @Failure      403  {object} map[string]string "Forbidden"
 // @Router       /users/{id}/role [put]
 func (s *UserService) UpdateRole(c *gin.Context) {
-	if c.GetString("role_id") != RoleAdmin {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Hanya Admin yang boleh ganti role!"})
-		return
-	}
 	id := c.Param("id")
 	var input UpdateRoleRequest
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -209,17 +197,13 @@ func (s *UserService) UpdateRole(c *gin.Context) {
 
 // DeleteUser godoc
 // @Summary      Soft Delete User
-// @Description  Hanya Admin.
+// @Description  Butuh permission user:delete.
 // @Tags         Users (Admin)
 // @Security     BearerAuth
 // @Param        id   path string true "User ID"
 // @Success      200  {object} map[string]string
 // @Router       /users/{id} [delete]
 func (s *UserService) DeleteUser(c *gin.Context) {
-	if c.GetString("role_id") != RoleAdmin {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Akses Ditolak!"})
-		return
-	}
 	if err := s.UserRepo.Delete(c.Request.Context(), c.Param("id")); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal delete"})
 		return
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"pelaporan_prestasi/app/models/postgres"
+	"pelaporan_prestasi/app/repository"
+)
+
+// VerificationTaskService menjalankan pekerjaan lanjutan prestasi VERIFIED yang dicatat di
+// verification_tasks. Task langsung dicoba setelah verifikasi; yang gagal diulang Start dengan
+// backoff sampai berhasil, sehingga status VERIFIED tidak pernah tertinggal tanpa poin atau atestasi.
+type VerificationTaskService struct {
+	Repo         *repository.VerificationTaskRepository
+	AchRepo      *repository.AchievementRepository
+	Points       *PointService
+	Attestations *AttestationService
+}
+
+func NewVerificationTaskService(repo *repository.VerificationTaskRepository, achRepo *repository.AchievementRepository, points *PointService, attestations *AttestationService) *VerificationTaskService {
+	return &VerificationTaskService{Repo: repo, AchRepo: achRepo, Points: points, Attestations: attestations}
+}
+
+// taskLease adalah lama task yang sedang dijalankan dikunci dari worker lain.
+const taskLease = 5 * time.Minute
+
+// taskBackoff: 1 menit, lalu berlipat sampai maksimal 1 jam.
+func taskBackoff(attempts int) time.Duration {
+	d := time.Minute
+	for i := 0; i < attempts && d < time.Hour; i++ {
+		d *= 2
+	}
+	if d > time.Hour {
+		d = time.Hour
+	}
+	return d
+}
+
+// RunFor menjalankan task prestasi refID yang masih tertunda dan belum diklaim worker lain.
+// Kegagalan hanya dicatat; task tetap tersimpan dan diulang Start.
+func (s *VerificationTaskService) RunFor(ctx context.Context, refID string) {
+	tasks, err := s.Repo.ClaimByAchievement(ctx, refID, taskLease)
+	if err != nil {
+		log.Printf("⚠️ Gagal membaca task verifikasi %s: %v", refID, err)
+		return
+	}
+	s.runAll(ctx, tasks)
+}
+
+// RunDue menjalankan task yang sudah waktunya diulang.
+func (s *VerificationTaskService) RunDue(ctx context.Context) {
+	tasks, err := s.Repo.ClaimDue(ctx, 100, taskLease)
+	if err != nil {
+		log.Printf("⚠️ Gagal membaca task verifikasi: %v", err)
+		return
+	}
+	s.runAll(ctx, tasks)
+}
+
+func (s *VerificationTaskService) runAll(ctx context.Context, tasks []postgres.VerificationTask) {
+	for _, task := range tasks {
+		if err := s.run(ctx, task); err != nil {
+			log.Printf("⚠️ Task verifikasi %s untuk %s gagal (percobaan %d): %v", task.Kind, task.AchievementID, task.Attempts+1, err)
+			if err := s.Repo.Fail(ctx, task.ID, err, taskBackoff(task.Attempts)); err != nil {
+				log.Printf("⚠️ Gagal menjadwalkan ulang task %s: %v", task.ID, err)
+			}
+			continue
+		}
+		if err := s.Repo.Done(ctx, task.ID, task.CreatedAt); err != nil {
+			log.Printf("⚠️ Gagal menandai task %s selesai: %v", task.ID, err)
+		}
+	}
+}
+
+func (s *VerificationTaskService) run(ctx context.Context, task postgres.VerificationTask) error {
+	ref, err := s.AchRepo.FindRefByID(ctx, task.AchievementID)
+	// Prestasi sudah di tong sampah atau tidak lagi VERIFIED: task tidak relevan lagi
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if ref.Status != StatusVerified {
+		return nil
+	}
+	switch task.Kind {
+	case repository.TaskPoints:
+		return s.Points.ApplyToRef(ctx, ref)
+	case repository.TaskAttestation:
+		_, err := s.Attestations.Issue(ctx, ref, task.VerifierID)
+		return err
+	default:
+		return fmt.Errorf("jenis task tidak dikenal: %s", task.Kind)
+	}
+}
+
+// Start mengulang task yang gagal setiap interval, termasuk sisa dari proses sebelumnya saat start.
+func (s *VerificationTaskService) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	s.RunDue(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.RunDue(ctx)
+		}
+	}
+}
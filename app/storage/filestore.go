@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+var (
+	ErrFileNotFound = errors.New("file not found")
+	ErrInvalidKey   = errors.New("invalid storage key")
+)
+
+// FileStore adalah backend penyimpanan lampiran. Key berbentuk path relatif bergaya "a/b/c.pdf".
+type FileStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// SignedURL menghasilkan URL unduhan yang kadaluarsa setelah ttl.
+	SignedURL(ctx context.Context, key, fileName string, ttl time.Duration) (string, error)
+}
+
+// NewFromEnv memilih driver dari STORAGE_DRIVER ("local" default, atau "s3").
+func NewFromEnv(ctx context.Context) (FileStore, error) {
+	switch driver := os.Getenv("STORAGE_DRIVER"); driver {
+	case "", "local":
+		dir := os.Getenv("LOCAL_STORAGE_DIR")
+		if dir == "" {
+			dir = "uploads"
+		}
+		// Kunci URL sengaja terpisah dari JWT_SECRET: bocornya salah satu tidak boleh membuka yang lain
+		secret := os.Getenv("FILE_URL_SECRET")
+		if secret != "" && secret == os.Getenv("JWT_SECRET") {
+			return nil, errors.New("FILE_URL_SECRET tidak boleh sama dengan JWT_SECRET")
+		}
+		baseURL := strings.TrimRight(os.Getenv("PUBLIC_BASE_URL"), "/") + "/api/v1/files"
+		return NewLocalStore(dir, baseURL, secret)
+	case "s3":
+		return NewS3Store(ctx, S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			Region:    os.Getenv("S3_REGION"),
+			UseSSL:    os.Getenv("S3_USE_SSL") == "true",
+		})
+	default:
+		return nil, fmt.Errorf("STORAGE_DRIVER tidak dikenal: %s", driver)
+	}
+}
+
+// cleanKey menolak key absolut atau yang keluar dari root ("..").
+func cleanKey(key string) (string, error) {
+	key = strings.TrimPrefix(strings.ReplaceAll(key, "\\", "/"), "/")
+	if key == "" {
+		return "", ErrInvalidKey
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return "", ErrInvalidKey
+		}
+	}
+	return key, nil
+}
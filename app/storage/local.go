@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrSignatureInvalid = errors.New("signature invalid")
+	ErrSignatureExpired = errors.New("signature expired")
+)
+
+// LocalStore menyimpan file di filesystem lokal dan menandatangani URL unduhan dengan HMAC.
+// URL ditangani oleh endpoint GET /api/v1/files/*key.
+type LocalStore struct {
+	Root    string
+	BaseURL string
+	secret  []byte
+}
+
+func NewLocalStore(root, baseURL, secret string) (*LocalStore, error) {
+	if secret == "" {
+		return nil, errors.New("FILE_URL_SECRET wajib diisi untuk storage lokal")
+	}
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, err
+	}
+	return &LocalStore{Root: root, BaseURL: baseURL, secret: []byte(secret)}, nil
+}
+
+func (s *LocalStore) path(key string) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.Root, filepath.FromSlash(key)), nil
+}
+
+func (s *LocalStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+
+	// Tulis ke file sementara lalu rename supaya tidak ada file setengah jadi
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".upload-*")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+func (s *LocalStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if os.IsNotExist(err) {
+		return nil, ErrFileNotFound
+	}
+	return f, err
+}
+
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// sign ikut menandatangani name supaya nama file (dan ekstensinya) di Content-Disposition tidak
+// bisa diganti pemegang link.
+func (s *LocalStore) sign(key string, expires int64, name string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + "\n" + strconv.FormatInt(expires, 10) + "\n" + name))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *LocalStore) SignedURL(ctx context.Context, key, fileName string, ttl time.Duration) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	expires := time.Now().Add(ttl).Unix()
+	q := url.Values{}
+	q.Set("expires", strconv.FormatInt(expires, 10))
+	q.Set("sig", s.sign(key, expires, fileName))
+	if fileName != "" {
+		q.Set("name", fileName)
+	}
+	return s.BaseURL + "/" + (&url.URL{Path: key}).EscapedPath() + "?" + q.Encode(), nil
+}
+
+// Verify memeriksa tanda tangan, nama file, dan masa berlaku URL hasil SignedURL.
+func (s *LocalStore) Verify(key, expires, name, sig string) error {
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return ErrSignatureInvalid
+	}
+	if !hmac.Equal([]byte(sig), []byte(s.sign(key, exp, name))) {
+		return ErrSignatureInvalid
+	}
+	if time.Now().Unix() > exp {
+		return ErrSignatureExpired
+	}
+	return nil
+}
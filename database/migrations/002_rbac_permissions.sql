@@ -0,0 +1,52 @@
+-- Permission per resource/action dan relasi role_permissions.
+CREATE TABLE IF NOT EXISTS permissions (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name        VARCHAR(100) NOT NULL UNIQUE,
+    resource    VARCHAR(50) NOT NULL,
+    action      VARCHAR(50) NOT NULL,
+    description TEXT,
+    UNIQUE (resource, action)
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id       UUID NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    permission_id UUID NOT NULL REFERENCES permissions(id) ON DELETE CASCADE,
+    PRIMARY KEY (role_id, permission_id)
+);
+
+INSERT INTO permissions (name, resource, action, description) VALUES
+    ('user:read',                 'user',        'read',            'Melihat daftar dan detail semua user'),
+    ('user:create',               'user',        'create',          'Membuat user baru'),
+    ('user:update',               'user',        'update',          'Mengubah data user lain'),
+    ('user:delete',               'user',        'delete',          'Menonaktifkan user'),
+    ('user:assign_role',          'user',        'assign_role',     'Mengganti role user'),
+    ('role:manage',               'role',        'manage',          'Mengelola role dan permission'),
+    ('achievement:read',          'achievement', 'read',            'Melihat prestasi'),
+    ('achievement:create',        'achievement', 'create',          'Membuat prestasi'),
+    ('achievement:update',        'achievement', 'update',          'Mengubah prestasi'),
+    ('achievement:delete',        'achievement', 'delete',          'Menghapus prestasi'),
+    ('achievement:submit',        'achievement', 'submit',          'Mengajukan prestasi untuk verifikasi'),
+    ('achievement:verify',        'achievement', 'verify',          'Memverifikasi atau menolak prestasi'),
+    ('mahasiswa:read',            'mahasiswa',   'read',            'Melihat data mahasiswa'),
+    ('mahasiswa:assign_advisor',  'mahasiswa',   'assign_advisor',  'Mengganti dosen wali mahasiswa'),
+    ('dosen:read',                'dosen',       'read',            'Melihat data dosen'),
+    ('report:read',               'report',      'read',            'Melihat laporan dan statistik')
+ON CONFLICT (name) DO NOTHING;
+
+-- Admin: semua permission
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT '11111111-1111-1111-1111-111111111111', id FROM permissions
+ON CONFLICT DO NOTHING;
+
+-- Mahasiswa
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT '22222222-2222-2222-2222-222222222222', id FROM permissions
+WHERE name IN ('achievement:read', 'achievement:create', 'achievement:update', 'achievement:delete',
+               'achievement:submit', 'mahasiswa:read', 'report:read')
+ON CONFLICT DO NOTHING;
+
+-- Dosen
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT '33333333-3333-3333-3333-333333333333', id FROM permissions
+WHERE name IN ('achievement:read', 'achievement:verify', 'mahasiswa:read', 'dosen:read', 'report:read')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,7 @@
+INSERT INTO permissions (name, resource, action, description) VALUES
+    ('system:maintain', 'system', 'maintain', 'Menjalankan job pemeliharaan (rekonsiliasi data)')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT '11111111-1111-1111-1111-111111111111', id FROM permissions WHERE name = 'system:maintain'
+ON CONFLICT DO NOTHING;
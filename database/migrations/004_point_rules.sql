@@ -0,0 +1,23 @@
+-- Rulebook poin prestasi. level/rank NULL = berlaku untuk semua nilai (wildcard).
+-- rank berisi peringkat (competition), jabatan (organization) atau indeksasi (publication).
+CREATE TABLE IF NOT EXISTS point_rules (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    achievement_type VARCHAR(50) NOT NULL,
+    level            VARCHAR(50),
+    rank             VARCHAR(50),
+    points           INT NOT NULL CHECK (points >= 0),
+    description      TEXT,
+    created_at       TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_point_rules_key
+    ON point_rules (achievement_type, COALESCE(level, ''), COALESCE(rank, ''));
+
+INSERT INTO permissions (name, resource, action, description) VALUES
+    ('point_rule:manage', 'point_rule', 'manage', 'Mengelola rulebook poin prestasi')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT '11111111-1111-1111-1111-111111111111', id FROM permissions WHERE name = 'point_rule:manage'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,8 @@
+-- Soft delete prestasi: baris dengan deleted_at terisi ada di tong sampah sampai dipurge
+-- setelah masa retensi (TRASH_RETENTION).
+ALTER TABLE achievement_references
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS deleted_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_achievement_references_deleted_at
+    ON achievement_references (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,15 @@
+-- Thread komentar review per prestasi. Komentar bisa dikaitkan ke satu field konten
+-- (anchor_field, mis. "title" atau "details.rank") atau ke satu lampiran (anchor_attachment_id).
+CREATE TABLE IF NOT EXISTS achievement_comments (
+    id                   UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    achievement_id       UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+    parent_id            UUID REFERENCES achievement_comments(id) ON DELETE CASCADE,
+    author_id            UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    anchor_field         VARCHAR(100),
+    anchor_attachment_id VARCHAR(100),
+    body                 TEXT NOT NULL,
+    created_at           TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_achievement_comments_achievement
+    ON achievement_comments (achievement_id, created_at);
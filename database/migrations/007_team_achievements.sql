@@ -0,0 +1,27 @@
+-- Prestasi tim: setiap prestasi punya daftar anggota (mahasiswa) dengan peran ketua/anggota.
+-- Pembuat prestasi (achievement_references.student_id) selalu tercatat sebagai anggota.
+CREATE TABLE IF NOT EXISTS achievement_members (
+    achievement_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+    student_id     UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role           VARCHAR(10) NOT NULL CHECK (role IN ('ketua', 'anggota')),
+    added_at       TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (achievement_id, student_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_achievement_members_student ON achievement_members (student_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_achievement_members_one_ketua
+    ON achievement_members (achievement_id) WHERE role = 'ketua';
+
+INSERT INTO achievement_members (achievement_id, student_id, role, added_at)
+SELECT id, student_id, 'ketua', created_at FROM achievement_references
+ON CONFLICT DO NOTHING;
+
+-- Persetujuan per dosen wali untuk mode TEAM_VERIFICATION=per_advisor. Dikosongkan setiap
+-- prestasi kembali ke PENDING (submit ulang).
+CREATE TABLE IF NOT EXISTS achievement_advisor_approvals (
+    achievement_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+    advisor_id     UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    notes          TEXT,
+    approved_at    TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (achievement_id, advisor_id)
+);
@@ -0,0 +1,22 @@
+-- Katalog kompetisi/event resmi yang dikelola admin. Prestasi menautkan event lewat field
+-- eventId di dokumen Mongo; level event menjadi default tingkat prestasi dan dasar rulebook poin.
+CREATE TABLE IF NOT EXISTS events (
+    id                    UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name                  VARCHAR(255) NOT NULL,
+    organizer             VARCHAR(255) NOT NULL,
+    level                 VARCHAR(50) NOT NULL,
+    year                  INT NOT NULL,
+    nationally_recognized BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at            TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at            TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_events_name_year ON events (LOWER(name), year);
+
+INSERT INTO permissions (name, resource, action, description) VALUES
+    ('event:manage', 'event', 'manage', 'Mengelola katalog kompetisi/event')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT '11111111-1111-1111-1111-111111111111', id FROM permissions WHERE name = 'event:manage'
+ON CONFLICT DO NOTHING;
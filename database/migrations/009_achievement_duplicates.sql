@@ -0,0 +1,18 @@
+-- Pasangan prestasi yang terdeteksi mirip saat create/submit. Pasangan disimpan tidak berarah
+-- (achievement_a < achievement_b). decision diputuskan dosen wali/admin: suspected (belum
+-- ditinjau), confirmed (memang duplikat) atau dismissed (bukan duplikat, tidak ditandai lagi).
+CREATE TABLE IF NOT EXISTS achievement_duplicates (
+    achievement_a UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+    achievement_b UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+    score         DOUBLE PRECISION NOT NULL,
+    reasons       TEXT[] NOT NULL DEFAULT '{}',
+    decision      VARCHAR(20) NOT NULL DEFAULT 'suspected' CHECK (decision IN ('suspected', 'confirmed', 'dismissed')),
+    decided_by    UUID REFERENCES users(id) ON DELETE SET NULL,
+    decided_at    TIMESTAMP,
+    notes         TEXT,
+    detected_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (achievement_a, achievement_b),
+    CHECK (achievement_a < achievement_b)
+);
+
+CREATE INDEX IF NOT EXISTS idx_achievement_duplicates_b ON achievement_duplicates (achievement_b);
@@ -0,0 +1,29 @@
+-- Dokumen SKPI (Surat Keterangan Pendamping Ijazah) yang pernah digenerate. Setiap generate
+-- menambah versi baru per mahasiswa; file PDF disimpan di FileStore supaya bisa diunduh ulang.
+CREATE SEQUENCE IF NOT EXISTS skpi_document_seq;
+
+CREATE TABLE IF NOT EXISTS skpi_documents (
+    id                UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    student_id        UUID NOT NULL REFERENCES mahasiswa(id) ON DELETE CASCADE,
+    version           INT NOT NULL,
+    document_number   VARCHAR(100) NOT NULL UNIQUE,
+    storage_key       TEXT NOT NULL,
+    sha256            CHAR(64) NOT NULL,
+    size              BIGINT NOT NULL,
+    achievement_count INT NOT NULL,
+    total_points      INT NOT NULL,
+    signatory_name    VARCHAR(255) NOT NULL,
+    signatory_title   VARCHAR(255) NOT NULL,
+    signatory_nip     VARCHAR(50),
+    generated_by      UUID REFERENCES users(id) ON DELETE SET NULL,
+    generated_at      TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (student_id, version)
+);
+
+INSERT INTO permissions (name, resource, action, description) VALUES
+    ('skpi:generate', 'skpi', 'generate', 'Menerbitkan dokumen SKPI mahasiswa')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT '11111111-1111-1111-1111-111111111111', id FROM permissions WHERE name = 'skpi:generate'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,27 @@
+-- Atestasi bertanda tangan Ed25519 yang diterbitkan saat prestasi VERIFIED. payload disimpan persis
+-- seperti yang ditandatangani; achievement_id dibiarkan NULL jika prestasi dihapus permanen supaya
+-- status pencabutan tetap bisa diperiksa pihak ketiga.
+CREATE TABLE IF NOT EXISTS achievement_attestations (
+    id                UUID PRIMARY KEY,
+    achievement_id    UUID REFERENCES achievement_references(id) ON DELETE SET NULL,
+    key_id            VARCHAR(32) NOT NULL,
+    payload           TEXT NOT NULL,
+    signature         TEXT NOT NULL,
+    content_sha256    CHAR(64) NOT NULL,
+    student_id        UUID NOT NULL,
+    verifier_id       UUID NOT NULL,
+    issued_at         TIMESTAMP NOT NULL,
+    revoked_at        TIMESTAMP,
+    revoked_by        UUID REFERENCES users(id) ON DELETE SET NULL,
+    revocation_reason TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_attestations_achievement ON achievement_attestations (achievement_id, issued_at DESC);
+
+INSERT INTO permissions (name, resource, action, description) VALUES
+    ('attestation:manage', 'attestation', 'manage', 'Menerbitkan ulang dan mencabut atestasi prestasi')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT '11111111-1111-1111-1111-111111111111', id FROM permissions WHERE name = 'attestation:manage'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,12 @@
+-- Assertion Open Badges untuk prestasi VERIFIED, satu per anggota tim penerima. Dibuat saat mahasiswa
+-- pertama kali mengekspor badge; status hosted (revoked atau tidak) dihitung dari status prestasi,
+-- keanggotaan tim, dan atestasi terbarunya.
+CREATE TABLE IF NOT EXISTS badge_assertions (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    achievement_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+    student_id     UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    salt           VARCHAR(64) NOT NULL,
+    issued_on      TIMESTAMP NOT NULL,
+    created_at     TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (achievement_id, student_id)
+);
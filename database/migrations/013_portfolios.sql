@@ -0,0 +1,22 @@
+-- Portofolio publik mahasiswa (opt-in). Diakses tanpa login lewat slug acak yang bisa diganti
+-- kapan saja untuk mencabut tautan lama; hanya prestasi VERIFIED yang dipilih yang ditampilkan.
+CREATE TABLE IF NOT EXISTS portfolios (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    student_id UUID NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    slug       VARCHAR(64) UNIQUE,
+    published  BOOLEAN NOT NULL DEFAULT FALSE,
+    headline   VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Pilihan per prestasi; prestasi tanpa baris di sini tidak tampil. Lampiran disembunyikan kecuali
+-- show_attachments dinyalakan.
+CREATE TABLE IF NOT EXISTS portfolio_items (
+    portfolio_id     UUID NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+    achievement_id   UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+    visible          BOOLEAN NOT NULL DEFAULT FALSE,
+    show_attachments BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at       TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (portfolio_id, achievement_id)
+);
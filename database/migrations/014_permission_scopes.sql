@@ -0,0 +1,20 @@
+-- Permission scope: tanpa *_all, user hanya melihat/mengelola data miliknya sendiri atau mahasiswa bimbingannya.
+INSERT INTO permissions (name, resource, action, description) VALUES
+    ('achievement:read_all',   'achievement', 'read_all',   'Melihat semua prestasi, bukan hanya milik sendiri atau mahasiswa bimbingan'),
+    ('achievement:verify_all', 'achievement', 'verify_all', 'Memverifikasi prestasi mahasiswa mana pun tanpa persetujuan dosen wali'),
+    ('achievement:manage_all', 'achievement', 'manage_all', 'Mengubah, melihat tong sampah dan memulihkan prestasi milik siapa pun'),
+    ('mahasiswa:read_all',     'mahasiswa',   'read_all',   'Melihat data semua mahasiswa'),
+    ('dosen:read_all',         'dosen',       'read_all',   'Melihat data semua dosen beserta bimbingannya'),
+    ('report:read_all',        'report',      'read_all',   'Melihat statistik global dan laporan semua mahasiswa'),
+    ('portfolio:manage',       'portfolio',   'manage',     'Mengelola portofolio publik milik sendiri')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT '11111111-1111-1111-1111-111111111111', id FROM permissions
+WHERE name IN ('achievement:read_all', 'achievement:verify_all', 'achievement:manage_all',
+               'mahasiswa:read_all', 'dosen:read_all', 'report:read_all')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT '22222222-2222-2222-2222-222222222222', id FROM permissions WHERE name = 'portfolio:manage'
+ON CONFLICT DO NOTHING;
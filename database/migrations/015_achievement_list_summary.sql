@@ -0,0 +1,17 @@
+-- Salinan field konten Mongo yang dipakai filter dan sort GET /achievements, supaya daftar prestasi
+-- dipaginasi dengan satu query Postgres. content_updated_at NULL berarti belum disalin; baris lama
+-- diisi ReconcileService saat start.
+ALTER TABLE achievement_references
+    ADD COLUMN IF NOT EXISTS title              TEXT,
+    ADD COLUMN IF NOT EXISTS achievement_type   VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS event_id           VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS tags               TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS points             INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS content_updated_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_achievement_references_created_at
+    ON achievement_references (created_at DESC, id DESC) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_achievement_references_type
+    ON achievement_references (achievement_type) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_achievement_references_tags
+    ON achievement_references USING GIN (tags);
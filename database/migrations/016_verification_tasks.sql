@@ -0,0 +1,15 @@
+-- Pekerjaan lanjutan prestasi VERIFIED (hitung poin, terbitkan atestasi). Dicatat dalam transaksi yang
+-- sama dengan perpindahan status, lalu dijalankan dan diulang VerificationTaskService sampai berhasil.
+CREATE TABLE IF NOT EXISTS verification_tasks (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    achievement_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+    kind           VARCHAR(20) NOT NULL,
+    verifier_id    UUID NOT NULL,
+    attempts       INT NOT NULL DEFAULT 0,
+    last_error     TEXT,
+    next_run_at    TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_at     TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (achievement_id, kind)
+);
+
+CREATE INDEX IF NOT EXISTS idx_verification_tasks_due ON verification_tasks (next_run_at);
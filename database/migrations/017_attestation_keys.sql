@@ -0,0 +1,8 @@
+-- Kunci publik atestasi per key id. Kunci aktif didaftarkan saat server start; kunci lama tetap
+-- disimpan (retired_at terisi) supaya atestasi yang ditandatangani sebelum rotasi masih bisa diverifikasi.
+CREATE TABLE IF NOT EXISTS attestation_keys (
+    key_id     VARCHAR(32) PRIMARY KEY,
+    public_key TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    retired_at TIMESTAMP
+);
@@ -0,0 +1,7 @@
+-- Referensi yang dokumen Mongo-nya hilang tidak lagi dihapus otomatis oleh rekonsiliasi; orphaned_at
+-- ditandai supaya admin bisa meninjau (mis. memulihkan Mongo dari backup) sebelum menghapusnya.
+ALTER TABLE achievement_references
+    ADD COLUMN IF NOT EXISTS orphaned_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_achievement_references_orphaned_at
+    ON achievement_references (orphaned_at) WHERE orphaned_at IS NOT NULL;
@@ -0,0 +1,4 @@
+-- Field details yang bersifat pribadi (mis. nomor sertifikat) hanya tampil di portofolio publik jika
+-- show_private_details dinyalakan untuk prestasi tersebut.
+ALTER TABLE portfolio_items
+    ADD COLUMN IF NOT EXISTS show_private_details BOOLEAN NOT NULL DEFAULT FALSE;
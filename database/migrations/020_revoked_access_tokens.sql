@@ -0,0 +1,9 @@
+-- Denylist access token (jti) yang sudah di-logout sebelum masa berlakunya habis.
+-- Baris boleh dihapus setelah expires_at lewat karena token-nya sudah ditolak oleh exp.
+CREATE TABLE IF NOT EXISTS revoked_access_tokens (
+    jti         UUID PRIMARY KEY,
+    expires_at  TIMESTAMP NOT NULL,
+    revoked_at  TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_access_tokens_expires ON revoked_access_tokens (expires_at);
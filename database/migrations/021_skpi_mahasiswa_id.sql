@@ -0,0 +1,9 @@
+-- skpi_documents.student_id sebenarnya menyimpan mahasiswa.id (bukan users.id seperti student_id
+-- di tabel lain), jadi kolomnya diganti nama supaya tidak tertukar.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_name = 'skpi_documents' AND column_name = 'student_id') THEN
+        ALTER TABLE skpi_documents RENAME COLUMN student_id TO mahasiswa_id;
+    END IF;
+END $$;
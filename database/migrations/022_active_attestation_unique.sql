@@ -0,0 +1,14 @@
+-- Maksimal satu atestasi aktif per prestasi. Penerbitan yang berebut (mis. task verifikasi yang
+-- berjalan dua kali) sekarang gagal di index ini alih-alih mencabut atestasi yang sudah dibagikan.
+-- Duplikat lama dirapikan dulu: yang terbaru dipertahankan, sisanya dicabut sebagai superseded.
+UPDATE achievement_attestations a SET revoked_at = NOW(), revocation_reason = 'superseded'
+WHERE a.revoked_at IS NULL
+  AND a.achievement_id IS NOT NULL
+  AND EXISTS (
+      SELECT 1 FROM achievement_attestations b
+      WHERE b.achievement_id = a.achievement_id AND b.revoked_at IS NULL
+        AND (b.issued_at, b.id) > (a.issued_at, a.id)
+  );
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_attestations_active_achievement
+    ON achievement_attestations (achievement_id) WHERE revoked_at IS NULL;
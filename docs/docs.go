@@ -17,34 +17,115 @@ const docTemplate = `{
     "paths": {
         "/achievements": {
             "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
+                "description": "Daftar prestasi yang boleh dilihat (semua dengan achievement:read_all; selain itu milik sendiri dan mahasiswa bimbingan), dengan pagination, filter dan sorting. Filter dan halaman dihitung di Postgres; \"missing\" berisi referensi di halaman ini yang kontennya hilang.",
                 "tags": [
                     "Achievements"
                 ],
                 "summary": "List Achievements",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Halaman (mulai 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Jumlah per halaman (maks 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "DRAFT | PENDING | VERIFIED | REJECTED",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tipe prestasi",
+                        "name": "achievement_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event katalog yang ditautkan",
+                        "name": "event_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Tag (semua harus cocok)",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Dibuat sejak (YYYY-MM-DD)",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Dibuat sampai (YYYY-MM-DD, inklusif)",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Program studi mahasiswa",
+                        "name": "program_study",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Poin minimum",
+                        "name": "min_points",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Poin maksimum",
+                        "name": "max_points",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "created_at",
+                        "description": "created_at | updated_at | points | title",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "desc",
+                        "description": "asc | desc",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dto.AchievementResponse"
-                            }
+                            "$ref": "#/definitions/dto.AchievementListResponse"
                         }
                     }
-                }
-            },
-            "post": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Kirim data + file sekaligus (Multipart Form)",
+                ]
+            },
+            "post": {
+                "description": "Kirim data + file sekaligus (Multipart Form). possible_duplicates berisi jumlah kemungkinan duplikat (judul/event/tanggal mirip atau lampiran identik).",
                 "consumes": [
                     "multipart/form-data"
                 ],
@@ -75,9 +156,21 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Poin",
-                        "name": "points",
+                        "type": "string",
+                        "description": "Details (JSON object sesuai schema achievement_type)",
+                        "name": "details",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event katalog; untuk competition mengisi default eventName/organizer/level",
+                        "name": "event_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Anggota tim lain (JSON array [{student_id, role: ketua|anggota}])",
+                        "name": "members",
                         "in": "formData"
                     },
                     {
@@ -95,135 +188,137 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     }
-                }
-            }
-        },
-        "/achievements/{id}": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
+                ]
+            }
+        },
+        "/achievements/bulk-decision": {
+            "post": {
+                "description": "Keputusan yang sama untuk banyak prestasi sekaligus (maks 100). Tiap item diperiksa hak dosen walinya dan dicatat di history seperti verify satuan; kegagalan satu item tidak membatalkan item lain.",
                 "tags": [
                     "Achievements"
                 ],
-                "summary": "Get Detail",
+                "summary": "Bulk Verify/Reject Achievements (Dosen)",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.BulkDecisionRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.AchievementResponse"
+                            "$ref": "#/definitions/service.BulkDecisionResponse"
                         }
                     }
-                }
-            },
-            "put": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
+                ]
+            }
+        },
+        "/achievements/schemas": {
+            "get": {
+                "description": "Schema field details per achievement_type, untuk merender form secara dinamis.",
                 "tags": [
                     "Achievements"
                 ],
-                "summary": "Update Achievement Content",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Body",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
+                "summary": "List Achievement Detail Schemas",
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/service.CreateAchievementRequest"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
-                ],
-                "responses": {}
-            },
-            "delete": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
+                ]
+            }
+        },
+        "/achievements/schemas/{type}": {
+            "get": {
                 "tags": [
                     "Achievements"
                 ],
-                "summary": "Delete Achievement",
+                "summary": "Get Achievement Detail Schema",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID",
-                        "name": "id",
+                        "description": "achievement_type",
+                        "name": "type",
                         "in": "path",
                         "required": true
                     }
                 ],
-                "responses": {}
-            }
-        },
-        "/achievements/{id}/attachments": {
-            "post": {
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.DetailSchema"
+                        }
+                    }
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Upload file bukti (bisa berkali-kali).",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+                ]
+            }
+        },
+        "/achievements/trash": {
+            "get": {
+                "description": "Tong sampah milik sendiri; dengan achievement:manage_all semua (opsional filter student_id).",
                 "tags": [
                     "Achievements"
                 ],
-                "summary": "Upload Attachment",
+                "summary": "List Trashed Achievements",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "file",
-                        "description": "File",
-                        "name": "file",
-                        "in": "formData",
-                        "required": true
+                        "description": "Filter mahasiswa (achievement:manage_all)",
+                        "name": "student_id",
+                        "in": "query"
                     }
                 ],
-                "responses": {}
-            }
-        },
-        "/achievements/{id}/history": {
-            "get": {
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
+                ]
+            }
+        },
+        "/achievements/{id}": {
+            "get": {
                 "tags": [
                     "Achievements"
                 ],
-                "summary": "Get History",
+                "summary": "Get Detail",
                 "parameters": [
                     {
                         "type": "string",
@@ -233,20 +328,26 @@ const docTemplate = `{
                         "required": true
                     }
                 ],
-                "responses": {}
-            }
-        },
-        "/achievements/{id}/reject": {
-            "post": {
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AchievementResponse"
+                        }
+                    }
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
+                ]
+            },
+            "put": {
+                "description": "Ganti seluruh konten. If-Match opsional: jika dikirim (ETag dari GET detail) dan konten sudah diubah orang lain, update ditolak dengan 412.",
                 "tags": [
                     "Achievements"
                 ],
-                "summary": "Reject Achievement (Dosen)",
+                "summary": "Update Achievement Content",
                 "parameters": [
                     {
                         "type": "string",
@@ -255,30 +356,50 @@ const docTemplate = `{
                         "in": "path",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "ETag versi konten",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
                     {
                         "description": "Body",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/service.VerifyRequest"
+                            "$ref": "#/definitions/service.UpdateAchievementRequest"
                         }
                     }
                 ],
-                "responses": {}
-            }
-        },
-        "/achievements/{id}/submit": {
-            "post": {
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
+                ]
+            },
+            "delete": {
+                "description": "Memindahkan prestasi DRAFT ke tong sampah; bisa dipulihkan selama masa retensi.",
                 "tags": [
                     "Achievements"
                 ],
-                "summary": "Submit for Verification",
+                "summary": "Delete Achievement",
                 "parameters": [
                     {
                         "type": "string",
@@ -288,20 +409,22 @@ const docTemplate = `{
                         "required": true
                     }
                 ],
-                "responses": {}
-            }
-        },
-        "/achievements/{id}/verify": {
-            "post": {
+                "responses": {},
                 "security": [
                     {
                         "BearerAuth": []
                     }
+                ]
+            },
+            "patch": {
+                "description": "Update sebagian dengan JSON Merge Patch (RFC 7386): field yang tidak dikirim tidak berubah, null menghapus (mis. details.doi). Hasil akhir divalidasi dengan aturan yang sama seperti create. Wajib kirim If-Match berisi ETag dari GET detail (428 jika tidak ada); 412 jika konten sudah diubah orang lain.",
+                "consumes": [
+                    "application/json"
                 ],
                 "tags": [
                     "Achievements"
                 ],
-                "summary": "Verify Achievement (Dosen)",
+                "summary": "Patch Achievement Content",
                 "parameters": [
                     {
                         "type": "string",
@@ -311,149 +434,154 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Body",
+                        "type": "string",
+                        "description": "ETag versi konten",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Merge patch, mis. {\\",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/service.VerifyRequest"
+                            "type": "object"
                         }
                     }
                 ],
-                "responses": {}
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/auth/login": {
+        "/achievements/{id}/attachments": {
             "post": {
-                "description": "Masuk sistem dan dapatkan access token + refresh token",
+                "description": "Upload file bukti (bisa berkali-kali) oleh anggota tim selama status DRAFT atau REJECTED. Isi file diperiksa (PDF/JPG/PNG asli, bukan polyglot), dibatasi ukurannya, dan dipindai sebelum dilampirkan.",
                 "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "tags": [
-                    "Authentication"
+                    "Achievements"
                 ],
-                "summary": "Login User",
+                "summary": "Upload Attachment",
                 "parameters": [
                     {
-                        "description": "Kredensial Login",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/service.LoginRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
+                    {
+                        "type": "file",
+                        "description": "File",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
                     }
-                }
-            }
-        },
-        "/auth/logout": {
-            "post": {
+                ],
+                "responses": {},
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Keluar dari sistem",
-                "tags": [
-                    "Authentication"
-                ],
-                "summary": "Logout User",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    }
-                }
+                ]
             }
         },
-        "/auth/profile": {
+        "/achievements/{id}/attachments/{attId}": {
             "get": {
-                "security": [
+                "description": "Stream file lampiran. Aturan akses sama dengan detail prestasi.",
+                "tags": [
+                    "Achievements"
+                ],
+                "summary": "Download Attachment",
+                "parameters": [
                     {
-                        "BearerAuth": []
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Attachment ID",
+                        "name": "attId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Tampilkan inline (preview) alih-alih unduh",
+                        "name": "inline",
+                        "in": "query"
                     }
                 ],
-                "description": "Mendapatkan data user yang sedang login (Butuh Token)",
-                "tags": [
-                    "Authentication"
-                ],
-                "summary": "Get User Profile",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "file"
                         }
                     }
-                }
-            }
-        },
-        "/auth/refresh": {
-            "post": {
-                "description": "Dapatkan token baru menggunakan refresh token",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Hanya untuk pembuat atau anggota tim, selama prestasi berstatus DRAFT atau REJECTED.",
                 "tags": [
-                    "Authentication"
+                    "Achievements"
                 ],
-                "summary": "Refresh Access Token",
+                "summary": "Delete Attachment",
                 "parameters": [
                     {
-                        "description": "Refresh Token",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object",
-                            "properties": {
-                                "refreshToken": {
-                                    "type": "string"
-                                }
-                            }
-                        }
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Attachment ID",
+                        "name": "attId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -461,108 +589,106 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            }
-        },
-        "/dosen": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "tags": [
-                    "Dosen"
-                ],
-                "summary": "Get All Dosen",
-                "responses": {}
+                ]
             }
         },
-        "/dosen/{id}/advisees": {
+        "/achievements/{id}/attestation": {
             "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
+                "description": "Atestasi bertanda tangan terbaru prestasi (aktif atau sudah dicabut) beserta payload QR dan URL verifikasi publik.",
                 "tags": [
-                    "Dosen"
+                    "Attestations"
                 ],
-                "summary": "Get Dosen Advisees (With Authorization)",
+                "summary": "Get Achievement Attestation",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID Dosen (Tabel Dosen)",
+                        "description": "ID prestasi",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
-                "responses": {}
-            }
-        },
-        "/mahasiswa": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.AttestationResponse"
+                        }
                     }
-                ],
-                "tags": [
-                    "Mahasiswa"
-                ],
-                "summary": "Get All Mahasiswa (Filtered by Role)",
-                "responses": {}
-            }
-        },
-        "/mahasiswa/{id}": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
+                ]
+            },
+            "post": {
+                "description": "Terbitkan atestasi baru untuk prestasi VERIFIED (mis. setelah penerbitan otomatis gagal atau kunci dirotasi). Atestasi aktif sebelumnya dicabut sebagai superseded; jika atestasi aktif sudah mencakup isi, anggota tim, dan kunci yang sama, atestasi itu dikembalikan dengan 200.",
                 "tags": [
-                    "Mahasiswa"
+                    "Attestations"
                 ],
-                "summary": "Get Mahasiswa Detail (Privacy Check)",
+                "summary": "Reissue Attestation (Admin)",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID Mahasiswa",
+                        "description": "ID prestasi",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
-                "responses": {}
-            }
-        },
-        "/mahasiswa/{id}/achievements": {
-            "get": {
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.AttestationResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/service.AttestationResponse"
+                        }
+                    }
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Mengambil daftar prestasi mahasiswa",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+                ]
+            }
+        },
+        "/achievements/{id}/badge": {
+            "get": {
+                "description": "Ekspor prestasi VERIFIED milik mahasiswa login (termasuk prestasi tim yang ia ikuti) sebagai Open Badge. version=2 (default) menghasilkan assertion hosted; version=3 menghasilkan OpenBadgeCredential yang hanya diberikan sebagai VC-JWT bertanda tangan. format=json (JSON-LD, hanya v2, default v2), jwt (hanya v3, default v3), png atau svg (gambar yang sudah di-bake).",
                 "tags": [
-                    "Mahasiswa"
+                    "Badges"
                 ],
-                "summary": "Get Mahasiswa Achievements",
+                "summary": "Export Open Badge",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID Mahasiswa",
+                        "description": "ID prestasi",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "json | jwt | png | svg",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "2 | 3",
+                        "name": "version",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -573,43 +699,28 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     }
-                }
-            }
-        },
-        "/mahasiswa/{id}/advisor": {
-            "put": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Mengubah dosen wali mahasiswa (Admin Only)",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+                ]
+            }
+        },
+        "/achievements/{id}/comments": {
+            "get": {
+                "description": "Thread komentar antara mahasiswa, dosen wali dan admin, tersusun sebagai pohon balasan.",
                 "tags": [
-                    "Mahasiswa"
+                    "Achievements"
                 ],
-                "summary": "Update Dosen Wali",
+                "summary": "List Review Comments",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID Mahasiswa",
+                        "description": "ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Request Body",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/service.UpdateAdvisorRequest"
-                        }
                     }
                 ],
                 "responses": {
@@ -620,60 +731,116 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     }
-                }
-            }
-        },
-        "/reports/statistics": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Melihat statistik total mahasiswa dan prestasi (Admin \u0026 Dosen)",
+                ]
+            },
+            "post": {
+                "description": "Mahasiswa pemilik, dosen wali, dan admin boleh berkomentar. Komentar bisa membalas komentar lain (parent_id) dan dikaitkan ke field atau lampiran.",
                 "tags": [
-                    "Reports"
+                    "Achievements"
                 ],
-                "summary": "Get Global Statistics",
-                "responses": {}
-            }
-        },
-        "/reports/student/{id}": {
-            "get": {
+                "summary": "Post Review Comment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Komentar",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.CreateCommentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/postgres.AchievementComment"
+                        }
+                    }
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Melihat ringkasan laporan prestasi per mahasiswa",
+                ]
+            }
+        },
+        "/achievements/{id}/duplicates/{otherId}": {
+            "put": {
+                "description": "Tandai pasangan prestasi sebagai duplikat (confirmed), bukan duplikat (dismissed; tidak ditandai lagi), atau kembalikan ke suspected.",
                 "tags": [
-                    "Reports"
+                    "Achievements"
                 ],
-                "summary": "Get Individual Student Report",
+                "summary": "Mark Duplicate Decision (Dosen)",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID Mahasiswa",
+                        "description": "ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID prestasi pasangan",
+                        "name": "otherId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Keputusan",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.DuplicateDecisionRequest"
+                        }
                     }
                 ],
-                "responses": {}
-            }
-        },
-        "/users": {
-            "get": {
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Hanya Admin.",
+                ]
+            }
+        },
+        "/achievements/{id}/history": {
+            "get": {
+                "description": "Timeline prestasi: perubahan status, revisi konten dan komentar review (terbaru dulu).",
                 "tags": [
-                    "Users (Admin)"
+                    "Achievements"
+                ],
+                "summary": "Get History",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get All Users",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -681,67 +848,96 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
-                    },
-                    "403": {
-                        "description": "Forbidden",
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/achievements/{id}/members": {
+            "get": {
+                "description": "Anggota tim prestasi (ketua/anggota) beserta dosen walinya. Pada mode per_advisor dan status PENDING, juga daftar persetujuan setiap dosen wali.",
+                "tags": [
+                    "Achievements"
+                ],
+                "summary": "List Team Members",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/service.TeamResponse"
                         }
                     }
-                }
-            },
-            "post": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Hanya Admin.",
+                ]
+            },
+            "put": {
+                "description": "Ganti daftar anggota tim. Hanya pembuat prestasi, dan hanya saat DRAFT atau REJECTED. Pembuat selalu tetap menjadi anggota.",
                 "tags": [
-                    "Users (Admin)"
+                    "Achievements"
                 ],
-                "summary": "Create User (Manual)",
+                "summary": "Replace Team Members",
                 "parameters": [
                     {
-                        "description": "Data User",
-                        "name": "request",
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Anggota",
+                        "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/service.CreateUserRequest"
+                            "$ref": "#/definitions/service.UpdateMembersRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
-                }
-            }
-        },
-        "/users/{id}": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Admin bisa lihat siapa saja. User biasa hanya bisa lihat dirinya sendiri.",
+                ]
+            }
+        },
+        "/achievements/{id}/members/me": {
+            "delete": {
+                "description": "Mahasiswa keluar dari tim prestasi yang mencantumkannya. Pembuat prestasi tidak bisa keluar. Pada prestasi VERIFIED, atestasi diterbitkan ulang untuk tim yang tersisa.",
                 "tags": [
-                    "Users (Admin)"
+                    "Achievements"
                 ],
-                "summary": "Get User Detail",
+                "summary": "Leave Team",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -750,22 +946,6 @@ const docTemplate = `{
                 "responses": {
                     "200": {
                         "description": "OK",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -773,40 +953,42 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            },
-            "put": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Admin bebas edit. User biasa hanya edit diri sendiri.",
+                ]
+            }
+        },
+        "/achievements/{id}/reject": {
+            "post": {
+                "description": "PENDING -\u003e REJECTED dengan catatan wajib.",
                 "tags": [
-                    "Users (Admin)"
+                    "Achievements"
                 ],
-                "summary": "Update User Data (Nama/Email)",
+                "summary": "Reject Achievement (Dosen)",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Data Update",
-                        "name": "request",
+                        "description": "Body",
+                        "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/service.UpdateUserRequest"
+                            "$ref": "#/definitions/service.RejectRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -814,23 +996,25 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            },
-            "delete": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Hanya Admin.",
+                ]
+            }
+        },
+        "/achievements/{id}/restore": {
+            "post": {
+                "description": "Pulihkan prestasi dari tong sampah selama masa retensi belum lewat (410 jika sudah).",
                 "tags": [
-                    "Users (Admin)"
+                    "Achievements"
                 ],
-                "summary": "Soft Delete User",
+                "summary": "Restore Trashed Achievement",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -846,37 +1030,28 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            }
-        },
-        "/users/{id}/role": {
-            "put": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Khusus Admin untuk mengubah jabatan user.",
+                ]
+            }
+        },
+        "/achievements/{id}/revisions": {
+            "get": {
+                "description": "Semua revisi konten prestasi (terbaru dulu), masing-masing dengan field yang berubah.",
                 "tags": [
-                    "Users (Admin)"
+                    "Achievements"
                 ],
-                "summary": "Change User Role (Promote/Demote)",
+                "summary": "List Content Revisions",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID Target",
+                        "description": "ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Role ID Baru",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/service.UpdateRoleRequest"
-                        }
                     }
                 ],
                 "responses": {
@@ -884,65 +1059,3226 @@ const docTemplate = `{
                         "description": "OK",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     }
-                }
-            }
-        }
-    },
-    "definitions": {
-        "dto.AchievementResponse": {
-            "type": "object",
-            "properties": {
-                "content": {
-                    "$ref": "#/definitions/mongodb.Achievement"
                 },
-                "ref_id": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/achievements/{id}/revisions/diff": {
+            "get": {
+                "tags": [
+                    "Achievements"
+                ],
+                "summary": "Diff Two Content Revisions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revisi awal",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revisi akhir",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.RevisionDiff"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/achievements/{id}/revisions/{rev}": {
+            "get": {
+                "tags": [
+                    "Achievements"
+                ],
+                "summary": "Get Content Revision",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Nomor revisi",
+                        "name": "rev",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/mongodb.AchievementRevision"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/achievements/{id}/submit": {
+            "post": {
+                "description": "DRAFT atau REJECTED -\u003e PENDING. Status lain menghasilkan 409. possible_duplicates berisi jumlah kemungkinan duplikat yang terdeteksi.",
+                "tags": [
+                    "Achievements"
+                ],
+                "summary": "Submit for Verification",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/achievements/{id}/verify": {
+            "post": {
+                "description": "PENDING -\u003e VERIFIED atau REJECTED. Status lain menghasilkan 409. Untuk prestasi tim pada mode per_advisor, VERIFIED baru berlaku setelah semua dosen wali anggota menyetujui (pending_advisors \u003e 0 selama menunggu).",
+                "tags": [
+                    "Achievements"
+                ],
+                "summary": "Verify Achievement (Dosen)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.VerifyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/orphan-refs": {
+            "get": {
+                "description": "Referensi Postgres yang dokumen Mongo-nya hilang, ditandai rekonsiliasi untuk ditinjau.",
+                "tags": [
+                    "Maintenance (Admin)"
+                ],
+                "summary": "List Orphaned References",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/orphan-refs/{id}": {
+            "delete": {
+                "description": "Hapus permanen referensi bertanda yatim beserta history-nya setelah ditinjau. Ditolak (409) jika dokumen Mongo-nya ternyata ada lagi.",
+                "tags": [
+                    "Maintenance (Admin)"
+                ],
+                "summary": "Delete Orphaned Reference",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID referensi",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/purge-trash": {
+            "post": {
+                "description": "Hapus permanen prestasi yang sudah lewat masa retensi sekarang juga (butuh permission system:maintain).",
+                "tags": [
+                    "Maintenance (Admin)"
+                ],
+                "summary": "Purge Expired Trash",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.PurgeReport"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/reconcile": {
+            "post": {
+                "description": "Jalankan rekonsiliasi data yatim sekarang juga (butuh permission system:maintain).",
+                "tags": [
+                    "Maintenance (Admin)"
+                ],
+                "summary": "Run Mongo/Postgres Reconciliation",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.ReconcileReport"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/reports/statistics": {
+            "get": {
+                "description": "report:read_all: statistik global. Dosen wali: statistik mahasiswa bimbingan (per status, antrian PENDING dan umur terlamanya, rincian per mahasiswa beserta poin, dan per tipe prestasi). Lainnya: jumlah prestasi per status miliknya.",
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Get Global Statistics",
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/reports/student/{id}": {
+            "get": {
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Get Individual Student Report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID Mahasiswa",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/attestations/public-key": {
+            "get": {
+                "description": "Kunci publik Ed25519 (base64) untuk memverifikasi tanda tangan atestasi secara offline. keys memuat juga kunci lama yang sudah dirotasi, dicocokkan lewat kid di atestasi.",
+                "tags": [
+                    "Attestations"
+                ],
+                "summary": "Attestation Public Key",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/attestations/verify": {
+            "get": {
+                "description": "Periksa keaslian atestasi dari ID atestasi atau payload QR. Tidak butuh token.",
+                "tags": [
+                    "Attestations"
+                ],
+                "summary": "Verify Attestation (Public)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID atestasi",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Payload QR (base64url payload . base64url signature)",
+                        "name": "payload",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.AttestationVerification"
+                        }
+                    }
+                }
+            }
+        },
+        "/attestations/{id}/revoke": {
+            "post": {
+                "tags": [
+                    "Attestations"
+                ],
+                "summary": "Revoke Attestation (Admin)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID atestasi",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Alasan",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.RevokeAttestationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Masuk sistem dan dapatkan access token + refresh token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Login User",
+                "parameters": [
+                    {
+                        "description": "Kredensial Login",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout": {
+            "post": {
+                "description": "Keluar dari sistem dan cabut sesi saat ini (semua refresh token dalam satu family).\nSesi diambil dari refreshToken di body, atau dari access token di header Authorization.\nAccess token di header ikut dicabut sehingga langsung ditolak walau belum kadaluarsa.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Logout User",
+                "parameters": [
+                    {
+                        "description": "Refresh Token",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/service.LogoutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/auth/profile": {
+            "get": {
+                "description": "Mendapatkan data user yang sedang login (Butuh Token)",
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Get User Profile",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Tukar refresh token dengan pasangan access token + refresh token baru (rotasi).\nRefresh token lama langsung tidak berlaku; memakai ulang token lama akan mencabut seluruh sesi.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Refresh Access Token",
+                "parameters": [
+                    {
+                        "description": "Refresh Token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.RefreshRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/badges/assertions/{id}": {
+            "get": {
+                "description": "Assertion hosted untuk verifikasi. Badge yang dicabut menghasilkan 410 dengan revoked=true sesuai spesifikasi Open Badges. version=3 mengembalikan OpenBadgeCredential sebagai VC-JWT bertanda tangan.",
+                "tags": [
+                    "Badges"
+                ],
+                "summary": "Open Badges Hosted Assertion",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID assertion",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "2 | 3",
+                        "name": "version",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "410": {
+                        "description": "Gone",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/badges/classes/{id}": {
+            "get": {
+                "description": "BadgeClass prestasi (hosted, publik).",
+                "tags": [
+                    "Badges"
+                ],
+                "summary": "Open Badges BadgeClass",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID prestasi",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/badges/classes/{id}/image": {
+            "get": {
+                "description": "Gambar PNG badge class (tanpa bake). Hasil render di-cache per versi konten prestasi.",
+                "produces": [
+                    "image/png"
+                ],
+                "tags": [
+                    "Badges"
+                ],
+                "summary": "Open Badges BadgeClass Image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID prestasi",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/badges/issuer": {
+            "get": {
+                "description": "Profil penerbit (hosted, publik). version=3 mengembalikan Profile Open Badges 3.0 beserta kunci penanda tangan VC-JWT (verificationMethod), termasuk kunci lama yang sudah dirotasi.",
+                "tags": [
+                    "Badges"
+                ],
+                "summary": "Open Badges Issuer Profile",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "2 | 3",
+                        "name": "version",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/badges/issuer/keys/{kid}": {
+            "get": {
+                "description": "Kunci publik (JWK Ed25519) yang dirujuk kid di header VC-JWT badge.",
+                "tags": [
+                    "Badges"
+                ],
+                "summary": "Open Badges Issuer Key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key ID",
+                        "name": "kid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/dosen": {
+            "get": {
+                "tags": [
+                    "Dosen"
+                ],
+                "summary": "Get All Dosen",
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/dosen/{id}/advisees": {
+            "get": {
+                "tags": [
+                    "Dosen"
+                ],
+                "summary": "Get Dosen Advisees (With Authorization)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID Dosen (Tabel Dosen)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/events": {
+            "get": {
+                "description": "Katalog event untuk dipilih saat membuat prestasi.",
+                "tags": [
+                    "Events"
+                ],
+                "summary": "List Events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cari nama / penyelenggara",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "campus | regional | national | international",
+                        "name": "level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Tahun",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Hanya event rekognisi nasional",
+                        "name": "recognized",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Create Event",
+                "parameters": [
+                    {
+                        "description": "Event",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.EventRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/events/{id}": {
+            "get": {
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Get Event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/postgres.Event"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Prestasi yang sudah tertaut tidak ikut diubah; level baru berlaku saat poin dihitung ulang.",
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Update Event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Event",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.EventRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Event yang masih ditautkan prestasi (termasuk di tong sampah) tidak bisa dihapus (409).",
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Delete Event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/files/{key}": {
+            "get": {
+                "description": "Endpoint tujuan URL bertanda tangan dari driver storage lokal. Tidak butuh token, tapi expires \u0026 sig wajib valid.",
+                "tags": [
+                    "Files"
+                ],
+                "summary": "Download File (signed URL)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Storage key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Unix timestamp kadaluarsa",
+                        "name": "expires",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanda tangan HMAC",
+                        "name": "sig",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nama file unduhan",
+                        "name": "name",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/mahasiswa": {
+            "get": {
+                "tags": [
+                    "Mahasiswa"
+                ],
+                "summary": "Get All Mahasiswa (Filtered by Permission)",
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/mahasiswa/{id}": {
+            "get": {
+                "tags": [
+                    "Mahasiswa"
+                ],
+                "summary": "Get Mahasiswa Detail (Privacy Check)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID Mahasiswa",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/mahasiswa/{id}/achievements": {
+            "get": {
+                "description": "Mengambil daftar prestasi mahasiswa. Referensi yang kontennya hilang dilaporkan di \"missing\".",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Mahasiswa"
+                ],
+                "summary": "Get Mahasiswa Achievements",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID Mahasiswa",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/mahasiswa/{id}/advisor": {
+            "put": {
+                "description": "Mengubah dosen wali mahasiswa (butuh permission mahasiswa:assign_advisor)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Mahasiswa"
+                ],
+                "summary": "Update Dosen Wali",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID Mahasiswa",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Request Body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.UpdateAdvisorRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/permissions": {
+            "get": {
+                "tags": [
+                    "Roles (Admin)"
+                ],
+                "summary": "List Permissions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "tags": [
+                    "Roles (Admin)"
+                ],
+                "summary": "Create Permission",
+                "parameters": [
+                    {
+                        "description": "Data Permission",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.PermissionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/permissions/{id}": {
+            "delete": {
+                "description": "role:manage tidak bisa dihapus (409).",
+                "tags": [
+                    "Roles (Admin)"
+                ],
+                "summary": "Delete Permission",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Permission ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/point-rules": {
+            "get": {
+                "tags": [
+                    "Point Rules (Admin)"
+                ],
+                "summary": "List Point Rules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "level/rank kosong = berlaku untuk semua nilai. Poin prestasi VERIFIED dihitung ulang di background.",
+                "tags": [
+                    "Point Rules (Admin)"
+                ],
+                "summary": "Create Point Rule",
+                "parameters": [
+                    {
+                        "description": "Rule",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.PointRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/point-rules/recalculate": {
+            "post": {
+                "description": "Hitung ulang poin semua prestasi VERIFIED sekarang juga.",
+                "tags": [
+                    "Point Rules (Admin)"
+                ],
+                "summary": "Recalculate Points",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.RecalculateResult"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/point-rules/{id}": {
+            "put": {
+                "tags": [
+                    "Point Rules (Admin)"
+                ],
+                "summary": "Update Point Rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rule",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.PointRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "tags": [
+                    "Point Rules (Admin)"
+                ],
+                "summary": "Delete Point Rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/portfolio": {
+            "get": {
+                "description": "Pengaturan portofolio publik beserta semua prestasi VERIFIED dan pilihan tampilnya.",
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Get My Portfolio (Mahasiswa)",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.PortfolioSettings"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Menyalakan atau mematikan portofolio publik dan mengubah headline. Slug tetap sama; gunakan POST /portfolio/slug untuk mencabut tautan lama.",
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Publish / Unpublish Portfolio (Mahasiswa)",
+                "parameters": [
+                    {
+                        "description": "Pengaturan",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.UpdatePortfolioRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.PortfolioSettings"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/portfolio/items/{id}": {
+            "put": {
+                "description": "Mengatur apakah prestasi VERIFIED tampil di portofolio publik, apakah lampirannya ikut dibagikan, dan apakah field details pribadi (mis. nomor sertifikat) ikut ditampilkan. Field yang tidak dikirim tidak berubah.",
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Set Portfolio Item Visibility (Mahasiswa)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID prestasi",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Pilihan tampil",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.UpdatePortfolioItemRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/postgres.PortfolioItem"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/portfolio/slug": {
+            "post": {
+                "description": "Mengganti slug portofolio dengan slug acak baru; tautan lama langsung tidak berlaku.",
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Revoke Portfolio Link (Mahasiswa)",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.PortfolioSettings"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/public/portfolios/{slug}": {
+            "get": {
+                "description": "Portofolio publik mahasiswa berdasarkan slug, tanpa token. Hanya prestasi VERIFIED yang dipilih mahasiswa; lampiran hanya jika dibagikan.",
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Public Portfolio (JSON)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Slug portofolio",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.PublicPortfolio"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/events": {
+            "get": {
+                "description": "Rekap prestasi per event katalog sesuai scope akses (achievement:read_all semua, selain itu milik sendiri dan mahasiswa bimbingan). Event tanpa prestasi tidak ditampilkan.",
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Achievements per Event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter level event",
+                        "name": "level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter tahun event",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Hanya event rekognisi nasional",
+                        "name": "recognized",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/reports/student/{id}/skpi": {
+            "get": {
+                "description": "Semua versi SKPI yang pernah diterbitkan untuk mahasiswa, terbaru dulu.",
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "List SKPI Versions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID Mahasiswa",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Render prestasi VERIFIED mahasiswa menjadi PDF SKPI dua bahasa (Indonesia/Inggris) dengan kop institusi, nomor dokumen, dan kolom tanda tangan. Setiap generate disimpan sebagai versi baru.",
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Generate SKPI (Admin)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID Mahasiswa",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Penanda tangan (opsional, default dari konfigurasi)",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/service.GenerateSkpiRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/postgres.SkpiDocument"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/reports/student/{id}/skpi/{version}": {
+            "get": {
+                "description": "Unduh ulang PDF SKPI versi tertentu persis seperti saat diterbitkan.",
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Download SKPI PDF",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID Mahasiswa",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Versi",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/roles": {
+            "get": {
+                "tags": [
+                    "Roles (Admin)"
+                ],
+                "summary": "List Roles",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Tambah role baru (mis. kaprodi, wakil dekan), lalu atur permission-nya.",
+                "tags": [
+                    "Roles (Admin)"
+                ],
+                "summary": "Create Role",
+                "parameters": [
+                    {
+                        "description": "Data Role",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.RoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/roles/{id}": {
+            "put": {
+                "tags": [
+                    "Roles (Admin)"
+                ],
+                "summary": "Update Role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data Role",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.RoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Role bawaan dan role yang masih dipakai user tidak bisa dihapus.",
+                "tags": [
+                    "Roles (Admin)"
+                ],
+                "summary": "Delete Role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/roles/{id}/permissions": {
+            "get": {
+                "tags": [
+                    "Roles (Admin)"
+                ],
+                "summary": "List Permission of Role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "tags": [
+                    "Roles (Admin)"
+                ],
+                "summary": "Grant Permission to Role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Permission",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.AssignPermissionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/roles/{id}/permissions/{permissionId}": {
+            "delete": {
+                "description": "role:manage tidak bisa dicabut dari role sendiri (409) supaya admin tidak terkunci.",
+                "tags": [
+                    "Roles (Admin)"
+                ],
+                "summary": "Revoke Permission from Role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Permission ID",
+                        "name": "permissionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/users": {
+            "get": {
+                "description": "Butuh permission user:read.",
+                "tags": [
+                    "Users (Admin)"
+                ],
+                "summary": "Get All Users",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Butuh permission user:create.",
+                "tags": [
+                    "Users (Admin)"
+                ],
+                "summary": "Create User (Manual)",
+                "parameters": [
+                    {
+                        "description": "Data User",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "description": "Pemilik permission user:read bisa lihat siapa saja. User biasa hanya bisa lihat dirinya sendiri.",
+                "tags": [
+                    "Users (Admin)"
+                ],
+                "summary": "Get User Detail",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Pemilik permission user:update bebas edit. User biasa hanya edit diri sendiri.",
+                "tags": [
+                    "Users (Admin)"
+                ],
+                "summary": "Update User Data (Nama/Email)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data Update",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.UpdateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Butuh permission user:delete.",
+                "tags": [
+                    "Users (Admin)"
+                ],
+                "summary": "Soft Delete User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}/role": {
+            "put": {
+                "description": "Butuh permission user:assign_role.",
+                "tags": [
+                    "Users (Admin)"
+                ],
+                "summary": "Change User Role (Promote/Demote)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID Target",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Role ID Baru",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.UpdateRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        }
+    },
+    "definitions": {
+        "dto.AchievementListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AchievementResponse"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/dto.PageMeta"
+                },
+                "missing": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.MissingContent"
+                    }
+                }
+            }
+        },
+        "dto.AchievementResponse": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "$ref": "#/definitions/mongodb.Achievement"
+                },
+                "duplicates": {
+                    "description": "Duplicates: kemungkinan duplikat, hanya untuk dosen/admin",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/postgres.DuplicateFlag"
+                    }
+                },
+                "members": {
+                    "description": "Members hanya diisi pada detail prestasi",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/postgres.AchievementMember"
+                    }
+                },
+                "ref_id": {
+                    "type": "string"
+                },
+                "rejection_note": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.MissingContent": {
+            "type": "object",
+            "properties": {
+                "mongo_achievement_id": {
+                    "type": "string"
+                },
+                "ref_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PageMeta": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "mongodb.Achievement": {
+            "type": "object",
+            "properties": {
+                "achievementType": {
+                    "type": "string"
+                },
+                "attachments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/mongodb.Attachment"
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deleted": {
+                    "type": "boolean"
+                },
+                "deletedAt": {
+                    "type": "string"
+                },
+                "deletedBy": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "details": {
+                    "$ref": "#/definitions/mongodb.AchievementDetails"
+                },
+                "eventId": {
+                    "description": "EventID menautkan prestasi ke katalog event (tabel events di Postgres); kosong = tidak tertaut",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "integer"
+                },
+                "pointsBreakdown": {
+                    "$ref": "#/definitions/mongodb.PointsBreakdown"
+                },
+                "studentId": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version sama dengan nomor revisi konten terakhir; dokumen lama tanpa field ini dianggap versi 1.",
+                    "type": "integer"
+                }
+            }
+        },
+        "mongodb.AchievementDetails": {
+            "type": "object",
+            "additionalProperties": true
+        },
+        "mongodb.AchievementRevision": {
+            "type": "object",
+            "properties": {
+                "achievementId": {
+                    "description": "hex _id dokumen achievement",
+                    "type": "string"
+                },
+                "achievementType": {
+                    "type": "string"
+                },
+                "changedBy": {
+                    "type": "string"
+                },
+                "changedFields": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "details": {
+                    "$ref": "#/definitions/mongodb.AchievementDetails"
+                },
+                "eventId": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "revision": {
+                    "type": "integer"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "mongodb.Attachment": {
+            "type": "object",
+            "properties": {
+                "fileName": {
+                    "type": "string"
+                },
+                "fileType": {
+                    "type": "string"
+                },
+                "fileUrl": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "preview": {
+                    "$ref": "#/definitions/mongodb.AttachmentPreview"
+                },
+                "sha256": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "uploadedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "mongodb.AttachmentPreview": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "generatedAt": {
+                    "type": "string"
+                },
+                "height": {
+                    "type": "integer"
+                },
+                "pageCount": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "thumbnailUrl": {
+                    "type": "string"
+                },
+                "width": {
+                    "type": "integer"
+                }
+            }
+        },
+        "mongodb.PointsBreakdown": {
+            "type": "object",
+            "properties": {
+                "achievementType": {
+                    "type": "string"
+                },
+                "calculatedAt": {
+                    "type": "string"
+                },
+                "level": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "integer"
+                },
+                "rank": {
+                    "type": "string"
+                },
+                "ruleId": {
+                    "type": "string"
+                }
+            }
+        },
+        "postgres.AchievementComment": {
+            "type": "object",
+            "properties": {
+                "achievement_id": {
+                    "type": "string"
+                },
+                "anchor_attachment_id": {
+                    "type": "string"
+                },
+                "anchor_field": {
+                    "type": "string"
+                },
+                "author_id": {
+                    "type": "string"
+                },
+                "author_name": {
+                    "type": "string"
+                },
+                "author_role_id": {
+                    "type": "string"
+                },
+                "body": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "replies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/postgres.AchievementComment"
+                    }
+                }
+            }
+        },
+        "postgres.AchievementMember": {
+            "type": "object",
+            "properties": {
+                "advisor_id": {
+                    "description": "user id dosen wali",
+                    "type": "string"
+                },
+                "advisor_name": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "nim": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "student_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "postgres.AdvisorApproval": {
+            "type": "object",
+            "properties": {
+                "advisor_id": {
+                    "type": "string"
+                },
+                "advisor_name": {
+                    "type": "string"
+                },
+                "approved": {
+                    "type": "boolean"
+                },
+                "approved_at": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                }
+            }
+        },
+        "postgres.AttestationClaims": {
+            "type": "object",
+            "properties": {
+                "achievement_id": {
+                    "type": "string"
+                },
+                "content_sha256": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kid": {
+                    "type": "string"
+                },
+                "student": {
+                    "$ref": "#/definitions/postgres.AttestationParty"
+                },
+                "team": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/postgres.AttestationParty"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "v": {
+                    "type": "integer"
+                },
+                "verified_at": {
+                    "type": "string"
+                },
+                "verifier": {
+                    "$ref": "#/definitions/postgres.AttestationParty"
+                }
+            }
+        },
+        "postgres.AttestationParty": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "nim": {
+                    "type": "string"
+                }
+            }
+        },
+        "postgres.DuplicateFlag": {
+            "type": "object",
+            "properties": {
+                "achievement_id": {
+                    "type": "string"
+                },
+                "decided_at": {
+                    "type": "string"
+                },
+                "decided_by": {
+                    "type": "string"
+                },
+                "decision": {
+                    "type": "string"
+                },
+                "detected_at": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "score": {
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "student_id": {
+                    "type": "string"
+                },
+                "student_name": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "postgres.Event": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "level": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "nationally_recognized": {
+                    "type": "boolean"
+                },
+                "organizer": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "postgres.Portfolio": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "headline": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "published": {
+                    "type": "boolean"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "student_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "postgres.PortfolioItem": {
+            "type": "object",
+            "properties": {
+                "achievement_id": {
+                    "type": "string"
+                },
+                "show_attachments": {
+                    "type": "boolean"
+                },
+                "show_private_details": {
+                    "description": "ShowPrivateDetails ikut menampilkan field details bertanda private (mis. nomor sertifikat)",
+                    "type": "boolean"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "visible": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "postgres.SkpiDocument": {
+            "type": "object",
+            "properties": {
+                "achievement_count": {
+                    "type": "integer"
+                },
+                "document_number": {
+                    "type": "string"
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "generated_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "mahasiswa_id": {
+                    "type": "string"
+                },
+                "sha256": {
+                    "type": "string"
+                },
+                "signatory_name": {
+                    "type": "string"
+                },
+                "signatory_nip": {
+                    "type": "string"
+                },
+                "signatory_title": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "total_points": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "repository.PortfolioOwner": {
+            "type": "object",
+            "properties": {
+                "academic_year": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "program_study": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.AssignPermissionRequest": {
+            "type": "object",
+            "required": [
+                "permission_id"
+            ],
+            "properties": {
+                "permission_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.AttestationResponse": {
+            "type": "object",
+            "properties": {
+                "achievement_id": {
+                    "type": "string"
+                },
+                "claims": {
+                    "$ref": "#/definitions/postgres.AttestationClaims"
+                },
+                "content_sha256": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "issued_at": {
+                    "type": "string"
+                },
+                "key_id": {
+                    "type": "string"
+                },
+                "qr_payload": {
+                    "type": "string"
+                },
+                "revocation_reason": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "revoked_by": {
+                    "type": "string"
+                },
+                "signature": {
+                    "type": "string"
+                },
+                "student_id": {
+                    "type": "string"
+                },
+                "verifier_id": {
+                    "type": "string"
+                },
+                "verify_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.AttestationVerification": {
+            "type": "object",
+            "properties": {
+                "claims": {
+                    "$ref": "#/definitions/postgres.AttestationClaims"
+                },
+                "content_matches": {
+                    "type": "boolean"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "registered": {
+                    "type": "boolean"
+                },
+                "revocation_reason": {
+                    "type": "string"
+                },
+                "revoked": {
+                    "type": "boolean"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "signature_valid": {
+                    "type": "boolean"
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "service.BulkDecisionItem": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "b3f1c2d4-..."
+                },
+                "notes": {
+                    "type": "string",
+                    "example": "Sertifikat tidak terbaca"
+                }
+            }
+        },
+        "service.BulkDecisionRequest": {
+            "type": "object",
+            "required": [
+                "items",
+                "status"
+            ],
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/service.BulkDecisionItem"
+                    }
+                },
+                "notes": {
+                    "description": "Notes dipakai untuk item yang tidak membawa notes sendiri",
+                    "type": "string",
+                    "example": "Oke bagus"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "VERIFIED",
+                        "REJECTED"
+                    ],
+                    "example": "VERIFIED"
+                }
+            }
+        },
+        "service.BulkDecisionResponse": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.BulkDecisionResult"
+                    }
+                },
+                "succeeded": {
+                    "type": "integer"
+                }
+            }
+        },
+        "service.BulkDecisionResult": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "pending_advisors": {
+                    "description": "PendingAdvisors: jumlah dosen wali anggota tim yang belum menyetujui (mode per_advisor)",
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "service.CreateCommentRequest": {
+            "type": "object",
+            "required": [
+                "body"
+            ],
+            "properties": {
+                "anchor_attachment_id": {
+                    "description": "AnchorAttachmentID mengaitkan komentar ke satu lampiran",
+                    "type": "string"
+                },
+                "anchor_field": {
+                    "description": "AnchorField mengaitkan komentar ke field konten: title, description, achievementType, tags, eventId, atau details.\u003cnama\u003e",
+                    "type": "string",
+                    "example": "details.rank"
+                },
+                "body": {
+                    "type": "string",
+                    "example": "Mohon lampirkan sertifikat yang terbaca"
+                },
+                "parent_id": {
+                    "type": "string",
+                    "example": ""
+                }
+            }
+        },
+        "service.CreateUserRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "full_name",
+                "password",
+                "role_id",
+                "username"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "mhs_baru@unair.ac.id"
+                },
+                "full_name": {
+                    "type": "string",
+                    "example": "Budi Santoso"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6,
+                    "example": "rahasia123"
+                },
+                "role_id": {
+                    "type": "string",
+                    "example": "22222222-2222-2222-2222-222222222222"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "mahasiswa_baru"
+                }
+            }
+        },
+        "service.DetailField": {
+            "type": "object",
+            "properties": {
+                "label": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "pattern": {
+                    "type": "string"
+                },
+                "private": {
+                    "description": "Private: tidak ditampilkan di portofolio publik kecuali dibagikan eksplisit",
+                    "type": "boolean"
+                },
+                "required": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.DetailSchema": {
+            "type": "object",
+            "properties": {
+                "fields": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.DetailField"
+                    }
+                },
+                "label": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.DuplicateDecisionRequest": {
+            "type": "object",
+            "required": [
+                "decision"
+            ],
+            "properties": {
+                "decision": {
+                    "type": "string",
+                    "enum": [
+                        "confirmed",
+                        "dismissed",
+                        "suspected"
+                    ],
+                    "example": "dismissed"
+                },
+                "notes": {
+                    "type": "string",
+                    "example": "Sertifikat berbeda, kegiatan berulang tiap tahun"
+                }
+            }
+        },
+        "service.EventRequest": {
+            "type": "object",
+            "required": [
+                "level",
+                "name",
+                "organizer",
+                "year"
+            ],
+            "properties": {
+                "level": {
+                    "type": "string",
+                    "example": "national"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Gemastik XVII"
+                },
+                "nationally_recognized": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "organizer": {
+                    "type": "string",
+                    "example": "Puspresnas"
+                },
+                "year": {
+                    "type": "integer",
+                    "maximum": 2100,
+                    "minimum": 1900,
+                    "example": 2024
+                }
+            }
+        },
+        "service.FieldChange": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "from": {},
+                "to": {}
+            }
+        },
+        "service.GenerateSkpiRequest": {
+            "type": "object",
+            "properties": {
+                "signatory_name": {
+                    "type": "string",
+                    "example": "Dr. Budi Santoso, M.Kom."
+                },
+                "signatory_nip": {
+                    "type": "string",
+                    "example": "197001012000031001"
+                },
+                "signatory_title": {
+                    "type": "string",
+                    "example": "Wakil Rektor Bidang Kemahasiswaan"
+                },
+                "signatory_title_en": {
+                    "type": "string",
+                    "example": "Vice Rector for Student Affairs"
+                }
+            }
+        },
+        "service.LoginRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "password123"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "mahasiswa123"
+                }
+            }
+        },
+        "service.LogoutRequest": {
+            "type": "object",
+            "properties": {
+                "refreshToken": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.PermissionRequest": {
+            "type": "object",
+            "required": [
+                "action",
+                "resource"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "export"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Export laporan prestasi"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "report:export"
+                },
+                "resource": {
+                    "type": "string",
+                    "example": "report"
+                }
+            }
+        },
+        "service.PointRuleRequest": {
+            "type": "object",
+            "required": [
+                "achievement_type"
+            ],
+            "properties": {
+                "achievement_type": {
+                    "type": "string",
+                    "example": "competition"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Juara 1 kompetisi nasional"
+                },
+                "level": {
+                    "type": "string",
+                    "example": "national"
+                },
+                "points": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 50
+                },
+                "rank": {
+                    "type": "string",
+                    "example": "juara_1"
+                }
+            }
+        },
+        "service.PortfolioChoice": {
+            "type": "object",
+            "properties": {
+                "achievement_id": {
                     "type": "string"
                 },
-                "rejection_note": {
+                "achievement_type": {
                     "type": "string"
                 },
-                "status": {
+                "attachment_count": {
+                    "type": "integer"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "integer"
+                },
+                "show_attachments": {
+                    "type": "boolean"
+                },
+                "show_private_details": {
+                    "description": "ShowPrivateDetails: field details pribadi (mis. nomor sertifikat) ikut dibagikan",
+                    "type": "boolean"
+                },
+                "title": {
                     "type": "string"
+                },
+                "visible": {
+                    "type": "boolean"
                 }
             }
         },
-        "mongodb.Achievement": {
+        "service.PortfolioSettings": {
             "type": "object",
             "properties": {
-                "achievementType": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.PortfolioChoice"
+                    }
+                },
+                "portfolio": {
+                    "$ref": "#/definitions/postgres.Portfolio"
+                },
+                "public_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.PublicAchievement": {
+            "type": "object",
+            "properties": {
+                "achievement_type": {
                     "type": "string"
                 },
                 "attachments": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/mongodb.Attachment"
+                        "$ref": "#/definitions/service.PublicAttachment"
                     }
                 },
-                "createdAt": {
+                "date": {
                     "type": "string"
                 },
-                "deleted": {
-                    "type": "boolean"
-                },
                 "description": {
                     "type": "string"
                 },
                 "details": {
-                    "$ref": "#/definitions/mongodb.AchievementDetails"
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.PublicDetail"
+                    }
                 },
                 "id": {
                     "type": "string"
@@ -950,9 +4286,6 @@ const docTemplate = `{
                 "points": {
                     "type": "integer"
                 },
-                "studentId": {
-                    "type": "string"
-                },
                 "tags": {
                     "type": "array",
                     "items": {
@@ -962,107 +4295,273 @@ const docTemplate = `{
                 "title": {
                     "type": "string"
                 },
-                "updatedAt": {
+                "type_label": {
                     "type": "string"
                 }
             }
         },
-        "mongodb.AchievementDetails": {
+        "service.PublicAttachment": {
             "type": "object",
-            "additionalProperties": true
+            "properties": {
+                "file_name": {
+                    "type": "string"
+                },
+                "file_type": {
+                    "type": "string"
+                },
+                "thumbnail_url": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
         },
-        "mongodb.Attachment": {
+        "service.PublicDetail": {
             "type": "object",
             "properties": {
-                "fileName": {
+                "label": {
                     "type": "string"
                 },
-                "fileType": {
+                "value": {
                     "type": "string"
+                }
+            }
+        },
+        "service.PublicPortfolio": {
+            "type": "object",
+            "properties": {
+                "achievements": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.PublicAchievement"
+                    }
                 },
-                "fileUrl": {
+                "headline": {
                     "type": "string"
                 },
-                "uploadedAt": {
+                "owner": {
+                    "$ref": "#/definitions/repository.PortfolioOwner"
+                },
+                "total_points": {
+                    "type": "integer"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "service.CreateAchievementRequest": {
+        "service.PurgeReport": {
             "type": "object",
-            "required": [
-                "achievement_type",
-                "description",
-                "title"
-            ],
             "properties": {
-                "achievement_type": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "finished_at": {
                     "type": "string"
                 },
-                "description": {
+                "purged": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "started_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.RecalculateResult": {
+            "type": "object",
+            "properties": {
+                "checked": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "updated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "service.ReconcileReport": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "finished_at": {
                     "type": "string"
                 },
-                "points": {
+                "flagged_refs": {
+                    "description": "FlaggedRefs adalah jumlah referensi yatim yang baru ditandai orphaned_at",
                     "type": "integer"
                 },
-                "tags": {
+                "orphan_mongo": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "title": {
+                "orphan_refs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "repaired_docs": {
+                    "type": "integer"
+                },
+                "started_at": {
                     "type": "string"
+                },
+                "synced_summaries": {
+                    "description": "SyncedSummaries adalah jumlah ringkasan daftar prestasi yang disalin ulang dari Mongo",
+                    "type": "integer"
                 }
             }
         },
-        "service.CreateUserRequest": {
+        "service.RefreshRequest": {
             "type": "object",
             "required": [
-                "email",
-                "full_name",
-                "password",
-                "role_id",
-                "username"
+                "refreshToken"
             ],
             "properties": {
-                "email": {
+                "refreshToken": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.RejectRequest": {
+            "type": "object",
+            "required": [
+                "notes"
+            ],
+            "properties": {
+                "notes": {
                     "type": "string",
-                    "example": "mhs_baru@unair.ac.id"
+                    "example": "Sertifikat tidak terbaca"
+                }
+            }
+        },
+        "service.RevisionDiff": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.FieldChange"
+                    }
                 },
-                "full_name": {
-                    "type": "string",
-                    "example": "Budi Santoso"
+                "from": {
+                    "type": "integer"
                 },
-                "password": {
+                "to": {
+                    "type": "integer"
+                }
+            }
+        },
+        "service.RevokeAttestationRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
                     "type": "string",
-                    "minLength": 6,
-                    "example": "rahasia123"
-                },
-                "role_id": {
+                    "example": "Sertifikat terbukti palsu"
+                }
+            }
+        },
+        "service.RoleRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
                     "type": "string",
-                    "example": "22222222-2222-2222-2222-222222222222"
+                    "example": "Ketua Program Studi"
                 },
-                "username": {
+                "name": {
                     "type": "string",
-                    "example": "mahasiswa_baru"
+                    "example": "kaprodi"
                 }
             }
         },
-        "service.LoginRequest": {
+        "service.TeamMemberInput": {
             "type": "object",
             "required": [
-                "password",
-                "username"
+                "student_id"
             ],
             "properties": {
-                "password": {
+                "role": {
                     "type": "string",
-                    "example": "password123"
+                    "example": "anggota"
                 },
-                "username": {
+                "student_id": {
                     "type": "string",
-                    "example": "mahasiswa123"
+                    "example": "user id mahasiswa"
+                }
+            }
+        },
+        "service.TeamResponse": {
+            "type": "object",
+            "properties": {
+                "approvals": {
+                    "description": "Approvals hanya terisi saat PENDING pada mode per_advisor",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/postgres.AdvisorApproval"
+                    }
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/postgres.AchievementMember"
+                    }
+                },
+                "verification": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.UpdateAchievementRequest": {
+            "type": "object",
+            "required": [
+                "achievement_type",
+                "description",
+                "title"
+            ],
+            "properties": {
+                "achievement_type": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
                 }
             }
         },
@@ -1077,6 +4576,48 @@ const docTemplate = `{
                 }
             }
         },
+        "service.UpdateMembersRequest": {
+            "type": "object",
+            "properties": {
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.TeamMemberInput"
+                    }
+                }
+            }
+        },
+        "service.UpdatePortfolioItemRequest": {
+            "type": "object",
+            "properties": {
+                "show_attachments": {
+                    "type": "boolean"
+                },
+                "show_private_details": {
+                    "description": "ShowPrivateDetails ikut menampilkan field pribadi seperti nomor sertifikat",
+                    "type": "boolean"
+                },
+                "visible": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "service.UpdatePortfolioRequest": {
+            "type": "object",
+            "required": [
+                "published"
+            ],
+            "properties": {
+                "headline": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "Mahasiswa Teknik Informatika, minat keamanan siber"
+                },
+                "published": {
+                    "type": "boolean"
+                }
+            }
+        },
         "service.UpdateRoleRequest": {
             "type": "object",
             "required": [
@@ -1118,6 +4659,10 @@ const docTemplate = `{
                 },
                 "status": {
                     "type": "string",
+                    "enum": [
+                        "VERIFIED",
+                        "REJECTED"
+                    ],
                     "example": "VERIFIED"
                 }
             }
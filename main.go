@@ -4,9 +4,17 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
+	"pelaporan_prestasi/app/attest"
 	"pelaporan_prestasi/app/repository"
+	"pelaporan_prestasi/app/scanner"
+	"pelaporan_prestasi/app/storage"
 	"pelaporan_prestasi/database"
 	"pelaporan_prestasi/middleware"
 
@@ -34,6 +42,20 @@ func main() {
 		log.Println("Warning: .env file not found")
 	}
 
+	// Kunci atestasi dibuat eksplisit sekali, lalu dibackup; server menolak start tanpa kunci
+	if len(os.Args) > 1 && os.Args[1] == "attestation-keygen" {
+		signer, err := attest.Generate(attestationKeyFile())
+		if err != nil {
+			log.Fatal("Gagal membuat kunci atestasi:", err)
+		}
+		log.Printf("🔑 Kunci atestasi dibuat di %s (key id %s); simpan dan backup file kuncinya", attestationKeyFile(), signer.KeyID)
+		return
+	}
+
+	// appCtx selesai saat SIGINT/SIGTERM; semua worker background berhenti mengikutinya
+	appCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	dsn := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
 		os.Getenv("POSTGRES_USER"),
 		os.Getenv("POSTGRES_PASSWORD"),
@@ -54,26 +76,93 @@ func main() {
 	}
 	fmt.Println("✅ MongoDB Connected!")
 
+	roleRepo := repository.NewRoleRepository(pgPool)
+	perms := middleware.NewPermissionCache(roleRepo, envDuration("RBAC_CACHE_TTL", 5*time.Minute))
+	roleService := service.NewRoleService(roleRepo, perms)
+
 	userRepo := repository.NewUserRepository(pgPool)
-	userService := service.NewUserService(userRepo)
-	authService := service.NewAuthService(userRepo, os.Getenv("JWT_SECRET"))
+	userService := service.NewUserService(userRepo, perms)
+	tokenRepo := repository.NewRefreshTokenRepository(pgPool)
+	authService := service.NewAuthService(userRepo, tokenRepo, os.Getenv("JWT_SECRET"))
+
+	fileStore, err := storage.NewFromEnv(context.Background())
+	if err != nil {
+		log.Fatal("Gagal inisialisasi storage:", err)
+	}
+	fileScanner, err := scanner.NewFromEnv()
+	if err != nil {
+		log.Fatal("Gagal inisialisasi scanner:", err)
+	}
+	files := service.NewAttachmentFiles(fileStore, fileScanner, envDuration("FILE_URL_TTL", 15*time.Minute), service.UploadLimits{
+		MaxFileSize:  envMegabytes("UPLOAD_MAX_FILE_MB", 5),
+		MaxTotalSize: envMegabytes("UPLOAD_MAX_ACHIEVEMENT_MB", 20),
+	})
 
 	achRepo := repository.NewAchievementRepository(pgPool, mongoDB)
-	achService := service.NewAchievementService(achRepo)
+	revisionRepo := repository.NewRevisionRepository(mongoDB)
+	achWriter := service.NewAchievementWriter(achRepo, revisionRepo, files)
+	eventRepo := repository.NewEventRepository(pgPool)
+	eventService := service.NewEventService(eventRepo, achRepo, perms)
+	pointRuleRepo := repository.NewPointRuleRepository(pgPool)
+	pointService := service.NewPointService(pointRuleRepo, achRepo, eventRepo)
+	go pointService.Start(appCtx)
+	previewService := service.NewPreviewService(achRepo, files, envString("PDF_RENDERER", "pdftoppm"))
+	previewService.Start(appCtx, envInt("PREVIEW_WORKERS", 2), envDuration("PREVIEW_SWEEP_INTERVAL", 10*time.Minute))
+	commentRepo := repository.NewCommentRepository(pgPool)
+	teamVerification := envString("TEAM_VERIFICATION", service.TeamVerificationPerAdvisor)
+	if err := service.CheckTeamVerification(teamVerification); err != nil {
+		log.Fatal("Konfigurasi tidak valid:", err)
+	}
+	signer, err := attest.Load(attestationKeyFile())
+	if err != nil {
+		log.Fatal("Gagal memuat kunci atestasi (buat dengan `go run . attestation-keygen`):", err)
+	}
+	attestationService := service.NewAttestationService(repository.NewAttestationRepository(pgPool), achRepo, signer, os.Getenv("PUBLIC_BASE_URL"))
+	if err := attestationService.RegisterKey(context.Background()); err != nil {
+		log.Fatal("Gagal mendaftarkan kunci atestasi:", err)
+	}
+	taskService := service.NewVerificationTaskService(repository.NewVerificationTaskRepository(pgPool), achRepo, pointService, attestationService)
+	go taskService.Start(appCtx, envDuration("VERIFICATION_TASK_INTERVAL", time.Minute))
+	achService := service.NewAchievementService(achRepo, achWriter, taskService, files, previewService, commentRepo, eventRepo, attestationService, teamVerification, perms)
+
+	reconcileService := service.NewReconcileService(achRepo, files, envDuration("RECONCILE_GRACE", 15*time.Minute))
+	go reconcileService.Start(appCtx, envDuration("RECONCILE_INTERVAL", time.Hour))
+
+	trashService := service.NewTrashService(achRepo, achWriter, envDuration("TRASH_RETENTION", 30*24*time.Hour), perms)
+	go trashService.Start(appCtx, envDuration("TRASH_PURGE_INTERVAL", 6*time.Hour))
 
 	mhsRepo := repository.NewMahasiswaRepository(pgPool)
-	mhsService := service.NewMahasiswaService(mhsRepo, achRepo)
+	mhsService := service.NewMahasiswaService(mhsRepo, achRepo, files, perms)
 
 	dosenRepo := repository.NewDosenRepository(pgPool)
-	dosenService := service.NewDosenService(dosenRepo)
+	dosenService := service.NewDosenService(dosenRepo, perms)
 
 	reportRepo := repository.NewReportRepository(pgPool)
-	reportService := service.NewReportService(reportRepo, mhsRepo, achRepo)
+	reportService := service.NewReportService(reportRepo, mhsRepo, achRepo, files, perms)
+	skpiService := service.NewSkpiService(repository.NewSkpiRepository(pgPool), mhsRepo, achRepo, fileStore, service.SkpiConfig{
+		InstitutionName:    envString("SKPI_INSTITUTION_NAME", "Universitas"),
+		InstitutionNameEN:  os.Getenv("SKPI_INSTITUTION_NAME_EN"),
+		InstitutionAddress: os.Getenv("SKPI_INSTITUTION_ADDRESS"),
+		City:               os.Getenv("SKPI_CITY"),
+		NumberPrefix:       envString("SKPI_NUMBER_PREFIX", "SKPI"),
+		SignatoryName:      os.Getenv("SKPI_SIGNATORY_NAME"),
+		SignatoryTitle:     os.Getenv("SKPI_SIGNATORY_TITLE"),
+		SignatoryTitleEN:   os.Getenv("SKPI_SIGNATORY_TITLE_EN"),
+		SignatoryNIP:       os.Getenv("SKPI_SIGNATORY_NIP"),
+	}, perms)
+	badgeService := service.NewBadgeService(repository.NewBadgeRepository(pgPool), achRepo, attestationService, service.BadgeIssuer{
+		Name:        envString("BADGE_ISSUER_NAME", envString("SKPI_INSTITUTION_NAME", "Universitas")),
+		URL:         envString("BADGE_ISSUER_URL", os.Getenv("PUBLIC_BASE_URL")),
+		Email:       os.Getenv("BADGE_ISSUER_EMAIL"),
+		Description: os.Getenv("BADGE_ISSUER_DESCRIPTION"),
+		Image:       os.Getenv("BADGE_ISSUER_IMAGE"),
+	}, os.Getenv("PUBLIC_BASE_URL"))
+	portfolioService := service.NewPortfolioService(repository.NewPortfolioRepository(pgPool), achRepo, files, os.Getenv("PUBLIC_BASE_URL"))
 
 	r := gin.Default()
 	r.Use(middleware.CORSMiddleware())
 
-	route.SetupRouter(r, authService, userService, achService, mhsService, dosenService, reportService)
+	route.SetupRouter(r, perms, authService, userService, roleService, achService, mhsService, dosenService, reportService, reconcileService, pointService, files, trashService, eventService, skpiService, attestationService, badgeService, portfolioService)
 
 	port := os.Getenv("APP_PORT")
 	if port == "" {
@@ -84,9 +173,54 @@ func main() {
 	log.Println("\033[32m✅  SEMUA DATABASE TERHUBUNG!\033[0m")
 	log.Println("\033[32m🚀  SERVER SIAP DI PORT :" + port + "\033[0m")
 	log.Println("\033[32m👉  Buka Swagger: http://localhost:" + port + "/swagger/index.html\033[0m")
-	log.Println("\033[32m=================================================\033[0m\n")
+	log.Println("\033[32m=================================================\033[0m")
+
+	srv := &http.Server{Addr: ":" + port, Handler: r}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+
+	<-appCtx.Done()
+	log.Println("🛑 Server berhenti...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("⚠️ Shutdown tidak bersih: %v", err)
+	}
+}
+
+func attestationKeyFile() string {
+	return envString("ATTESTATION_KEY_FILE", "keys/attestation.ed25519")
+}
+
+// envDuration membaca durasi dari env (mis. "5m", "1h"), pakai fallback jika kosong/tidak valid.
+func envDuration(key string, fallback time.Duration) time.Duration {
+	if d, err := time.ParseDuration(os.Getenv(key)); err == nil && d > 0 {
+		return d
+	}
+	return fallback
+}
+
+// envMegabytes membaca ukuran dalam MB dari env dan mengembalikannya dalam byte.
+func envMegabytes(key string, fallback int64) int64 {
+	if mb, err := strconv.ParseInt(os.Getenv(key), 10, 64); err == nil && mb > 0 {
+		return mb << 20
+	}
+	return fallback << 20
+}
+
+func envInt(key string, fallback int) int {
+	if n, err := strconv.Atoi(os.Getenv(key)); err == nil && n > 0 {
+		return n
+	}
+	return fallback
+}
 
-	if err := r.Run(":" + port); err != nil {
-		log.Fatal(err)
+func envString(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
 	}
+	return fallback
 }
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"os"
@@ -10,7 +11,12 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+// AccessTokenDenylist dipakai AuthMiddleware untuk menolak access token yang sudah di-logout.
+type AccessTokenDenylist interface {
+	IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+func AuthMiddleware(denylist AccessTokenDenylist) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -35,14 +41,37 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-			userID := claims["user_id"].(string)
-			roleID := claims["role_id"].(string)
+		claims, ok := token.Claims.(jwt.MapClaims)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			c.Abort()
+			return
+		}
 
-			c.Set("user_id", userID)
-			c.Set("role_id", roleID)
+		// Hanya access token yang boleh dipakai; token tanpa type (atau refresh token) ditolak
+		userID, _ := claims["user_id"].(string)
+		roleID, _ := claims["role_id"].(string)
+		jti, _ := claims["jti"].(string)
+		if claims["type"] != "access" || userID == "" || roleID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			c.Abort()
+			return
 		}
+		revoked, err := denylist.IsAccessTokenRevoked(c.Request.Context(), jti)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa token"})
+			c.Abort()
+			return
+		}
+		if revoked {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			c.Abort()
+			return
+		}
+
+		c.Set("user_id", userID)
+		c.Set("role_id", roleID)
 
 		c.Next()
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"pelaporan_prestasi/app/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+type rolePermissions struct {
+	perms    map[string]bool
+	loadedAt time.Time
+}
+
+// PermissionCache menyimpan permission per role di memori.
+// Entry kadaluarsa setelah TTL, atau langsung lewat Invalidate saat admin mengubah role.
+type PermissionCache struct {
+	Repo *repository.RoleRepository
+	TTL  time.Duration
+
+	mu    sync.RWMutex
+	roles map[string]rolePermissions
+}
+
+func NewPermissionCache(repo *repository.RoleRepository, ttl time.Duration) *PermissionCache {
+	return &PermissionCache{Repo: repo, TTL: ttl, roles: make(map[string]rolePermissions)}
+}
+
+func permissionKey(resource, action string) string {
+	return resource + ":" + action
+}
+
+func (p *PermissionCache) load(ctx context.Context, roleID string) (map[string]bool, error) {
+	p.mu.RLock()
+	entry, ok := p.roles[roleID]
+	p.mu.RUnlock()
+	if ok && time.Since(entry.loadedAt) < p.TTL {
+		return entry.perms, nil
+	}
+
+	list, err := p.Repo.FindPermissionsByRoleID(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+	perms := make(map[string]bool, len(list))
+	for _, perm := range list {
+		perms[permissionKey(perm.Resource, perm.Action)] = true
+	}
+
+	p.mu.Lock()
+	p.roles[roleID] = rolePermissions{perms: perms, loadedAt: time.Now()}
+	p.mu.Unlock()
+	return perms, nil
+}
+
+func (p *PermissionCache) Has(ctx context.Context, roleID, resource, action string) (bool, error) {
+	perms, err := p.load(ctx, roleID)
+	if err != nil {
+		return false, err
+	}
+	return perms[permissionKey(resource, action)], nil
+}
+
+// Can mengecek permission role user yang sedang login. Error DB dianggap tidak punya akses.
+func (p *PermissionCache) Can(c *gin.Context, resource, action string) bool {
+	ok, err := p.Has(c.Request.Context(), c.GetString("role_id"), resource, action)
+	return err == nil && ok
+}
+
+func (p *PermissionCache) Invalidate(roleID string) {
+	p.mu.Lock()
+	delete(p.roles, roleID)
+	p.mu.Unlock()
+}
+
+func (p *PermissionCache) InvalidateAll() {
+	p.mu.Lock()
+	p.roles = make(map[string]rolePermissions)
+	p.mu.Unlock()
+}
+
+// RequirePermission harus dipasang setelah AuthMiddleware karena membaca role_id dari context.
+func (p *PermissionCache) RequirePermission(resource, action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ok, err := p.Has(c.Request.Context(), c.GetString("role_id"), resource, action)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memuat permission"})
+			c.Abort()
+			return
+		}
+		if !ok {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Akses Ditolak! Butuh permission " + permissionKey(resource, action)})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -9,12 +9,17 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func SetupRouter(r *gin.Engine, authService *service.AuthService, userService *service.UserService, achService *service.AchievementService, mhsService *service.MahasiswaService, dosenService *service.DosenService, reportService *service.ReportService) {
+func SetupRouter(r *gin.Engine, perms *middleware.PermissionCache, authService *service.AuthService, userService *service.UserService, roleService *service.RoleService, achService *service.AchievementService, mhsService *service.MahasiswaService, dosenService *service.DosenService, reportService *service.ReportService, reconcileService *service.ReconcileService, pointService *service.PointService, files *service.AttachmentFiles, trashService *service.TrashService, eventService *service.EventService, skpiService *service.SkpiService, attestationService *service.AttestationService, badgeService *service.BadgeService, portfolioService *service.PortfolioService) {
 
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	// Halaman portofolio publik (HTML); slug acak yang bisa dicabut mahasiswa
+	r.GET("/p/:slug", portfolioService.RenderPublicPortfolio)
 
 	api := r.Group("/api/v1")
 	{
+		// Publik: akses dijaga tanda tangan + masa berlaku di query string
+		api.GET("/files/*key", files.Download)
+
 		auth := api.Group("/auth")
 		{
 			auth.POST("/login", authService.Login)
@@ -24,54 +29,161 @@ func SetupRouter(r *gin.Engine, authService *service.AuthService, userService *s
 		}
 
 		users := api.Group("/users")
-		users.Use(middleware.AuthMiddleware())
+		users.Use(middleware.AuthMiddleware(authService))
 		{
-			users.GET("", userService.GetAllUsers)
+			users.GET("", perms.RequirePermission("user", "read"), userService.GetAllUsers)
 			users.GET("/:id", userService.GetUserByID)
-			users.POST("", userService.CreateUser)
+			users.POST("", perms.RequirePermission("user", "create"), userService.CreateUser)
 			users.PUT("/:id", userService.UpdateUser)
-			users.PUT("/:id/role", userService.UpdateRole)
-			users.DELETE("/:id", userService.DeleteUser)
+			users.PUT("/:id/role", perms.RequirePermission("user", "assign_role"), userService.UpdateRole)
+			users.DELETE("/:id", perms.RequirePermission("user", "delete"), userService.DeleteUser)
+		}
+
+		roles := api.Group("/roles")
+		roles.Use(middleware.AuthMiddleware(authService), perms.RequirePermission("role", "manage"))
+		{
+			roles.GET("", roleService.GetRoles)
+			roles.POST("", roleService.CreateRole)
+			roles.PUT("/:id", roleService.UpdateRole)
+			roles.DELETE("/:id", roleService.DeleteRole)
+			roles.GET("/:id/permissions", roleService.GetRolePermissions)
+			roles.POST("/:id/permissions", roleService.AssignPermission)
+			roles.DELETE("/:id/permissions/:permissionId", roleService.RevokePermission)
+		}
+
+		permissions := api.Group("/permissions")
+		permissions.Use(middleware.AuthMiddleware(authService), perms.RequirePermission("role", "manage"))
+		{
+			permissions.GET("", roleService.GetPermissions)
+			permissions.POST("", roleService.CreatePermission)
+			permissions.DELETE("/:id", roleService.DeletePermission)
 		}
 
 		ach := api.Group("/achievements")
-		ach.Use(middleware.AuthMiddleware())
+		ach.Use(middleware.AuthMiddleware(authService))
 		{
-			ach.GET("", achService.GetList)       
-			ach.GET("/:id", achService.GetDetail) 
-			ach.POST("", achService.Create)      
-			ach.PUT("/:id", achService.Update)    
-			ach.DELETE("/:id", achService.Delete) 
-
-			ach.POST("/:id/submit", achService.Submit) 
-			ach.POST("/:id/verify", achService.Verify) 
-			ach.POST("/:id/reject", achService.Reject) 
-
-			ach.GET("/:id/history", achService.GetHistory)            
-			ach.POST("/:id/attachments", achService.UploadAttachment) 
+			read := perms.RequirePermission("achievement", "read")
+			update := perms.RequirePermission("achievement", "update")
+			verify := perms.RequirePermission("achievement", "verify")
+
+			ach.GET("", read, achService.GetList)
+			ach.GET("/trash", perms.RequirePermission("achievement", "delete"), trashService.GetTrash)
+			ach.GET("/schemas", read, achService.GetSchemas)
+			ach.GET("/schemas/:type", read, achService.GetSchema)
+			ach.GET("/:id", read, achService.GetDetail)
+			ach.POST("", perms.RequirePermission("achievement", "create"), achService.Create)
+			ach.PUT("/:id", update, achService.Update)
+			ach.PATCH("/:id", update, achService.Patch)
+			ach.DELETE("/:id", perms.RequirePermission("achievement", "delete"), achService.Delete)
+			ach.POST("/:id/restore", perms.RequirePermission("achievement", "delete"), trashService.Restore)
+
+			ach.POST("/:id/submit", perms.RequirePermission("achievement", "submit"), achService.Submit)
+			ach.POST("/:id/verify", verify, achService.Verify)
+			ach.POST("/:id/reject", verify, achService.Reject)
+			ach.POST("/bulk-decision", verify, achService.BulkDecide)
+
+			ach.GET("/:id/history", read, achService.GetHistory)
+			ach.GET("/:id/members", read, achService.GetMembers)
+			ach.PUT("/:id/members", update, achService.UpdateMembers)
+			ach.DELETE("/:id/members/me", read, achService.LeaveTeam)
+			ach.PUT("/:id/duplicates/:otherId", verify, achService.DecideDuplicate)
+			ach.GET("/:id/attestation", read, achService.GetAttestation)
+			ach.POST("/:id/attestation", perms.RequirePermission("attestation", "manage"), attestationService.ReissueAttestation)
+			ach.GET("/:id/badge", read, badgeService.ExportBadge)
+			ach.GET("/:id/comments", read, achService.GetComments)
+			ach.POST("/:id/comments", read, achService.CreateComment)
+			ach.GET("/:id/revisions", read, achService.GetRevisions)
+			ach.GET("/:id/revisions/diff", read, achService.DiffRevisions)
+			ach.GET("/:id/revisions/:rev", read, achService.GetRevision)
+			ach.POST("/:id/attachments", update, achService.UploadAttachment)
+			ach.GET("/:id/attachments/:attId", read, achService.GetAttachment)
+			ach.DELETE("/:id/attachments/:attId", update, achService.DeleteAttachment)
 		}
 
 		mahasiswa := api.Group("/mahasiswa")
-		mahasiswa.Use(middleware.AuthMiddleware())
+		mahasiswa.Use(middleware.AuthMiddleware(authService), perms.RequirePermission("mahasiswa", "read"))
 		{
 			mahasiswa.GET("", mhsService.GetAll)
 			mahasiswa.GET("/:id", mhsService.GetDetail)
-			mahasiswa.GET("/:id/achievements", mhsService.GetAchievements)
-			mahasiswa.PUT("/:id/advisor", mhsService.UpdateAdvisor)
+			mahasiswa.GET("/:id/achievements", perms.RequirePermission("achievement", "read"), mhsService.GetAchievements)
+			mahasiswa.PUT("/:id/advisor", perms.RequirePermission("mahasiswa", "assign_advisor"), mhsService.UpdateAdvisor)
 		}
 		
 		dosen := api.Group("/dosen")
-		dosen.Use(middleware.AuthMiddleware())
+		dosen.Use(middleware.AuthMiddleware(authService), perms.RequirePermission("dosen", "read"))
 		{
 			dosen.GET("", dosenService.GetAll)
 			dosen.GET("/:id/advisees", dosenService.GetAdvisees)
 		}
 		
 		reports := api.Group("/reports")
-        reports.Use(middleware.AuthMiddleware())
+        reports.Use(middleware.AuthMiddleware(authService), perms.RequirePermission("report", "read"))
         {
             reports.GET("/statistics", reportService.GetGlobalStats)
             reports.GET("/student/:id", reportService.GetStudentReport)
+            reports.GET("/events", eventService.GetEventReport)
+            reports.GET("/student/:id/skpi", skpiService.GetSkpiVersions)
+            reports.POST("/student/:id/skpi", perms.RequirePermission("skpi", "generate"), skpiService.GenerateSkpi)
+            reports.GET("/student/:id/skpi/:version", skpiService.DownloadSkpi)
         }
+
+		// Verifikasi atestasi terbuka untuk pihak ketiga tanpa token; hanya pencabutan yang butuh login
+		attestations := api.Group("/attestations")
+		{
+			attestations.GET("/public-key", attestationService.GetPublicKey)
+			attestations.GET("/verify", attestationService.VerifyAttestation)
+			attestations.POST("/:id/revoke", middleware.AuthMiddleware(authService), perms.RequirePermission("attestation", "manage"), attestationService.RevokeAttestation)
+		}
+
+		// Endpoint hosted Open Badges harus bisa diakses verifier tanpa token
+		badges := api.Group("/badges")
+		{
+			badges.GET("/issuer", badgeService.GetIssuer)
+			badges.GET("/issuer/keys/:kid", badgeService.GetIssuerKey)
+			badges.GET("/classes/:id", badgeService.GetBadgeClass)
+			badges.GET("/classes/:id/image", badgeService.GetBadgeImage)
+			badges.GET("/assertions/:id", badgeService.GetAssertion)
+		}
+
+		api.GET("/public/portfolios/:slug", portfolioService.GetPublicPortfolio)
+
+		portfolio := api.Group("/portfolio")
+		portfolio.Use(middleware.AuthMiddleware(authService), perms.RequirePermission("portfolio", "manage"))
+		{
+			portfolio.GET("", portfolioService.GetMyPortfolio)
+			portfolio.PUT("", portfolioService.UpdateMyPortfolio)
+			portfolio.POST("/slug", portfolioService.RotatePortfolioSlug)
+			portfolio.PUT("/items/:id", portfolioService.UpdatePortfolioItem)
+		}
+
+		events := api.Group("/events")
+		events.Use(middleware.AuthMiddleware(authService))
+		{
+			// Katalog event dibaca saat mengisi atau meninjau prestasi
+			events.GET("", perms.RequirePermission("achievement", "read"), eventService.GetEvents)
+			events.GET("/:id", perms.RequirePermission("achievement", "read"), eventService.GetEvent)
+			events.POST("", perms.RequirePermission("event", "manage"), eventService.CreateEvent)
+			events.PUT("/:id", perms.RequirePermission("event", "manage"), eventService.UpdateEvent)
+			events.DELETE("/:id", perms.RequirePermission("event", "manage"), eventService.DeleteEvent)
+		}
+
+		pointRules := api.Group("/point-rules")
+		pointRules.Use(middleware.AuthMiddleware(authService), perms.RequirePermission("point_rule", "manage"))
+		{
+			pointRules.GET("", pointService.GetRules)
+			pointRules.POST("", pointService.CreateRule)
+			pointRules.POST("/recalculate", pointService.Recalculate)
+			pointRules.PUT("/:id", pointService.UpdateRule)
+			pointRules.DELETE("/:id", pointService.DeleteRule)
+		}
+
+		admin := api.Group("/admin")
+		admin.Use(middleware.AuthMiddleware(authService), perms.RequirePermission("system", "maintain"))
+		{
+			admin.POST("/reconcile", reconcileService.Reconcile)
+			admin.GET("/orphan-refs", reconcileService.GetOrphanRefs)
+			admin.DELETE("/orphan-refs/:id", reconcileService.DeleteOrphanRef)
+			admin.POST("/purge-trash", trashService.Purge)
+		}
 	}
 }